@@ -120,10 +120,11 @@ func extractFlagsAndCommands(args []string) ([]string, []string) {
 		"-s": "--status",
 		"-S": "--source",
 		"-r": "--repo",
+		"-g": "--group",
 	}
 
 	// Flags that require a value (long form prefix)
-	valueFlagsLong := []string{"--limit", "--pager", "--status", "--source", "--since", "--until", "--repo", "--root", "--depth", "--branch", "--tag"}
+	valueFlagsLong := []string{"--limit", "--pager", "--status", "--source", "--older-than", "--since", "--until", "--repo", "--root", "--depth", "--branch", "--tag", "--device", "--group", "--gap", "--from", "--to", "--by", "--round", "--min-block", "--group-by", "--year", "--where", "--during"}
 
 	i := 0
 	for i < len(args) {
@@ -132,6 +132,42 @@ func TestExtractFlagsAndCommands(t *testing.T) {
 			wantFlags:    []string{"-e"},
 			wantCommands: []string{},
 		},
+		{
+			name:         "--gap with space-separated value",
+			args:         []string{"sessions", "--gap", "45m"},
+			wantFlags:    []string{"--gap=45m"},
+			wantCommands: []string{"sessions"},
+		},
+		{
+			name:         "timesheet flags with space-separated values",
+			args:         []string{"report", "timesheet", "--from", "2025-03-01", "--to", "2025-03-31", "--by", "client", "--round", "15m", "--min-block", "30m"},
+			wantFlags:    []string{"--from=2025-03-01", "--to=2025-03-31", "--by=client", "--round=15m", "--min-block=30m"},
+			wantCommands: []string{"report", "timesheet"},
+		},
+		{
+			name:         "--group-by with space-separated value",
+			args:         []string{"stats", "--group-by", "weekday"},
+			wantFlags:    []string{"--group-by=weekday"},
+			wantCommands: []string{"stats"},
+		},
+		{
+			name:         "--year with space-separated value",
+			args:         []string{"heatmap", "--year", "2025"},
+			wantFlags:    []string{"--year=2025"},
+			wantCommands: []string{"heatmap"},
+		},
+		{
+			name:         "--where with space-separated value",
+			args:         []string{"activity", "--where", "branch = main"},
+			wantFlags:    []string{"--where=branch = main"},
+			wantCommands: []string{"activity"},
+		},
+		{
+			name:         "--during with space-separated value",
+			args:         []string{"stats", "--during", "last week"},
+			wantFlags:    []string{"--during=last week"},
+			wantCommands: []string{"stats"},
+		},
 		{
 			name:         "complex real-world example",
 			args:         []string{"activity", "-5", "--oneline", "--status", "pending"},
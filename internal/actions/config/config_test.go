@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -91,6 +92,34 @@ func TestSet_AddNew(t *testing.T) {
 	require.Equal(t, "theme=dark", writtenLines[0])
 }
 
+func TestSet_ArrayKeyAppends(t *testing.T) {
+	var capturedPrintf string
+	var writtenLines []string
+	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return []string{"remote_rewrite[]=gh: github.com/"}, nil
+		},
+		AppendArray: func(lines []string, arrayKey, value string) ([]string, bool) {
+			return append(lines, arrayKey+"[]="+value), true
+		},
+		WriteLines: func(lines []string) error {
+			writtenLines = lines
+			return nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			capturedPrintf = fmt.Sprintf(format, a...)
+			return 0, nil
+		},
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{})
+	err := set([]string{"remote_rewrite[]", "gl: gitlab.com/"}, flags, deps)
+
+	require.NoError(t, err)
+	require.Equal(t, "added remote_rewrite[]=gl: gitlab.com/\n", capturedPrintf)
+	require.Equal(t, []string{"remote_rewrite[]=gh: github.com/", "remote_rewrite[]=gl: gitlab.com/"}, writtenLines)
+}
+
 func TestSet_UpdateExisting(t *testing.T) {
 	var capturedPrintf string
 	deps := Deps{
@@ -197,6 +226,34 @@ func TestUnset_Success(t *testing.T) {
 	require.Contains(t, capturedPrintf, "unset")
 }
 
+func TestUnset_ArrayValue(t *testing.T) {
+	var capturedPrintf string
+	var removedValue string
+	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return []string{"remote_rewrite[]=gh: github.com/"}, nil
+		},
+		RemoveFromArray: func(lines []string, arrayKey, value string) ([]string, bool) {
+			removedValue = value
+			return nil, true
+		},
+		WriteLines: func(lines []string) error {
+			return nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			capturedPrintf = fmt.Sprintf(format, a...)
+			return 0, nil
+		},
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{})
+	err := unset([]string{"remote_rewrite[]", "gh: github.com/"}, flags, deps)
+
+	require.NoError(t, err)
+	require.Equal(t, "gh: github.com/", removedValue)
+	require.Equal(t, "unset remote_rewrite[]=gh: github.com/\n", capturedPrintf)
+}
+
 func TestUnset_KeyNotFound(t *testing.T) {
 	deps := Deps{
 		ReadLines: func() ([]string, error) {
@@ -310,6 +367,9 @@ func TestUnset_WriteLinesError(t *testing.T) {
 func TestList_Success(t *testing.T) {
 	var printedLines []string
 	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return nil, nil
+		},
 		GetAll: func() (map[string]string, error) {
 			return map[string]string{
 				"theme":     "neon",
@@ -327,12 +387,15 @@ func TestList_Success(t *testing.T) {
 
 	require.NoError(t, err)
 	// Should show visible keys (HideIfEmpty keys are hidden when not set)
-	require.Len(t, printedLines, 8) // 8 always-visible keys
+	require.Len(t, printedLines, 17) // 17 always-visible keys
 }
 
 func TestList_ShowsDefaults(t *testing.T) {
 	var printedLines []string
 	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return nil, nil
+		},
 		GetAll: func() (map[string]string, error) {
 			return map[string]string{}, nil
 		},
@@ -347,12 +410,15 @@ func TestList_ShowsDefaults(t *testing.T) {
 
 	require.NoError(t, err)
 	// Should show visible keys with defaults (HideIfEmpty keys are hidden)
-	require.Len(t, printedLines, 8)
+	require.Len(t, printedLines, 17)
 }
 
 func TestList_ShowsColorOverridesWhenSet(t *testing.T) {
 	var printedLines []string
 	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return nil, nil
+		},
 		GetAll: func() (map[string]string, error) {
 			return map[string]string{
 				"color_success": "46",
@@ -369,8 +435,35 @@ func TestList_ShowsColorOverridesWhenSet(t *testing.T) {
 	err := list([]string{}, flags, deps)
 
 	require.NoError(t, err)
-	// 8 always-visible + 2 color overrides that are set
-	require.Len(t, printedLines, 10)
+	// 17 always-visible + 2 color overrides that are set
+	require.Len(t, printedLines, 19)
+}
+
+func TestList_ShowsArrayValues(t *testing.T) {
+	var printedLines []string
+	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return []string{
+				"remote_rewrite[]=gh: github.com/",
+				"remote_rewrite[]=git.acme.internal/mirrors/ github.com/acme/",
+			}, nil
+		},
+		GetAll: func() (map[string]string, error) {
+			return map[string]string{}, nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printedLines = append(printedLines, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{})
+	err := list([]string{}, flags, deps)
+
+	require.NoError(t, err)
+	// 17 always-visible + one line per rewrite rule
+	require.Len(t, printedLines, 19)
+	require.Contains(t, strings.Join(printedLines, ""), "remote_rewrite[]=git.acme.internal/mirrors/ github.com/acme/")
 }
 
 func TestList_GetAllError(t *testing.T) {
@@ -390,6 +483,9 @@ func TestList_GetAllError(t *testing.T) {
 func TestList_JSON(t *testing.T) {
 	var printedOutput string
 	deps := Deps{
+		ReadLines: func() ([]string, error) {
+			return nil, nil
+		},
 		GetAll: func() (map[string]string, error) {
 			return map[string]string{
 				"theme": "neon",
@@ -12,22 +12,29 @@ type Deps struct {
 	Parse      func([]string) (map[string]string, error)
 	Set        func([]string, string, string) ([]string, bool)
 	Unset      func([]string, string) ([]string, bool)
-	Get        func(string) (string, bool)
-	GetAll     func() (map[string]string, error)
-	Printf     func(string, ...any) (int, error)
-	Println    func(...any) (int, error)
+	// Array keys (key[]=value)
+	AppendArray     func([]string, string, string) ([]string, bool)
+	RemoveFromArray func([]string, string, string) ([]string, bool)
+	UnsetArray      func([]string, string) ([]string, bool)
+	Get             func(string) (string, bool)
+	GetAll          func() (map[string]string, error)
+	Printf          func(string, ...any) (int, error)
+	Println         func(...any) (int, error)
 }
 
 func DefaultDeps() Deps {
 	return Deps{
-		ReadLines:  config.ReadLines,
-		WriteLines: config.WriteLines,
-		Parse:      config.Parse,
-		Set:        config.Set,
-		Unset:      config.Unset,
-		Get:        config.Get,
-		GetAll:     config.GetAll,
-		Printf:     fmt.Printf,
-		Println:    fmt.Println,
+		ReadLines:       config.ReadLines,
+		WriteLines:      config.WriteLines,
+		Parse:           config.Parse,
+		Set:             config.Set,
+		Unset:           config.Unset,
+		AppendArray:     config.AppendArray,
+		RemoveFromArray: config.RemoveFromArray,
+		UnsetArray:      config.UnsetArray,
+		Get:             config.Get,
+		GetAll:          config.GetAll,
+		Printf:          fmt.Printf,
+		Println:         fmt.Println,
 	}
 }
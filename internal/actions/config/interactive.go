@@ -33,7 +33,13 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
-	keys := domain.VisibleConfigKeys()
+	// Array keys hold several values; they are edited with fp config set/unset
+	var keys []domain.ConfigKey
+	for _, key := range domain.VisibleConfigKeys() {
+		if !key.IsArray() {
+			keys = append(keys, key)
+		}
+	}
 
 	m := configModel{
 		keys:         keys,
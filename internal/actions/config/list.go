@@ -1,6 +1,9 @@
 package config
 
 import (
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/domain"
 	"github.com/footprint-tools/cli/internal/output"
@@ -19,12 +22,24 @@ func list(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
+	arrays, err := readArrays(deps)
+	if err != nil {
+		return err
+	}
+
 	if jsonOutput {
-		return listJSON(configMap, deps)
+		return listJSON(configMap, arrays, deps)
 	}
 
 	// Show all visible keys with their values (or defaults if not set)
 	for _, key := range domain.VisibleConfigKeys() {
+		if key.IsArray() {
+			for _, value := range arrays[key.Name] {
+				_, _ = deps.Printf("%s=%s\n", style.Info(key.Name), value)
+			}
+			continue
+		}
+
 		value, exists := configMap[key.Name]
 		hasValue := exists && value != ""
 
@@ -46,7 +61,24 @@ func list(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	return nil
 }
 
-func listJSON(configMap map[string]string, deps Deps) error {
+// readArrays returns the values of the visible array keys, which the
+// key=value map leaves out.
+func readArrays(deps Deps) (map[string][]string, error) {
+	lines, err := deps.ReadLines()
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := make(map[string][]string)
+	for _, key := range domain.VisibleConfigKeys() {
+		if key.IsArray() {
+			arrays[key.Name] = config.ParseArray(lines, strings.TrimSuffix(key.Name, "[]"))
+		}
+	}
+	return arrays, nil
+}
+
+func listJSON(configMap map[string]string, arrays map[string][]string, deps Deps) error {
 	type configEntry struct {
 		Key     string `json:"key"`
 		Value   string `json:"value"`
@@ -57,6 +89,13 @@ func listJSON(configMap map[string]string, deps Deps) error {
 	keys := domain.VisibleConfigKeys()
 	entries := make([]configEntry, 0, len(keys))
 	for _, key := range keys {
+		if key.IsArray() {
+			for _, value := range arrays[key.Name] {
+				entries = append(entries, configEntry{Key: key.Name, Value: value, IsSet: true})
+			}
+			continue
+		}
+
 		value, exists := configMap[key.Name]
 		hasValue := exists && value != ""
 
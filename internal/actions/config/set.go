@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/footprint-tools/cli/internal/actions/tracking"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/domain"
@@ -29,6 +31,20 @@ func set(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
+	// Array keys collect values instead of replacing them
+	if arrayKey, ok := strings.CutSuffix(key, "[]"); ok {
+		lines, added := deps.AppendArray(lines, arrayKey, value)
+		if !added {
+			_, _ = deps.Printf("already set %s=%s\n", key, value)
+			return nil
+		}
+		if err := deps.WriteLines(lines); err != nil {
+			return err
+		}
+		_, _ = deps.Printf("added %s=%s\n", key, value)
+		return nil
+	}
+
 	lines, updated := deps.Set(lines, key, value)
 
 	if err := deps.WriteLines(lines); err != nil {
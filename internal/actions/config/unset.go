@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/usage"
 )
@@ -34,7 +36,18 @@ func unset(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
-	lines, removed := deps.Unset(lines, key)
+	var removed bool
+	arrayKey, isArray := strings.CutSuffix(key, "[]")
+	switch {
+	case isArray && len(args) > 1:
+		// Remove a single value of an array key
+		lines, removed = deps.RemoveFromArray(lines, arrayKey, args[1])
+		key += "=" + args[1]
+	case isArray:
+		lines, removed = deps.UnsetArray(lines, arrayKey)
+	default:
+		lines, removed = deps.Unset(lines, key)
+	}
 	if !removed {
 		return usage.InvalidConfigKey(key)
 	}
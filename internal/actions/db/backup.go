@@ -0,0 +1,68 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// Backup writes a consistent copy of the database to a file.
+func Backup(args []string, flags *dispatchers.ParsedFlags) error {
+	return backup(args, flags, DefaultDeps())
+}
+
+func backup(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("file")
+	}
+	dest := args[0]
+
+	if _, err := deps.Stat(dest); err == nil {
+		if !flags.Has("--force") {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", dest)
+		}
+		if err := deps.Remove(dest); err != nil {
+			return fmt.Errorf("remove existing %s: %w", dest, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("stat %s: %w", dest, err)
+	}
+
+	dbPath := deps.DBPath()
+	s, err := deps.OpenStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if err := s.Backup(dest); err != nil {
+		return err
+	}
+
+	_, _ = deps.Printf("Backed up %s to %s (%s)\n", dbPath, dest, fileSize(deps, dest))
+	return nil
+}
+
+// fileSize returns the size of a file in human units, or "unknown size".
+func fileSize(deps Deps, path string) string {
+	info, err := deps.Stat(path)
+	if err != nil {
+		return "unknown size"
+	}
+	return formatBytes(info.Size())
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store/migrations"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// Check runs SQLite's integrity and foreign key checks on the database.
+func Check(args []string, flags *dispatchers.ParsedFlags) error {
+	return check(args, flags, DefaultDeps())
+}
+
+func check(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	dbPath := deps.DBPath()
+	s, err := deps.OpenStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = s.Close() }()
+
+	version, err := migrations.CurrentVersion(s.DB())
+	if err != nil {
+		return err
+	}
+
+	problems, err := s.CheckIntegrity()
+	if err != nil {
+		return err
+	}
+
+	if flags.Has("--json") {
+		if problems == nil {
+			problems = []string{}
+		}
+		if err := output.JSON(deps.Println, map[string]any{
+			"path":           dbPath,
+			"schema_version": version,
+			"ok":             len(problems) == 0,
+			"problems":       problems,
+		}); err != nil {
+			return err
+		}
+	} else {
+		_, _ = deps.Printf("%s %s\n", style.Header(dbPath), style.Muted(fmt.Sprintf("(schema version %d)", version)))
+		if len(problems) == 0 {
+			_, _ = deps.Println(style.Success("ok: integrity and foreign key checks passed"))
+			return nil
+		}
+		for _, p := range problems {
+			_, _ = deps.Println(style.Error("  " + p))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("database check found %d problem(s); restore a backup with 'fp db restore'", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// testDatabase creates a database file holding one event per commit.
+// The actions close the store they open, so tests reopen the file to inspect it.
+func testDatabase(t *testing.T, commits ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "store.db")
+	s, err := store.New(path)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	for _, commit := range commits {
+		insertEvent(t, s, commit)
+	}
+	return path
+}
+
+func insertEvent(t *testing.T, s *store.Store, commit string) {
+	t.Helper()
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/repo",
+		Commit:    commit,
+		Branch:    "main",
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Status:    store.StatusPending,
+		Source:    store.SourcePostCommit,
+	}))
+}
+
+func commitsIn(t *testing.T, path string) []string {
+	t.Helper()
+	s, err := store.New(path)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	var commits []string
+	for _, e := range events {
+		commits = append(commits, e.Commit)
+	}
+	return commits
+}
+
+func testDeps(path string, out *[]string) Deps {
+	deps := DefaultDeps()
+	deps.DBPath = func() string { return path }
+	deps.Println = func(a ...any) (int, error) {
+		*out = append(*out, fmt.Sprint(a...))
+		return 0, nil
+	}
+	deps.Printf = func(f string, a ...any) (int, error) {
+		*out = append(*out, fmt.Sprintf(f, a...))
+		return 0, nil
+	}
+	return deps
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	path := testDatabase(t, "aaa111")
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	var out []string
+	deps := testDeps(path, &out)
+
+	require.NoError(t, backup([]string{backupPath}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"aaa111"}, commitsIn(t, backupPath))
+
+	s, err := store.New(path)
+	require.NoError(t, err)
+	insertEvent(t, s, "bbb222")
+	require.NoError(t, s.Close())
+
+	require.NoError(t, restore([]string{backupPath}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"aaa111"}, commitsIn(t, path))
+
+	// The database as it was before restoring is kept
+	require.ElementsMatch(t, []string{"aaa111", "bbb222"}, commitsIn(t, path+".before-restore"))
+}
+
+func TestBackup_ExistingFile(t *testing.T) {
+	path := testDatabase(t, "aaa111")
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, os.WriteFile(backupPath, []byte("keep me"), 0600))
+	var out []string
+	deps := testDeps(path, &out)
+
+	err := backup([]string{backupPath}, dispatchers.NewParsedFlags(nil), deps)
+	require.ErrorContains(t, err, "--force")
+
+	require.NoError(t, backup([]string{backupPath}, dispatchers.NewParsedFlags([]string{"--force"}), deps))
+	require.Equal(t, []string{"aaa111"}, commitsIn(t, backupPath))
+}
+
+func TestRestore_RejectsNonDatabase(t *testing.T) {
+	path := testDatabase(t, "aaa111")
+	notDB := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(notDB, []byte("hello"), 0600))
+	var out []string
+
+	require.Error(t, restore([]string{notDB}, dispatchers.NewParsedFlags(nil), testDeps(path, &out)))
+	require.Equal(t, []string{"aaa111"}, commitsIn(t, path))
+}
+
+func TestCheck_JSON(t *testing.T) {
+	path := testDatabase(t, "aaa111")
+	var out []string
+
+	require.NoError(t, check(nil, dispatchers.NewParsedFlags([]string{"--json"}), testDeps(path, &out)))
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &result))
+	require.Equal(t, true, result["ok"])
+	require.Equal(t, []any{}, result["problems"])
+}
+
+func TestVacuum(t *testing.T) {
+	path := testDatabase(t, "aaa111")
+	var out []string
+
+	require.NoError(t, vacuum(nil, dispatchers.NewParsedFlags(nil), testDeps(path, &out)))
+	require.Len(t, out, 1)
+	require.Contains(t, out[0], "Compacted")
+}
+
+func TestFormatBytes(t *testing.T) {
+	require.Equal(t, "512 B", formatBytes(512))
+	require.Equal(t, "1.5 KB", formatBytes(1536))
+	require.Equal(t, "2.0 MB", formatBytes(2*1024*1024))
+}
+
+func TestMigrations_JSON(t *testing.T) {
+	path := testDatabase(t)
+	var out []string
+
+	require.NoError(t, listMigrations(nil, dispatchers.NewParsedFlags([]string{"--json"}), testDeps(path, &out)))
+
+	var result struct {
+		Current    int            `json:"current"`
+		Supported  int            `json:"supported"`
+		Migrations []migrationRow `json:"migrations"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &result))
+	require.Equal(t, result.Supported, result.Current)
+	require.Len(t, result.Migrations, result.Supported)
+	for _, m := range result.Migrations {
+		require.True(t, m.Applied)
+		require.NotEmpty(t, m.AppliedAt)
+	}
+}
+
+func TestMigrations_NewerDatabase(t *testing.T) {
+	path := testDatabase(t)
+	s, err := store.New(path)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`INSERT INTO schema_migrations (version, description) VALUES (999, 'future')`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// Normal commands refuse the database
+	_, err = store.New(path)
+	require.ErrorContains(t, err, "newer than this fp supports")
+
+	var out []string
+	require.NoError(t, listMigrations(nil, dispatchers.NewParsedFlags([]string{"--json"}), testDeps(path, &out)))
+
+	var result struct {
+		Current    int            `json:"current"`
+		Migrations []migrationRow `json:"migrations"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &result))
+	require.Equal(t, 999, result.Current)
+	last := result.Migrations[len(result.Migrations)-1]
+	require.Equal(t, 999, last.Version)
+	require.False(t, last.Known)
+}
+
+func TestDowngrade(t *testing.T) {
+	path := testDatabase(t, "aaa111")
+	var out []string
+	deps := testDeps(path, &out)
+
+	require.NoError(t, downgrade([]string{"10"}, dispatchers.NewParsedFlags(nil), deps))
+	require.Contains(t, out[0], "to 10")
+	_, err := os.Stat(path + ".before-downgrade")
+	require.NoError(t, err)
+
+	// Opening with this fp migrates forward again and keeps the events
+	require.Equal(t, []string{"aaa111"}, commitsIn(t, path))
+
+	require.ErrorContains(t, downgrade([]string{"latest"}, dispatchers.NewParsedFlags(nil), deps), "invalid version")
+}
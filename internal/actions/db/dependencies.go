@@ -0,0 +1,31 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+type Deps struct {
+	DBPath    func() string
+	OpenStore func(string) (*store.Store, error)
+	// OpenUnmigrated opens the database without migrating it
+	OpenUnmigrated func(string) (*store.Store, error)
+	Stat           func(string) (os.FileInfo, error)
+	Remove         func(string) error
+	Printf         func(string, ...any) (int, error)
+	Println        func(...any) (int, error)
+}
+
+func DefaultDeps() Deps {
+	return Deps{
+		DBPath:         store.DBPath,
+		OpenStore:      store.New,
+		OpenUnmigrated: store.NewUnmigrated,
+		Stat:           os.Stat,
+		Remove:         os.Remove,
+		Printf:         fmt.Printf,
+		Println:        fmt.Println,
+	}
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store/migrations"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// Downgrade reverts the database schema to an older version so an older fp
+// can use it.
+func Downgrade(args []string, flags *dispatchers.ParsedFlags) error {
+	return downgrade(args, flags, DefaultDeps())
+}
+
+func downgrade(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("version")
+	}
+	target, err := strconv.Atoi(args[0])
+	if err != nil || target < 1 {
+		return fmt.Errorf("invalid version '%s': expected a schema version number (see 'fp db migrations')", args[0])
+	}
+
+	dbPath := deps.DBPath()
+	s, err := deps.OpenUnmigrated(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = s.Close() }()
+
+	current, err := migrations.CurrentVersion(s.DB())
+	if err != nil {
+		return err
+	}
+	if current <= target {
+		_, _ = deps.Printf("Schema is already at version %d\n", current)
+		return nil
+	}
+
+	if err := s.Downgrade(target); err != nil {
+		return err
+	}
+
+	_, _ = deps.Printf("Downgraded schema from version %d to %d\n", current, target)
+	_, _ = deps.Printf("Previous database saved to %s.before-downgrade\n", dbPath)
+	return nil
+}
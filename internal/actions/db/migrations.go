@@ -0,0 +1,119 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store/migrations"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// migrationRow is one line of 'fp db migrations'.
+type migrationRow struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Applied     bool   `json:"applied"`
+	AppliedAt   string `json:"applied_at,omitempty"`
+	Reversible  bool   `json:"reversible"`
+	Known       bool   `json:"known"`
+}
+
+// Migrations lists applied and pending schema migrations.
+func Migrations(args []string, flags *dispatchers.ParsedFlags) error {
+	return listMigrations(args, flags, DefaultDeps())
+}
+
+func listMigrations(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	dbPath := deps.DBPath()
+	s, err := deps.OpenUnmigrated(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = s.Close() }()
+
+	known, err := migrations.Load()
+	if err != nil {
+		return err
+	}
+	applied, err := migrations.Applied(s.DB())
+	if err != nil {
+		return err
+	}
+	current, err := migrations.CurrentVersion(s.DB())
+	if err != nil {
+		return err
+	}
+	supported, err := migrations.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	rows := migrationRows(known, applied)
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, map[string]any{
+			"current":    current,
+			"supported":  supported,
+			"migrations": rows,
+		})
+	}
+
+	_, _ = deps.Printf("%s %s\n", style.Header("Schema version"), fmt.Sprintf("%d (this fp supports up to %d)", current, supported))
+	if current > supported {
+		_, _ = deps.Println(style.Warning("The database is newer than this fp; update fp before using it."))
+	}
+	_, _ = deps.Println()
+
+	for _, r := range rows {
+		name := fmt.Sprintf("%02d_%s", r.Version, r.Description)
+		switch {
+		case !r.Known:
+			_, _ = deps.Printf("  %s  %s  %s\n", style.Warning("unknown "), name, style.Muted(r.AppliedAt))
+		case r.Applied:
+			_, _ = deps.Printf("  %s  %s  %s\n", style.Success("applied "), name, style.Muted(r.AppliedAt))
+		default:
+			_, _ = deps.Printf("  %s  %s\n", style.Muted("pending "), name)
+		}
+	}
+	return nil
+}
+
+// migrationRows merges the migrations embedded in this binary with the ones
+// recorded in the database, in version order.
+func migrationRows(known []migrations.Migration, applied []migrations.AppliedMigration) []migrationRow {
+	appliedAt := make(map[int]string, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	rows := make([]migrationRow, 0, len(known))
+	for _, m := range known {
+		at, ok := appliedAt[m.Version]
+		rows = append(rows, migrationRow{
+			Version:     m.Version,
+			Description: m.Description,
+			Applied:     ok,
+			AppliedAt:   at,
+			Reversible:  m.Down != "",
+			Known:       true,
+		})
+	}
+
+	// Versions applied by a newer fp come last
+	last := 0
+	if len(known) > 0 {
+		last = known[len(known)-1].Version
+	}
+	for _, a := range applied {
+		if a.Version > last {
+			rows = append(rows, migrationRow{
+				Version:     a.Version,
+				Description: a.Description,
+				Applied:     true,
+				AppliedAt:   a.AppliedAt,
+			})
+		}
+	}
+	return rows
+}
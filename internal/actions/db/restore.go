@@ -0,0 +1,49 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// Restore replaces the database with a backup made by 'fp db backup'.
+func Restore(args []string, flags *dispatchers.ParsedFlags) error {
+	return restore(args, flags, DefaultDeps())
+}
+
+func restore(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("file")
+	}
+	src := args[0]
+
+	if _, err := deps.Stat(src); err != nil {
+		return fmt.Errorf("cannot read backup %s: %w", src, err)
+	}
+
+	dbPath := deps.DBPath()
+	s, err := deps.OpenStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = s.Close() }()
+
+	// Keep the current contents so a wrong restore can be undone
+	previous := dbPath + ".before-restore"
+	if err := deps.Remove(previous); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove old %s: %w", previous, err)
+	}
+	if err := s.Backup(previous); err != nil {
+		return fmt.Errorf("save current database before restoring: %w", err)
+	}
+
+	if err := s.RestoreFrom(src); err != nil {
+		return err
+	}
+
+	_, _ = deps.Printf("Restored %s from %s\n", dbPath, src)
+	_, _ = deps.Printf("Previous database saved to %s\n", previous)
+	return nil
+}
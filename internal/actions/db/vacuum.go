@@ -0,0 +1,41 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+// Vacuum compacts the database file.
+func Vacuum(args []string, flags *dispatchers.ParsedFlags) error {
+	return vacuum(args, flags, DefaultDeps())
+}
+
+func vacuum(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	dbPath := deps.DBPath()
+	s, err := deps.OpenStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = s.Close() }()
+
+	before := databaseSize(deps, dbPath)
+	if err := s.Compact(); err != nil {
+		return err
+	}
+	after := databaseSize(deps, dbPath)
+
+	_, _ = deps.Printf("Compacted %s: %s -> %s\n", dbPath, formatBytes(before), formatBytes(after))
+	return nil
+}
+
+// databaseSize returns the size of the database file plus its write-ahead log.
+func databaseSize(deps Deps, dbPath string) int64 {
+	var total int64
+	for _, path := range []string{dbPath, dbPath + "-wal"} {
+		if info, err := deps.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -34,6 +34,10 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	oneline := flags.Has("--oneline")
 	jsonOutput := flags.Has("--json")
 	enrich := flags.Has("--enrich")
+	showRewrites := flags.Has("--show-rewrites")
+
+	// Commits replaced by amend/rebase are collapsed unless asked for
+	filter.ExcludeSuperseded = !showRewrites
 
 	if statusStr := flags.String("--status", ""); statusStr != "" {
 		status, ok := parseStatus(statusStr)
@@ -51,24 +55,33 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		filter.Source = &source
 	}
 
-	if sinceStr := flags.String("--since", ""); sinceStr != "" {
-		since := flags.Date("--since")
-		if since == nil {
-			return fmt.Errorf("invalid date '%s' for --since: expected format YYYY-MM-DD", sinceStr)
-		}
-		filter.Since = since
+	since, until, err := dateRange(flags, deps.Now)
+	if err != nil {
+		return err
 	}
+	filter.Since, filter.Until = since, until
 
-	if untilStr := flags.String("--until", ""); untilStr != "" {
-		until := flags.Date("--until")
-		if until == nil {
-			return fmt.Errorf("invalid date '%s' for --until: expected format YYYY-MM-DD", untilStr)
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
 		}
-		filter.Until = until
 	}
 
-	if repoID := flags.String("--repo", ""); repoID != "" {
-		filter.RepoID = &repoID
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	if tagStr := flags.String("--tag", ""); tagStr != "" {
+		tag, err := normalizeTag(tagStr)
+		if err != nil {
+			return err
+		}
+		filter.Tag = &tag
 	}
 
 	// Validate and parse limit flag
@@ -96,19 +109,46 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
+	var rewrites map[int64][]store.CommitRewrite
+	if showRewrites {
+		rewrites, err = store.ListRewritesForEvents(db, eventIDsBySource(events, store.SourcePostRewrite))
+		if err != nil {
+			return fmt.Errorf("failed to list rewrites: %w", err)
+		}
+	}
+
+	pushRefs, err := store.ListPushRefsForEvents(db, eventIDsBySource(events, store.SourcePrePush))
+	if err != nil {
+		return fmt.Errorf("failed to list pushed refs: %w", err)
+	}
+
+	annotations := loadAnnotations(db, events)
+	aliases := loadRepoAliases(db)
+
+	// Enrichment reads metadata snapshots first and falls back to git
+	var snapshots map[string]git.CommitMetadata
+	if enrich {
+		snapshots = loadMetadataSnapshots(db, events)
+	}
+
 	if jsonOutput {
-		return outputEventsJSON(events, enrich, deps)
+		return outputEventsJSON(events, enrich, snapshots, rewrites, pushRefs, annotations, aliases, deps)
 	}
 
 	var output bytes.Buffer
 
 	for _, event := range events {
 		if enrich {
-			meta := git.GetCommitMetadata(event.RepoPath, event.Commit)
-			output.WriteString(formatEventEnriched(event, meta, oneline))
+			meta := commitMetadataFor(event, snapshots)
+			output.WriteString(formatEventEnriched(event, meta, aliases, oneline))
 		} else {
-			output.WriteString(formatEvent(event, oneline))
+			output.WriteString(formatEvent(event, aliases, oneline))
+		}
+		if showRewrites {
+			output.WriteString(formatRewrites(event, rewrites[event.ID], oneline))
 		}
+		output.WriteString(formatPushRefs(pushRefs[event.ID], oneline))
+		output.WriteString(formatAnnotations(annotations[event.ID], oneline))
 		output.WriteString("\n")
 	}
 
@@ -116,34 +156,113 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	return nil
 }
 
-func outputEventsJSON(events []store.RepoEvent, enrich bool, deps Deps) error {
+// eventIDsBySource returns the IDs of events from one source, used to load
+// per-event details (rewrite pairs for post-rewrite, refs for pre-push).
+func eventIDsBySource(events []store.RepoEvent, source store.Source) []int64 {
+	var ids []int64
+	for _, e := range events {
+		if e.Source == source {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// eventIDs returns the IDs of the events, used to load their annotations.
+func eventIDs(events []store.RepoEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func outputEventsJSON(events []store.RepoEvent, enrich bool, snapshots map[string]git.CommitMetadata, rewrites map[int64][]store.CommitRewrite, pushRefs map[int64][]store.PushRef, annotations map[int64]store.Annotations, aliases repoAliases, deps Deps) error {
+	type jsonRewrite struct {
+		Type      string `json:"type"`
+		OldCommit string `json:"old_commit"`
+		NewCommit string `json:"new_commit"`
+	}
+
+	type jsonPushRef struct {
+		Remote      string `json:"remote"`
+		RemoteURL   string `json:"remote_url,omitempty"`
+		LocalRef    string `json:"local_ref"`
+		LocalSHA    string `json:"local_sha"`
+		RemoteRef   string `json:"remote_ref"`
+		RemoteSHA   string `json:"remote_sha"`
+		CommitCount int    `json:"commit_count"`
+	}
+
 	type jsonEvent struct {
 		ID        int64  `json:"id"`
 		RepoID    string `json:"repo_id"`
+		RepoAlias string `json:"repo_alias,omitempty"`
 		RepoPath  string `json:"repo_path"`
 		Commit    string `json:"commit"`
 		Branch    string `json:"branch"`
 		Timestamp string `json:"timestamp"`
 		Status    string `json:"status"`
 		Source    string `json:"source"`
+		Device    string `json:"device,omitempty"`
 		Author    string `json:"author,omitempty"`
 		Message   string `json:"message,omitempty"`
+
+		PreviousCommit string `json:"previous_commit,omitempty"`
+		PreviousBranch string `json:"previous_branch,omitempty"`
+		BranchSwitch   *bool  `json:"branch_switch,omitempty"`
+
+		SupersededBy string        `json:"superseded_by,omitempty"`
+		Rewrites     []jsonRewrite `json:"rewrites,omitempty"`
+		Pushes       []jsonPushRef `json:"pushes,omitempty"`
+
+		Tags []string `json:"tags,omitempty"`
+		Note string   `json:"note,omitempty"`
 	}
 
 	out := make([]jsonEvent, 0, len(events))
 	for _, e := range events {
 		je := jsonEvent{
-			ID:        e.ID,
-			RepoID:    e.RepoID,
-			RepoPath:  e.RepoPath,
-			Commit:    e.Commit,
-			Branch:    e.Branch,
-			Timestamp: e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
-			Status:    e.Status.String(),
-			Source:    e.Source.String(),
+			ID:             e.ID,
+			RepoID:         e.RepoID,
+			RepoAlias:      aliases[e.RepoID],
+			RepoPath:       e.RepoPath,
+			Commit:         e.Commit,
+			Branch:         e.Branch,
+			Timestamp:      e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+			Status:         e.Status.String(),
+			Source:         e.Source.String(),
+			Device:         e.Device,
+			PreviousCommit: e.PreviousCommit,
+			PreviousBranch: e.PreviousBranch,
+			SupersededBy:   e.SupersededBy,
+			Tags:           annotations[e.ID].Tags,
+			Note:           annotations[e.ID].Note,
+		}
+		for _, rw := range rewrites[e.ID] {
+			je.Rewrites = append(je.Rewrites, jsonRewrite{
+				Type:      rw.RewriteType,
+				OldCommit: rw.OldCommit,
+				NewCommit: rw.NewCommit,
+			})
+		}
+		for _, ref := range pushRefs[e.ID] {
+			je.Pushes = append(je.Pushes, jsonPushRef{
+				Remote:      ref.RemoteName,
+				RemoteURL:   ref.RemoteURL,
+				LocalRef:    ref.LocalRef,
+				LocalSHA:    ref.LocalSHA,
+				RemoteRef:   ref.RemoteRef,
+				RemoteSHA:   ref.RemoteSHA,
+				CommitCount: ref.CommitCount,
+			})
+		}
+		if e.Source == store.SourcePostCheckout {
+			branchSwitch := e.BranchSwitch
+			je.BranchSwitch = &branchSwitch
 		}
 		if enrich {
-			meta := git.GetCommitMetadata(e.RepoPath, e.Commit)
+			meta := commitMetadataFor(e, snapshots)
 			je.Author = meta.AuthorName
 			je.Message = meta.Subject
 		}
@@ -0,0 +1,44 @@
+package tracking
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/heatmap"
+)
+
+// activityDays counts the loaded events per local day for the stats panel
+// heatmap.
+func activityDays(m activityModel) map[string]int {
+	days := make(map[string]int)
+	for _, e := range m.events {
+		days[e.Timestamp.Local().Format("2006-01-02")]++
+	}
+	return days
+}
+
+// heatmapLines draws the compact heatmap of the stats panel: as many weeks
+// as fit in width, ending with the newest event.
+func (m *activityModel) heatmapLines(width int) []string {
+	if len(m.events) == 0 || width < 1 {
+		return nil
+	}
+
+	to := m.events[0].Timestamp
+	for _, e := range m.events {
+		if e.Timestamp.After(to) {
+			to = e.Timestamp
+		}
+	}
+	to = to.Local()
+	monday := to.AddDate(0, 0, -((int(to.Weekday()) + 6) % 7))
+	grid := heatmap.New(m.byDay, monday.AddDate(0, 0, -7*(width-1)), to)
+
+	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Success))
+	emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.UIDim))
+	paint := func(level int, s string) string {
+		if level == 0 {
+			return emptyStyle.Render(s)
+		}
+		return activeStyle.Render(s)
+	}
+	return grid.Cells(heatmapGlyphs(), paint)
+}
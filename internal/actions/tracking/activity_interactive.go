@@ -1,10 +1,11 @@
 package tracking
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -13,15 +14,18 @@ import (
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/format"
 	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/sessions"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui/components"
 	"github.com/footprint-tools/cli/internal/ui/splitpanel"
 	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/where"
 	"golang.org/x/term"
 )
 
 
-func activityInteractive(_ *dispatchers.ParsedFlags, deps Deps) error {
+func activityInteractive(flags *dispatchers.ParsedFlags, deps Deps) error {
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("interactive mode requires a terminal")
 	}
@@ -34,19 +38,64 @@ func activityInteractive(_ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 	defer store.CloseDB(db)
 
-	events, err := deps.ListEvents(db, store.EventFilter{})
+	filter := store.EventFilter{ExcludeSuperseded: true}
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	events, err := deps.ListEvents(db, filter)
 	if err != nil {
 		return fmt.Errorf("failed to list events: %w", err)
 	}
 
+	snapshots := loadMetadataSnapshots(db, events)
 	commitMeta := make(map[string]git.CommitMetadata)
 	for _, e := range events {
 		if _, exists := commitMeta[e.Commit]; !exists {
-			commitMeta[e.Commit] = git.GetCommitMetadata(e.RepoPath, e.Commit)
+			commitMeta[e.Commit] = commitMetadataFor(e, snapshots)
 		}
 	}
 
-	m := newActivityModel(events, commitMeta)
+	m := newActivityModel(events, commitMeta, loadRepoAliases(db))
+	if annotations := loadAnnotations(db, events); annotations != nil {
+		m.annotations = annotations
+	}
+	m.annotate = func(e store.RepoEvent, kind annotationKind, input string) (store.Annotations, error) {
+		return annotateFromInput(db, e, kind, input, deps.Now())
+	}
+	// Events recorded before the search index existed are indexed while the
+	// view is open; the filter matches loaded fields until that is done
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.indexing = true
+	m.index = func() { indexPendingEvents(ctx, db) }
+	// Matches come from the whole database, including events this view
+	// did not load; they are read with their metadata and annotations
+	m.search = func(query string) (activitySearchMsg, error) {
+		results, err := store.SearchEvents(db, query, store.SearchFilter{Group: filter.Group, Where: filter.Where})
+		if err != nil {
+			return activitySearchMsg{}, err
+		}
+		found := activitySearchMsg{commitMeta: make(map[string]git.CommitMetadata)}
+		for _, r := range results {
+			found.events = append(found.events, r.Event)
+		}
+		snapshots := loadMetadataSnapshots(db, found.events)
+		for _, e := range found.events {
+			if _, exists := found.commitMeta[e.Commit]; !exists {
+				found.commitMeta[e.Commit] = commitMetadataFor(e, snapshots)
+			}
+		}
+		found.annotations = loadAnnotations(db, found.events)
+		return found, nil
+	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err = p.Run()
@@ -54,14 +103,28 @@ func activityInteractive(_ *dispatchers.ParsedFlags, deps Deps) error {
 }
 
 
+// activityIndexedMsg reports that unindexed events have been indexed.
+type activityIndexedMsg struct{}
+
+// activitySearchMsg carries the events full-text search found for a query,
+// with their commit metadata and annotations.
+type activitySearchMsg struct {
+	query       string
+	events      []store.RepoEvent
+	commitMeta  map[string]git.CommitMetadata
+	annotations map[int64]store.Annotations
+}
+
 // activityModel is the Bubble Tea model for interactive activity view
 type activityModel struct {
 	events     []store.RepoEvent
 	commitMeta map[string]git.CommitMetadata
+	aliases    repoAliases
 
 	// Stats
 	bySource map[store.Source]int
 	byRepo   map[string]int
+	byDay    map[string]int
 
 	// UI dimensions
 	width  int
@@ -73,6 +136,39 @@ type activityModel struct {
 	filterQuery  string
 	filterSource store.Source // -1 means no filter
 
+	// typingFilter is set after / while the filter takes every key, so it
+	// can hold spaces and the letters bound to other keys
+	typingFilter bool
+
+	// Tags and notes by event ID. The drawer edits them through annotate:
+	// editing is the annotation being typed into editInput
+	annotations map[int64]store.Annotations
+	annotate    func(store.RepoEvent, annotationKind, string) (store.Annotations, error)
+	editing     annotationKind
+	editInput   string
+	editError   string
+
+	// Full-text search over the database; searchHits holds the event IDs
+	// matching searchQuery, which may lag filterQuery while a search runs,
+	// and searchExtra the matches that are not among the loaded events.
+	// index fills the search index in the background; the database is only
+	// searched once it is done
+	search      func(string) (activitySearchMsg, error)
+	searchQuery string
+	searchHits  map[int64]bool
+	searchExtra []store.RepoEvent
+	index       func()
+	indexing    bool
+
+	// Work sessions, newest first. showSessions swaps the events list for
+	// the sessions pane; choosing one keeps only its events (sessionFilter)
+	sessions      []sessions.Session
+	showSessions  bool
+	sessionCursor int
+	sessionScroll int
+	sessionFilter map[int64]bool
+	sessionName   string
+
 	// Focus: 0=events, 1=sidebar, 2=drawer
 	focusedPanel  int
 	sidebarScroll int
@@ -86,28 +182,40 @@ type activityModel struct {
 	colors style.ColorConfig
 }
 
-func newActivityModel(events []store.RepoEvent, commitMeta map[string]git.CommitMetadata) activityModel {
+func newActivityModel(events []store.RepoEvent, commitMeta map[string]git.CommitMetadata, aliases repoAliases) activityModel {
 	// Calculate stats
 	bySource := make(map[store.Source]int)
 	byRepo := make(map[string]int)
 	for _, e := range events {
 		bySource[e.Source]++
-		byRepo[filepath.Base(e.RepoPath)]++
+		byRepo[aliases.label(e)]++
 	}
 
-	return activityModel{
+	m := activityModel{
 		events:         events,
 		commitMeta:     commitMeta,
+		aliases:        aliases,
 		bySource:       bySource,
 		byRepo:         byRepo,
+		annotations:    make(map[int64]store.Annotations),
 		filterSource:   -1,
 		colors:         style.GetColors(),
 		drawerViewport: components.NewThemedViewport(40, 20),
 	}
+	m.sessions = activitySessions(m)
+	m.byDay = activityDays(m)
+	return m
 }
 
 func (m activityModel) Init() tea.Cmd {
-	return nil
+	if m.index == nil {
+		return nil
+	}
+	index := m.index
+	return func() tea.Msg {
+		index()
+		return activityIndexedMsg{}
+	}
 }
 
 func (m activityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -122,12 +230,79 @@ func (m activityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.MouseMsg:
 		return m.handleMouse(msg)
+
+	case activityIndexedMsg:
+		m.indexing = false
+		return m, m.searchCmd()
+
+	case activitySearchMsg:
+		// Drop results for a query the user has typed past
+		if msg.query == m.filterQuery {
+			m = m.withSearchResults(msg)
+		}
+		return m, nil
 	}
 
 	return m, nil
 }
 
+// searchCmd searches the database for the current query in the background.
+func (m activityModel) searchCmd() tea.Cmd {
+	if m.search == nil || m.indexing || m.filterQuery == "" || queryExpr(m.filterQuery) != nil {
+		return nil
+	}
+	query := m.filterQuery
+	search := m.search
+	return func() tea.Msg {
+		found, err := search(query)
+		if err != nil {
+			log.Debug("activity: search failed: %v", err)
+		}
+		found.query = query
+		return found
+	}
+}
+
+// withSearchResults keeps the matches of a search. Matches that were not
+// loaded are listed too, with the metadata and annotations read for them.
+func (m activityModel) withSearchResults(msg activitySearchMsg) activityModel {
+	loaded := make(map[int64]bool, len(m.events))
+	for _, e := range m.events {
+		loaded[e.ID] = true
+	}
+
+	m.searchQuery = msg.query
+	m.searchHits = make(map[int64]bool, len(msg.events))
+	m.searchExtra = nil
+	for _, e := range msg.events {
+		m.searchHits[e.ID] = true
+		if !loaded[e.ID] {
+			m.searchExtra = append(m.searchExtra, e)
+		}
+	}
+
+	for commit, meta := range msg.commitMeta {
+		if _, exists := m.commitMeta[commit]; !exists {
+			m.commitMeta[commit] = meta
+		}
+	}
+	for id, a := range msg.annotations {
+		if _, exists := m.annotations[id]; !exists {
+			m.annotations[id] = a
+		}
+	}
+	return m
+}
+
 func (m activityModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Typing a tag or note takes every key until Enter or Esc
+	if m.editing != annotateNone {
+		return m.handleEditKeys(msg)
+	}
+	if m.typingFilter {
+		return m.handleFilterKeys(msg)
+	}
+
 	// Global keys
 	switch msg.Type {
 	case tea.KeyCtrlC:
@@ -142,6 +317,9 @@ func (m activityModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Handle based on focused panel
+	if m.showSessions && m.focusedPanel == 0 {
+		return m.handleSessionsKeys(msg)
+	}
 	if m.drawerOpen && m.focusedPanel == 2 {
 		return m.handleDrawerKeys(msg)
 	}
@@ -192,10 +370,92 @@ func (m activityModel) handleDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "G":
 		m.drawerViewport.GotoBottom()
 		return m, nil
+	case "n":
+		return m.startEditing(annotateNote), nil
+	case "t":
+		return m.startEditing(annotateTags), nil
+	}
+	return m, nil
+}
+
+// startEditing opens the input for the selected event's note or tags.
+// The note input starts from the current note so it can be edited.
+func (m activityModel) startEditing(kind annotationKind) activityModel {
+	if m.drawerDetail == nil || m.annotate == nil {
+		return m
+	}
+	m.editing = kind
+	m.editInput = ""
+	m.editError = ""
+	if kind == annotateNote {
+		m.editInput = m.annotations[m.drawerDetail.Event.ID].Note
+	}
+	return m
+}
+
+func (m activityModel) handleEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editing = annotateNone
+		m.editError = ""
+		return m, nil
+	case tea.KeyEnter:
+		event := m.drawerDetail.Event
+		updated, err := m.annotate(event, m.editing, m.editInput)
+		if err != nil {
+			m.editError = err.Error()
+			return m, nil
+		}
+		m.annotations[event.ID] = updated
+		m.editing = annotateNone
+		m.editError = ""
+		return m, nil
+	case tea.KeyBackspace:
+		if runes := []rune(m.editInput); len(runes) > 0 {
+			m.editInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case tea.KeySpace:
+		m.editInput += " "
+		return m, nil
+	case tea.KeyRunes:
+		m.editInput += string(msg.Runes)
+		return m, nil
 	}
 	return m, nil
 }
 
+// handleFilterKeys edits the filter typed after /. Enter keeps it, Esc
+// drops it.
+func (m activityModel) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.typingFilter = false
+		m.filterQuery = ""
+		return m, nil
+	case tea.KeyEnter:
+		m.typingFilter = false
+		return m, nil
+	case tea.KeyBackspace:
+		if runes := []rune(m.filterQuery); len(runes) > 0 {
+			m.filterQuery = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.filterQuery += " "
+	case tea.KeyRunes:
+		m.filterQuery += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.cursor = 0
+	m.eventScroll = 0
+	return m, m.searchCmd()
+}
+
 func (m activityModel) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc:
@@ -225,6 +485,15 @@ func (m activityModel) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "c":
 		m.filterSource = -1
 		m.filterQuery = ""
+		m.sessionFilter = nil
+		return m, nil
+	case "s":
+		m.showSessions = !m.showSessions
+		if m.showSessions {
+			m.drawerOpen = false
+			m.drawerDetail = nil
+			m.focusedPanel = 0
+		}
 		return m, nil
 	case "1", "2", "3", "4", "5", "6", "7":
 		return m.toggleSourceFilter(msg.String())
@@ -239,6 +508,10 @@ func (m activityModel) handleEventsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.filterQuery = ""
 			return m, nil
 		}
+		if m.sessionFilter != nil {
+			m.sessionFilter = nil
+			return m, nil
+		}
 		if m.drawerOpen {
 			m.drawerOpen = false
 			m.drawerDetail = nil
@@ -276,7 +549,7 @@ func (m activityModel) handleEventsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.filterQuery) > 0 {
 			m.filterQuery = m.filterQuery[:len(m.filterQuery)-1]
 		}
-		return m, nil
+		return m, m.searchCmd()
 
 	case tea.KeyRunes:
 		return m.handleRunes(msg)
@@ -332,14 +605,23 @@ func (m activityModel) handleRunes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "c":
 		m.filterQuery = ""
 		m.filterSource = -1
+		m.sessionFilter = nil
 		return m, nil
 	case "1", "2", "3", "4", "5", "6", "7":
 		return m.toggleSourceFilter(key)
+	case "/":
+		// Start a new filter: a --where expression or words to search for
+		m.filterQuery = ""
+		m.typingFilter = true
+		m.cursor = 0
+		m.eventScroll = 0
+		return m, nil
 	default:
 		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
 			m.filterQuery += key
 			m.cursor = 0
 			m.eventScroll = 0
+			return m, m.searchCmd()
 		}
 	}
 
@@ -365,6 +647,13 @@ func (m activityModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			footerHeight := 2
 			if msg.Y >= headerHeight && msg.Y < m.height-footerHeight {
 				clickedLine := msg.Y - headerHeight
+				if m.showSessions {
+					clickedIdx := m.sessionScroll + clickedLine
+					if clickedIdx >= 0 && clickedIdx < len(m.sessions) {
+						m.sessionCursor = clickedIdx
+					}
+					break
+				}
 				clickedIdx := m.eventScroll + clickedLine
 				filtered := m.filteredEvents()
 				if clickedIdx >= 0 && clickedIdx < len(filtered) {
@@ -379,6 +668,8 @@ func (m activityModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		switch {
 		case msg.X < statsWidth:
 			m.sidebarScroll = max(0, m.sidebarScroll-1)
+		case msg.X < drawerStart && m.showSessions:
+			m.moveSessionCursor(-1)
 		case msg.X < drawerStart:
 			m.moveCursor(-1)
 		case m.drawerOpen:
@@ -389,6 +680,8 @@ func (m activityModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		switch {
 		case msg.X < statsWidth:
 			m.sidebarScroll++
+		case msg.X < drawerStart && m.showSessions:
+			m.moveSessionCursor(1)
 		case msg.X < drawerStart:
 			m.moveCursor(1)
 		case m.drawerOpen:
@@ -425,19 +718,36 @@ func (m *activityModel) updateDrawerDetail() {
 }
 
 func (m activityModel) filteredEvents() []store.RepoEvent {
-	if m.filterQuery == "" && m.filterSource == -1 {
+	if m.filterQuery == "" && m.filterSource == -1 && m.sessionFilter == nil {
 		return m.events
 	}
 
+	// A query that parses as a --where expression filters by it instead
 	query := strings.ToLower(m.filterQuery)
+	expr := queryExpr(m.filterQuery)
 	var filtered []store.RepoEvent
 
-	for _, e := range m.events {
+	// Search matches that were not loaded join the list in time order
+	events := m.events
+	if expr == nil && m.searchQuery == m.filterQuery && len(m.searchExtra) > 0 {
+		events = append(append([]store.RepoEvent{}, m.events...), m.searchExtra...)
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.After(events[j].Timestamp) })
+	}
+
+	for _, e := range events {
 		if m.filterSource != -1 && e.Source != m.filterSource {
 			continue
 		}
 
-		if query != "" && !m.matchesQuery(e, query) {
+		if m.sessionFilter != nil && !m.sessionFilter[e.ID] {
+			continue
+		}
+
+		if expr != nil {
+			if !where.Match(expr, whereEvent(e, m.aliases, m.annotations[e.ID].Tags)) {
+				continue
+			}
+		} else if query != "" && !m.matchesQuery(e, query) {
 			continue
 		}
 
@@ -447,10 +757,18 @@ func (m activityModel) filteredEvents() []store.RepoEvent {
 	return filtered
 }
 
+// matchesQuery matches the loaded fields by substring, plus any event the
+// database search found for the query (commit bodies, words in any order).
 func (m activityModel) matchesQuery(e store.RepoEvent, query string) bool {
-	repoName := strings.ToLower(filepath.Base(e.RepoPath))
+	if m.searchQuery == m.filterQuery && m.searchHits[e.ID] {
+		return true
+	}
+	repoName := strings.ToLower(m.aliases.label(e))
 	meta := m.commitMeta[e.Commit]
+	a := m.annotations[e.ID]
 	return strings.Contains(repoName, query) ||
+		strings.Contains(strings.Join(a.Tags, " "), query) ||
+		strings.Contains(strings.ToLower(a.Note), query) ||
 		strings.Contains(strings.ToLower(e.Branch), query) ||
 		strings.Contains(strings.ToLower(e.Commit), query) ||
 		strings.Contains(strings.ToLower(meta.Subject), query)
@@ -495,7 +813,12 @@ func (m activityModel) View() string {
 	layout.SetDrawerOpen(m.drawerOpen)
 
 	statsPanel := m.buildStatsPanel(layout, mainHeight)
-	eventsPanel := m.buildEventsPanel(layout, mainHeight)
+	var eventsPanel splitpanel.Panel
+	if m.showSessions {
+		eventsPanel = m.buildSessionsPanel(layout, mainHeight)
+	} else {
+		eventsPanel = m.buildEventsPanel(layout, mainHeight)
+	}
 
 	header := m.renderHeader()
 
@@ -529,8 +852,19 @@ func (m activityModel) renderHeader() string {
 	if m.filterSource != -1 {
 		filterStr = mutedStyle.Render(" | Source: ") + lipgloss.NewStyle().Foreground(m.sourceColor(m.filterSource)).Render(sourceName(m.filterSource))
 	}
-	if m.filterQuery != "" {
-		filterStr += mutedStyle.Render(" | Search: ") + mutedStyle.Render(m.filterQuery)
+	if m.filterQuery != "" || m.typingFilter {
+		label := " | Search: "
+		if queryExpr(m.filterQuery) != nil {
+			label = " | Where: "
+		}
+		query := m.filterQuery
+		if m.typingFilter {
+			query += "_"
+		}
+		filterStr += mutedStyle.Render(label) + mutedStyle.Render(query)
+	}
+	if m.sessionFilter != nil {
+		filterStr += mutedStyle.Render(" | Session: ") + mutedStyle.Render(m.sessionName)
 	}
 
 	filtered := m.filteredEvents()
@@ -572,8 +906,17 @@ func (m *activityModel) buildStatsPanel(layout *splitpanel.Layout, height int) s
 	lines = append(lines, headerStyle.Render("SUMMARY"))
 	lines = append(lines, "")
 	lines = append(lines, labelStyle.Render("Events: ")+valueStyle.Render(formatCount(len(m.events))))
+	lines = append(lines, labelStyle.Render("Sessions: ")+valueStyle.Render(formatCount(len(m.sessions))))
+	lines = append(lines, labelStyle.Render("Time: ")+valueStyle.Render(format.Duration(m.sessionTotal())))
 	lines = append(lines, "")
 
+	if heat := m.heatmapLines(layout.SidebarContentWidth()); len(heat) > 0 {
+		lines = append(lines, headerStyle.Render("ACTIVITY"))
+		lines = append(lines, "")
+		lines = append(lines, heat...)
+		lines = append(lines, "")
+	}
+
 	lines = append(lines, headerStyle.Render("BY SOURCE"))
 	lines = append(lines, "")
 
@@ -720,7 +1063,7 @@ func (m activityModel) formatEventLine(event store.RepoEvent, width int, selecte
 	dateStr := format.Date(event.Timestamp)
 	timeStr := format.Time(event.Timestamp)
 
-	repoName := filepath.Base(event.RepoPath)
+	repoName := m.aliases.label(event)
 	if len(repoName) > 12 {
 		repoName = repoName[:9] + "..."
 	}
@@ -846,6 +1189,8 @@ func (m *activityModel) buildDrawerPanel(layout *splitpanel.Layout, height int)
 		event := m.drawerDetail.Event
 		meta := m.drawerDetail.Meta
 
+		lines = append(lines, m.annotationLines(event, width, headerStyle, labelStyle, valueStyle)...)
+
 		if meta.Subject != "" {
 			lines = append(lines, headerStyle.Render("MESSAGE"))
 			lines = append(lines, "")
@@ -880,13 +1225,19 @@ func (m *activityModel) buildDrawerPanel(layout *splitpanel.Layout, height int)
 		sourceColor := m.sourceColor(event.Source)
 		sourceStyle := lipgloss.NewStyle().Foreground(sourceColor).Bold(true)
 		lines = append(lines, sourceStyle.Render(sourceName(event.Source))+" "+labelStyle.Render("on")+" "+valueStyle.Render(event.Branch))
+		if summary := checkoutSummary(event); summary != "" {
+			lines = append(lines, labelStyle.Render(summary))
+		}
 		lines = append(lines, "")
 
 		lines = append(lines, headerStyle.Render("DETAILS"))
 		lines = append(lines, "")
 		lines = append(lines, labelStyle.Render("Commit:  ")+valueStyle.Render(event.Commit))
-		lines = append(lines, labelStyle.Render("Repo:    ")+valueStyle.Render(filepath.Base(event.RepoPath)))
+		lines = append(lines, labelStyle.Render("Repo:    ")+valueStyle.Render(m.aliases.label(event)))
 		lines = append(lines, labelStyle.Render("Branch:  ")+valueStyle.Render(event.Branch))
+		if event.PreviousCommit != "" {
+			lines = append(lines, labelStyle.Render("From:    ")+valueStyle.Render(event.PreviousCommit))
+		}
 		lines = append(lines, "")
 
 		if meta.AuthorName != "" {
@@ -948,6 +1299,51 @@ func (m *activityModel) buildDrawerPanel(layout *splitpanel.Layout, height int)
 	}
 }
 
+// annotationLines renders the tags and note of the drawer's event, or the
+// input while one is being edited.
+func (m activityModel) annotationLines(event store.RepoEvent, width int, headerStyle, labelStyle, valueStyle lipgloss.Style) []string {
+	var lines []string
+
+	if m.editing != annotateNone {
+		title := "NOTE"
+		hint := "Enter to save, empty to remove"
+		if m.editing == annotateTags {
+			title = "TAGS"
+			hint = "Space-separated; -tag removes"
+		}
+		lines = append(lines, headerStyle.Render(title))
+		lines = append(lines, "")
+		wrapped := wrapTextSimple(m.editInput+"█", width-2)
+		for _, line := range strings.Split(wrapped, "\n") {
+			lines = append(lines, valueStyle.Render(line))
+		}
+		lines = append(lines, labelStyle.Render(hint))
+		if m.editError != "" {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Error)).Render(m.editError))
+		}
+		lines = append(lines, "")
+		return lines
+	}
+
+	a := m.annotations[event.ID]
+	if len(a.Tags) > 0 {
+		lines = append(lines, valueStyle.Render("#"+strings.Join(a.Tags, " #")))
+		lines = append(lines, "")
+	}
+	if a.Note != "" {
+		lines = append(lines, headerStyle.Render("NOTE"))
+		lines = append(lines, "")
+		for _, noteLine := range strings.Split(a.Note, "\n") {
+			wrapped := wrapTextSimple(noteLine, width-2)
+			for _, wl := range strings.Split(wrapped, "\n") {
+				lines = append(lines, valueStyle.Render(wl))
+			}
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
 func (m activityModel) renderFooter() string {
 	help := components.NewThemedHelp()
 
@@ -955,6 +1351,24 @@ func (m activityModel) renderFooter() string {
 	tabBinding := key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "focus"))
 
 	switch {
+	case m.editing != annotateNone:
+		bindings = []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "save")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+		}
+	case m.typingFilter:
+		bindings = []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "apply")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "clear")),
+		}
+	case m.showSessions && m.focusedPanel == 0:
+		bindings = []key.Binding{
+			tabBinding,
+			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "nav")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "show events")),
+			key.NewBinding(key.WithKeys("esc", "s"), key.WithHelp("Esc", "back")),
+		}
 	case m.focusedPanel == 2 && m.drawerOpen:
 		bindings = []key.Binding{
 			tabBinding,
@@ -962,12 +1376,15 @@ func (m activityModel) renderFooter() string {
 			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "scroll")),
 			key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "top")),
 			key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "bottom")),
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tag")),
+			key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "note")),
 		}
 	case m.focusedPanel == 1:
 		bindings = []key.Binding{
 			tabBinding,
 			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
 			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "filter")),
+			key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sessions")),
 			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
 		}
 	default:
@@ -979,7 +1396,7 @@ func (m activityModel) renderFooter() string {
 			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "source")),
 		}
 		if m.filterQuery == "" {
-			bindings = append(bindings, key.NewBinding(key.WithKeys(""), key.WithHelp("type", "search")))
+			bindings = append(bindings, key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")))
 		} else {
 			bindings = append(bindings, key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")))
 		}
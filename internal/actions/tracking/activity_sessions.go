@@ -0,0 +1,190 @@
+package tracking
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/sessions"
+	"github.com/footprint-tools/cli/internal/ui/splitpanel"
+)
+
+// activitySessions groups the loaded events into work sessions for the
+// sessions pane, newest first like the events list.
+func activitySessions(m activityModel) []sessions.Session {
+	list := sessions.GroupByRepo(m.events, sessions.ConfiguredGap())
+	slices.Reverse(list)
+	return list
+}
+
+// handleSessionsKeys handles keys while the sessions pane replaces the
+// events list. Enter limits the events to the chosen session.
+func (m activityModel) handleSessionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.showSessions = false
+		return m, nil
+	case tea.KeyEnter:
+		return m.selectSession(), nil
+	case tea.KeyUp:
+		m.moveSessionCursor(-1)
+		return m, nil
+	case tea.KeyDown:
+		m.moveSessionCursor(1)
+		return m, nil
+	case tea.KeyPgUp:
+		m.moveSessionCursor(-10)
+		return m, nil
+	case tea.KeyPgDown:
+		m.moveSessionCursor(10)
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "s":
+		m.showSessions = false
+	case "j":
+		m.moveSessionCursor(1)
+	case "k":
+		m.moveSessionCursor(-1)
+	case "g":
+		m.sessionCursor = 0
+	case "G":
+		m.sessionCursor = max(0, len(m.sessions)-1)
+	}
+	return m, nil
+}
+
+func (m *activityModel) moveSessionCursor(delta int) {
+	if len(m.sessions) == 0 {
+		return
+	}
+	m.sessionCursor = max(0, min(m.sessionCursor+delta, len(m.sessions)-1))
+}
+
+// selectSession shows the events of the session under the cursor.
+func (m activityModel) selectSession() activityModel {
+	if m.sessionCursor >= len(m.sessions) {
+		return m
+	}
+
+	s := m.sessions[m.sessionCursor]
+	m.sessionFilter = make(map[int64]bool, len(s.Events))
+	for _, e := range s.Events {
+		m.sessionFilter[e.ID] = true
+	}
+	m.sessionName = m.sessionLabel(s) + " " + format.DateShort(s.Start) + " " + format.Time(s.Start)
+	m.showSessions = false
+	m.focusedPanel = 0
+	m.cursor = 0
+	m.eventScroll = 0
+	return m
+}
+
+// sessionLabel names the repositories of a session.
+func (m activityModel) sessionLabel(s sessions.Session) string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, e := range s.Events {
+		if !seen[e.RepoID] {
+			seen[e.RepoID] = true
+			labels = append(labels, m.aliases.label(e))
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// sessionTotal returns the time spent in all sessions.
+func (m activityModel) sessionTotal() time.Duration {
+	var total time.Duration
+	for _, s := range m.sessions {
+		total += s.Duration()
+	}
+	return total
+}
+
+func (m *activityModel) buildSessionsPanel(layout *splitpanel.Layout, height int) splitpanel.Panel {
+	colors := m.colors
+	infoColor := lipgloss.Color(colors.Info)
+	mutedColor := lipgloss.Color(colors.Muted)
+	headerColor := lipgloss.Color(colors.Header)
+	borderColor := lipgloss.Color(colors.Border)
+
+	// Subtract: 2 for panel borders, 2 for header + separator
+	visibleRows := max(1, height-4)
+
+	scrollOffset := m.sessionScroll
+	if m.sessionCursor < scrollOffset {
+		scrollOffset = m.sessionCursor
+	}
+	if m.sessionCursor >= scrollOffset+visibleRows {
+		scrollOffset = m.sessionCursor - visibleRows + 1
+	}
+	scrollOffset = max(0, min(scrollOffset, max(0, len(m.sessions)-visibleRows)))
+	m.sessionScroll = scrollOffset
+
+	var lines []string
+	width := layout.MainContentWidth()
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(headerColor)
+	header := "  " +
+		padRight("DATE", 10) + " " +
+		padRight("START", 5) + " " +
+		padRight("END", 5) + " " +
+		padLeft("DURATION", 8) + " " +
+		padLeft("COMMITS", 7) + " " +
+		"REPOSITORY"
+	lines = append(lines, headerStyle.Render(header))
+
+	sepStyle := lipgloss.NewStyle().Foreground(borderColor)
+	lines = append(lines, sepStyle.Render(strings.Repeat("─", min(width, len(header)+10))))
+
+	if len(m.sessions) == 0 {
+		emptyStyle := lipgloss.NewStyle().Foreground(mutedColor).Italic(true)
+		lines = append(lines, emptyStyle.Render("No sessions"))
+	}
+
+	timeStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	valueStyle := lipgloss.NewStyle().Foreground(infoColor)
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color(colors.UIActive))
+
+	endIdx := min(scrollOffset+visibleRows, len(m.sessions))
+	for i := scrollOffset; i < endIdx; i++ {
+		s := m.sessions[i]
+		date := padRight(format.Date(s.Start), 10)
+		start := padRight(format.Time(s.Start), 5)
+		end := padRight(format.Time(s.End), 5)
+		duration := padLeft(format.Duration(s.Duration()), 8)
+		commits := padLeft(formatCount(s.Commits), 7)
+		repos := m.sessionLabel(s)
+		if maxRepo := max(5, width-52); len(repos) > maxRepo {
+			repos = repos[:maxRepo-3] + "..."
+		}
+
+		if i == m.sessionCursor {
+			lines = append(lines, selectedStyle.Render("▸ "+date+" "+start+" "+end+" "+duration+" "+commits+" "+repos))
+			continue
+		}
+		lines = append(lines, "  "+
+			timeStyle.Render(date)+" "+
+			timeStyle.Render(start)+" "+
+			timeStyle.Render(end)+" "+
+			valueStyle.Render(duration)+" "+
+			valueStyle.Render(commits)+" "+
+			repos)
+	}
+
+	return splitpanel.Panel{
+		Lines:      lines,
+		ScrollPos:  scrollOffset,
+		TotalItems: len(m.sessions),
+	}
+}
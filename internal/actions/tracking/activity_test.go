@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/store"
@@ -160,6 +161,7 @@ func mockDeps() Deps {
 func mockDepsWithFilterCapture(capturedFilter *store.EventFilter, totalEvents int) Deps {
 	return Deps{
 		DBPath: func() string { return ":memory:" },
+		Now:    func() time.Time { return time.Date(2024, 6, 12, 10, 0, 0, 0, time.UTC) },
 		OpenDB: func(path string) (*sql.DB, error) {
 			db, err := sql.Open("sqlite3", path)
 			if err != nil {
@@ -241,6 +243,58 @@ func TestActivity_DateFilters(t *testing.T) {
 	}
 }
 
+func TestActivity_RelativeDates(t *testing.T) {
+	var capturedFilter store.EventFilter
+	deps := mockDepsWithFilterCapture(&capturedFilter, 5)
+
+	tests := []struct {
+		flags        []string
+		since, until string
+		err          string
+	}{
+		{flags: []string{"--until=2024-06-01"}, until: "2024-06-01T23:59:59Z"},
+		{flags: []string{"--since=yesterday"}, since: "2024-06-11T00:00:00Z"},
+		{flags: []string{"--since=3d"}, since: "2024-06-09T10:00:00Z"},
+		{flags: []string{"--during=last week"}, since: "2024-06-03T00:00:00Z", until: "2024-06-09T23:59:59Z"},
+		{flags: []string{"--during=2024-W01"}, since: "2024-01-01T00:00:00Z", until: "2024-01-07T23:59:59Z"},
+		{flags: []string{"--since=last-week"}, err: "invalid --since: unrecognized date 'last-week'"},
+		{flags: []string{"--until=2024-02-30"}, err: "invalid --until: invalid date '2024-02-30'"},
+		{flags: []string{"--during=today", "--since=2024-01-01"}, err: "use either --during or --since/--until"},
+		{flags: []string{"--during=3d"}, err: "invalid --during '3d': it needs a span"},
+		{flags: []string{"--during=now"}, err: "not a point in time"},
+		{flags: []string{"--since=today", "--until=last month"}, err: "--until last month is before --since today"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.flags, " "), func(t *testing.T) {
+			capturedFilter = store.EventFilter{}
+			err := activity([]string{}, dispatchers.NewParsedFlags(tt.flags), deps)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("activity() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("activity() unexpected error = %v", err)
+			}
+
+			format := func(ts *time.Time) string {
+				if ts == nil {
+					return ""
+				}
+				return ts.Format(time.RFC3339)
+			}
+			if got := format(capturedFilter.Since); got != tt.since {
+				t.Errorf("since = %q, want %q", got, tt.since)
+			}
+			if got := format(capturedFilter.Until); got != tt.until {
+				t.Errorf("until = %q, want %q", got, tt.until)
+			}
+		})
+	}
+}
+
 func TestActivity_OnelineFlag(t *testing.T) {
 	var pagerOutput string
 	deps := Deps{
@@ -410,3 +464,84 @@ func TestActivity_JSON_WithEvents(t *testing.T) {
 		t.Errorf("activity() JSON should contain commit, got %q", printedOutput)
 	}
 }
+
+func TestActivity_HidesSupersededByDefault(t *testing.T) {
+	var capturedFilter store.EventFilter
+	deps := mockDepsWithFilterCapture(&capturedFilter, 5)
+
+	err := activity([]string{}, dispatchers.NewParsedFlags([]string{}), deps)
+	if err != nil {
+		t.Fatalf("activity() unexpected error = %v", err)
+	}
+	if !capturedFilter.ExcludeSuperseded {
+		t.Error("activity() should hide superseded events by default")
+	}
+
+	err = activity([]string{}, dispatchers.NewParsedFlags([]string{"--show-rewrites"}), deps)
+	if err != nil {
+		t.Fatalf("activity() unexpected error = %v", err)
+	}
+	if capturedFilter.ExcludeSuperseded {
+		t.Error("activity() --show-rewrites should include superseded events")
+	}
+}
+
+func TestActivity_ShowRewrites(t *testing.T) {
+	var pagerOutput string
+	deps := Deps{
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(path string) (*sql.DB, error) {
+			s, err := store.New(path)
+			if err != nil {
+				return nil, err
+			}
+			db := s.DB()
+			event := store.RepoEvent{
+				RepoID:    "test",
+				Commit:    "new2222222",
+				Branch:    "main",
+				Status:    store.StatusPending,
+				Source:    store.SourcePostRewrite,
+				Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+			}
+			if err := store.InsertEvent(db, event); err != nil {
+				return nil, err
+			}
+			_, err = store.RecordRewrites(db, event, "amend", []store.CommitRewrite{
+				{OldCommit: "old1111111", NewCommit: "new2222222"},
+			})
+			return db, err
+		},
+		ListEvents: store.ListEvents,
+		Pager:      func(s string) { pagerOutput = s },
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{"--show-rewrites", "--oneline"})
+	err := activity([]string{}, flags, deps)
+
+	if err != nil {
+		t.Fatalf("activity() unexpected error = %v", err)
+	}
+	if !strings.Contains(pagerOutput, "amend old1111 -> new2222") {
+		t.Errorf("activity() should show rewrite mapping, got %q", pagerOutput)
+	}
+}
+
+func TestActivity_WhereFilter(t *testing.T) {
+	var capturedFilter store.EventFilter
+	deps := mockDepsWithFilterCapture(&capturedFilter, 5)
+
+	flags := dispatchers.NewParsedFlags([]string{`--where=repo ~ "acme/*" and hour >= 18`})
+	if err := activity([]string{}, flags, deps); err != nil {
+		t.Fatalf("activity() unexpected error = %v", err)
+	}
+	if capturedFilter.Where == nil {
+		t.Error("activity() where filter should be applied")
+	}
+
+	flags = dispatchers.NewParsedFlags([]string{"--where=hour = 25"})
+	err := activity([]string{}, flags, deps)
+	if err == nil || !strings.Contains(err.Error(), "invalid --where: invalid hour '25'") {
+		t.Errorf("activity() error = %v, want an invalid --where error", err)
+	}
+}
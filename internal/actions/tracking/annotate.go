@@ -0,0 +1,280 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// maxTagLength keeps tags short enough to read in the activity view.
+const maxTagLength = 40
+
+// annotationKind is the annotation being edited in the activity view.
+type annotationKind int
+
+const (
+	annotateNone annotationKind = iota
+	annotateNote
+	annotateTags
+)
+
+// Note sets, shows or clears the note of an event.
+func Note(args []string, flags *dispatchers.ParsedFlags) error {
+	return note(args, flags, DefaultDeps())
+}
+
+func note(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("event")
+	}
+	text := strings.TrimSpace(strings.Join(args[1:], " "))
+	clearNote := flags.Has("--clear")
+	if clearNote && text != "" {
+		return fmt.Errorf("--clear takes no note text")
+	}
+
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	event, err := store.FindEvent(s.DB(), args[0])
+	if err != nil {
+		return err
+	}
+
+	if text != "" || clearNote {
+		if err := store.SetNote(s.DB(), event.ID, text, deps.Now()); err != nil {
+			return fmt.Errorf("failed to save note: %w", err)
+		}
+		requeueAnnotated(s.DB(), event)
+	}
+
+	annotations, err := store.ListAnnotations(s.DB(), []int64{event.ID})
+	if err != nil {
+		return fmt.Errorf("failed to read note: %w", err)
+	}
+	current := annotations[event.ID].Note
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, map[string]any{
+			"event_id": event.ID,
+			"commit":   event.Commit,
+			"note":     current,
+		})
+	}
+
+	switch {
+	case clearNote:
+		_, _ = deps.Printf("Cleared the note on %s\n", describeEvent(event))
+	case text != "":
+		_, _ = deps.Printf("Noted %s\n", describeEvent(event))
+	case current == "":
+		_, _ = deps.Printf("No note on %s\n", describeEvent(event))
+	default:
+		_, _ = deps.Println(current)
+	}
+	return nil
+}
+
+// Tag adds or removes tags on an event, or lists tags.
+func Tag(args []string, flags *dispatchers.ParsedFlags) error {
+	return tag(args, flags, DefaultDeps())
+}
+
+func tag(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	jsonOutput := flags.Has("--json")
+	remove := flags.Has("--remove")
+
+	var labels []string
+	for _, arg := range args[min(1, len(args)):] {
+		label, err := normalizeTag(arg)
+		if err != nil {
+			return err
+		}
+		labels = append(labels, label)
+	}
+	if remove && len(labels) == 0 {
+		return usage.MissingArgument("label")
+	}
+
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if len(args) == 0 {
+		return listTags(s, jsonOutput, deps)
+	}
+
+	event, err := store.FindEvent(s.DB(), args[0])
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, label := range labels {
+		var ok bool
+		if remove {
+			ok, err = store.RemoveTag(s.DB(), event.ID, label)
+		} else {
+			ok, err = store.AddTag(s.DB(), event.ID, label, deps.Now())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update tags: %w", err)
+		}
+		if ok {
+			changed++
+		}
+	}
+	if changed > 0 {
+		requeueAnnotated(s.DB(), event)
+	}
+
+	annotations, err := store.ListAnnotations(s.DB(), []int64{event.ID})
+	if err != nil {
+		return fmt.Errorf("failed to read tags: %w", err)
+	}
+	tags := annotations[event.ID].Tags
+
+	if jsonOutput {
+		if tags == nil {
+			tags = []string{}
+		}
+		return output.JSON(deps.Println, map[string]any{
+			"event_id": event.ID,
+			"commit":   event.Commit,
+			"tags":     tags,
+		})
+	}
+
+	switch {
+	case len(labels) > 0 && remove:
+		_, _ = deps.Printf("Removed %s from %s\n", pluralize(changed, "tag"), describeEvent(event))
+	case len(labels) > 0:
+		_, _ = deps.Printf("Added %s to %s\n", pluralize(changed, "tag"), describeEvent(event))
+	}
+	if len(tags) == 0 {
+		_, _ = deps.Printf("No tags on %s\n", describeEvent(event))
+		return nil
+	}
+	_, _ = deps.Println("#" + strings.Join(tags, " #"))
+	return nil
+}
+
+func listTags(s *store.Store, jsonOutput bool, deps Deps) error {
+	counts, err := store.ListTags(s.DB())
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	if jsonOutput {
+		type jsonTag struct {
+			Tag    string `json:"tag"`
+			Events int    `json:"events"`
+		}
+		out := make([]jsonTag, 0, len(counts))
+		for _, c := range counts {
+			out = append(out, jsonTag{Tag: c.Tag, Events: c.Events})
+		}
+		return output.JSON(deps.Println, out)
+	}
+
+	if len(counts) == 0 {
+		_, _ = deps.Println("No tags yet. Tag an event with: fp tag <event> <label>")
+		return nil
+	}
+	for _, c := range counts {
+		_, _ = deps.Printf("%-20s %s\n", c.Tag, pluralize(c.Events, "event"))
+	}
+	return nil
+}
+
+// annotateFromInput applies text typed in the activity view: the new note,
+// or space-separated tags where a leading '-' removes the tag. Returns the
+// event's annotations after the change.
+func annotateFromInput(db *sql.DB, e store.RepoEvent, kind annotationKind, input string, now time.Time) (store.Annotations, error) {
+	switch kind {
+	case annotateNote:
+		if err := store.SetNote(db, e.ID, strings.TrimSpace(input), now); err != nil {
+			return store.Annotations{}, err
+		}
+	case annotateTags:
+		for _, word := range strings.Fields(input) {
+			remove := strings.HasPrefix(word, "-")
+			label, err := normalizeTag(strings.TrimPrefix(word, "-"))
+			if err != nil {
+				return store.Annotations{}, err
+			}
+			if remove {
+				_, err = store.RemoveTag(db, e.ID, label)
+			} else {
+				_, err = store.AddTag(db, e.ID, label, now)
+			}
+			if err != nil {
+				return store.Annotations{}, err
+			}
+		}
+	}
+	requeueAnnotated(db, e)
+
+	annotations, err := store.ListAnnotations(db, []int64{e.ID})
+	if err != nil {
+		return store.Annotations{}, err
+	}
+	return annotations[e.ID], nil
+}
+
+// requeueAnnotated marks an exported event pending again when
+// export_annotations is on, so the next export rewrites its row with the
+// new tags and note.
+func requeueAnnotated(db *sql.DB, e store.RepoEvent) {
+	if e.Status != store.StatusExported || !exportAnnotations() {
+		return
+	}
+	if err := store.UpdateEventStatuses(db, []int64{e.ID}, store.StatusPending); err != nil {
+		log.Warn("annotate: could not queue %.7s for export: %v", e.Commit, err)
+	}
+}
+
+// loadAnnotations returns the tags and notes of the events. Annotations only
+// add detail, so a failure is logged and the events are shown without them.
+func loadAnnotations(db *sql.DB, events []store.RepoEvent) map[int64]store.Annotations {
+	annotations, err := store.ListAnnotations(db, eventIDs(events))
+	if err != nil {
+		log.Warn("could not read annotations: %v", err)
+		return nil
+	}
+	return annotations
+}
+
+// normalizeTag lowercases a tag and drops a leading '#'. Tags are single
+// words: no spaces or commas, since export joins them with commas.
+func normalizeTag(value string) (string, error) {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "#"))
+	if tag == "" {
+		return "", fmt.Errorf("invalid tag '%s': tags cannot be empty", value)
+	}
+	if len(tag) > maxTagLength {
+		return "", fmt.Errorf("invalid tag '%s': tags are at most %d characters", value, maxTagLength)
+	}
+	if strings.IndexFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) >= 0 {
+		return "", fmt.Errorf("invalid tag '%s': tags cannot contain spaces or commas", value)
+	}
+	return tag, nil
+}
+
+// describeEvent names an event for messages, e.g. "abc1234 on main (github.com/user/repo)".
+func describeEvent(e store.RepoEvent) string {
+	return fmt.Sprintf("%.7s on %s (%s)", e.Commit, e.Branch, e.RepoID)
+}
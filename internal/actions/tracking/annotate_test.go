@@ -0,0 +1,177 @@
+package tracking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// annotateTestStore creates a database file with two events, the first one exported.
+func annotateTestStore(t *testing.T, now time.Time) string {
+	t.Helper()
+	exported := testEvent("github.com/user/repo", "abc1234567", now.Add(-time.Hour))
+	exported.Status = store.StatusExported
+	return newTestStoreFile(t, exported, testEvent("github.com/user/repo", "def7654321", now.Add(-2*time.Hour)))
+}
+
+func eventAnnotations(t *testing.T, path, commit string) (store.RepoEvent, store.Annotations) {
+	t.Helper()
+	s := openTestStore(t, path)
+
+	e, err := store.FindEvent(s.DB(), commit)
+	require.NoError(t, err)
+	annotations, err := store.ListAnnotations(s.DB(), []int64{e.ID})
+	require.NoError(t, err)
+	return e, annotations[e.ID]
+}
+
+func TestNote(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := annotateTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, note([]string{"abc1", "Hotfix", "for", "the", "outage"}, dispatchers.NewParsedFlags(nil), deps))
+	_, a := eventAnnotations(t, path, "abc1")
+	require.Equal(t, "Hotfix for the outage", a.Note)
+
+	out = nil
+	require.NoError(t, note([]string{"abc1"}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"Hotfix for the outage"}, out)
+
+	require.NoError(t, note([]string{"abc1"}, dispatchers.NewParsedFlags([]string{"--clear"}), deps))
+	_, a = eventAnnotations(t, path, "abc1")
+	require.True(t, a.IsEmpty())
+
+	require.ErrorContains(t, note([]string{"abc1", "text"}, dispatchers.NewParsedFlags([]string{"--clear"}), deps), "--clear")
+	require.Error(t, note([]string{"ffff"}, dispatchers.NewParsedFlags(nil), deps))
+}
+
+func TestTag(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := annotateTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, tag([]string{"abc1", "#Billable", "review"}, dispatchers.NewParsedFlags(nil), deps))
+	require.NoError(t, tag([]string{"def7", "billable"}, dispatchers.NewParsedFlags(nil), deps))
+	_, a := eventAnnotations(t, path, "abc1")
+	require.Equal(t, []string{"billable", "review"}, a.Tags)
+
+	require.NoError(t, tag([]string{"abc1", "review"}, dispatchers.NewParsedFlags([]string{"--remove"}), deps))
+	_, a = eventAnnotations(t, path, "abc1")
+	require.Equal(t, []string{"billable"}, a.Tags)
+
+	out = nil
+	require.NoError(t, tag(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+	var counts []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &counts))
+	require.Equal(t, []map[string]any{{"tag": "billable", "events": float64(2)}}, counts)
+
+	require.ErrorContains(t, tag([]string{"abc1", "two words"}, dispatchers.NewParsedFlags(nil), deps), "invalid tag")
+	require.Error(t, tag([]string{"abc1"}, dispatchers.NewParsedFlags([]string{"--remove"}), deps))
+}
+
+func TestTag_RequeuesExportedEvents(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("annotations not exported", func(t *testing.T) {
+		useTestConfig(t, "")
+		path := annotateTestStore(t, now)
+		var out []string
+		require.NoError(t, tag([]string{"abc1", "incident"}, dispatchers.NewParsedFlags(nil), pruneTestDeps(path, now, &out)))
+		e, _ := eventAnnotations(t, path, "abc1")
+		require.Equal(t, store.StatusExported, e.Status)
+	})
+
+	t.Run("annotations exported", func(t *testing.T) {
+		useTestConfig(t, "export_annotations=true\n")
+		path := annotateTestStore(t, now)
+		var out []string
+		require.NoError(t, tag([]string{"abc1", "incident"}, dispatchers.NewParsedFlags(nil), pruneTestDeps(path, now, &out)))
+		e, _ := eventAnnotations(t, path, "abc1")
+		require.Equal(t, store.StatusPending, e.Status)
+	})
+}
+
+func TestActivity_TagFilter(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := annotateTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, tag([]string{"def7", "incident"}, dispatchers.NewParsedFlags(nil), deps))
+	require.NoError(t, note([]string{"def7", "Paged at 3am"}, dispatchers.NewParsedFlags(nil), deps))
+
+	out = nil
+	require.NoError(t, activity(nil, dispatchers.NewParsedFlags([]string{"--tag=#Incident", "--json"}), deps))
+	var events []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &events))
+	require.Len(t, events, 1)
+	require.Equal(t, "def7654321", events[0]["commit"])
+	require.Equal(t, []any{"incident"}, events[0]["tags"])
+	require.Equal(t, "Paged at 3am", events[0]["note"])
+
+	require.ErrorContains(t, activity(nil, dispatchers.NewParsedFlags([]string{"--tag=a,b"}), deps), "invalid tag")
+}
+
+func TestAnnotateFromInput(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := openTestStore(t, annotateTestStore(t, now))
+
+	e, err := store.FindEvent(s.DB(), "abc1")
+	require.NoError(t, err)
+
+	a, err := annotateFromInput(s.DB(), e, annotateTags, "billable #review", now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"billable", "review"}, a.Tags)
+
+	a, err = annotateFromInput(s.DB(), e, annotateTags, "-review incident", now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"billable", "incident"}, a.Tags)
+
+	a, err = annotateFromInput(s.DB(), e, annotateNote, "  needs a follow-up  ", now)
+	require.NoError(t, err)
+	require.Equal(t, "needs a follow-up", a.Note)
+
+	_, err = annotateFromInput(s.DB(), e, annotateTags, "ok bad,tag", now)
+	require.ErrorContains(t, err, "invalid tag")
+}
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"billable", "billable", true},
+		{"#Incident", "incident", true},
+		{"  review ", "review", true},
+		{"", "", false},
+		{"#", "", false},
+		{"two words", "", false},
+		{"a,b", "", false},
+		{"x123456789012345678901234567890123456789012", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := normalizeTag(tt.input)
+			if !tt.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -58,6 +58,24 @@ func setupBackfill(args []string, deps Deps) (repoID string, repoRoot string, er
 	return string(id), repoRoot, nil
 }
 
+// backfillOptions reads the commit range to import. Dates are passed to
+// git as exact times, so relative dates mean the same as everywhere else.
+func backfillOptions(flags *dispatchers.ParsedFlags, deps Deps) (git.ListCommitsOptions, error) {
+	opts := git.ListCommitsOptions{Limit: flags.Int("--limit", 0)}
+
+	since, until, err := dateRange(flags, deps.Now)
+	if err != nil {
+		return opts, err
+	}
+	if since != nil {
+		opts.Since = since.Format(time.RFC3339)
+	}
+	if until != nil {
+		opts.Until = until.Format(time.RFC3339)
+	}
+	return opts, nil
+}
+
 // doBackfillText performs the backfill and prints text output.
 func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	repoID, repoRoot, err := setupBackfill(args, deps)
@@ -65,10 +83,9 @@ func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
+	opts, err := backfillOptions(flags, deps)
+	if err != nil {
+		return err
 	}
 
 	commits, err := git.ListCommits(repoRoot, opts)
@@ -91,7 +108,11 @@ func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 
 	_ = deps.InitDB(db)
 
+	remoteURL, _ := deps.OriginURL(repoRoot)
+	repoID = identifyRepo(db, repoRoot, remoteURL, repoID, deps)
+
 	branchOverride := flags.String("--branch", "")
+	snapshot := metadataSnapshotsEnabled()
 
 	imported := 0
 	skipped := 0
@@ -121,6 +142,9 @@ func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 
 		if err := deps.InsertEvent(db, event); err == nil {
 			imported++
+			if snapshot {
+				snapshotCommitMetadata(db, event)
+			}
 		} else {
 			skipped++
 		}
@@ -137,10 +161,9 @@ func doBackfillDryRun(args []string, flags *dispatchers.ParsedFlags, deps Deps)
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
+	opts, err := backfillOptions(flags, deps)
+	if err != nil {
+		return err
 	}
 
 	commits, err := git.ListCommits(repoRoot, opts)
@@ -181,10 +204,9 @@ func doBackfillDryRunJSON(args []string, flags *dispatchers.ParsedFlags, deps De
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
+	opts, err := backfillOptions(flags, deps)
+	if err != nil {
+		return err
 	}
 
 	commits, err := git.ListCommits(repoRoot, opts)
@@ -242,10 +264,9 @@ func doBackfillJSON(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
+	opts, err := backfillOptions(flags, deps)
+	if err != nil {
+		return err
 	}
 
 	commits, err := git.ListCommits(repoRoot, opts)
@@ -280,7 +301,11 @@ func doBackfillJSON(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 
 	_ = deps.InitDB(db)
 
+	remoteURL, _ := deps.OriginURL(repoRoot)
+	repoID = identifyRepo(db, repoRoot, remoteURL, repoID, deps)
+
 	branchOverride := flags.String("--branch", "")
+	snapshot := metadataSnapshotsEnabled()
 
 	// Insert each commit as an event
 	for _, c := range commits {
@@ -309,6 +334,9 @@ func doBackfillJSON(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 
 		if err := deps.InsertEvent(db, event); err == nil {
 			result.Imported++
+			if snapshot {
+				snapshotCommitMetadata(db, event)
+			}
 		} else {
 			result.Skipped++
 		}
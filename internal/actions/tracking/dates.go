@@ -0,0 +1,78 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+)
+
+// dateFlag parses a date flag such as --since, nil when it is not set.
+// now is only called when it is.
+func dateFlag(flags *dispatchers.ParsedFlags, name string, now func() time.Time) (*format.Period, error) {
+	value := flags.String(name, "")
+	if value == "" {
+		return nil, nil
+	}
+	p, err := format.ParsePeriod(value, now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &p, nil
+}
+
+// duringFlag parses --during, nil when it is not set. It names a span such
+// as a day or a week; a point in time such as 3d or now spans nothing, so
+// it is refused rather than matching no events.
+func duringFlag(flags *dispatchers.ParsedFlags, now func() time.Time) (*format.Period, error) {
+	during, err := dateFlag(flags, "--during", now)
+	if err != nil || during == nil {
+		return during, err
+	}
+	if !during.End.After(during.Start) {
+		return nil, fmt.Errorf("invalid --during '%s': it needs a span such as yesterday, this week or 2025-03, not a point in time",
+			flags.String("--during", ""))
+	}
+	return during, nil
+}
+
+// dateRange reads --since, --until and --during. --until keeps all of the
+// day or period it names, so --until yesterday includes yesterday, and
+// --during sets both ends.
+func dateRange(flags *dispatchers.ParsedFlags, now func() time.Time) (since, until *time.Time, err error) {
+	during, err := duringFlag(flags, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if during != nil {
+		if flags.String("--since", "") != "" || flags.String("--until", "") != "" {
+			return nil, nil, errors.New("use either --during or --since/--until")
+		}
+		start, last := during.Start, during.Last()
+		return &start, &last, nil
+	}
+
+	sincePeriod, err := dateFlag(flags, "--since", now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sincePeriod != nil {
+		since = &sincePeriod.Start
+	}
+
+	untilPeriod, err := dateFlag(flags, "--until", now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if untilPeriod != nil {
+		last := untilPeriod.Last()
+		until = &last
+	}
+
+	if since != nil && until != nil && until.Before(*since) {
+		return nil, nil, fmt.Errorf("--until %s is before --since %s", flags.String("--until", ""), flags.String("--since", ""))
+	}
+	return since, until, nil
+}
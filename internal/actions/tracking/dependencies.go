@@ -2,6 +2,7 @@ package tracking
 
 import (
 	"database/sql"
+	"io"
 	"os"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	repodomain "github.com/footprint-tools/cli/internal/repo"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui"
+	"golang.org/x/term"
 )
 
 type Deps struct {
@@ -21,9 +23,15 @@ type Deps struct {
 	GetRemoteURL   func(string, string) (string, error)
 	HeadCommit     func() (string, error)
 	CurrentBranch  func() (string, error)
+	PreviousBranch func() (string, error)
 	CommitMessage  func() (string, error)
 	CommitAuthor   func() (string, error)
 
+	CountPushedCommits func(string, string, string, string) (int, error)
+	UnpushedCommits    func(string, string) ([]string, error)
+	CommitSubject      func(string, string) string
+	RootCommit         func(string) (string, error)
+
 	// repo
 	DeriveID func(string, string) (repodomain.RepoID, error)
 
@@ -36,14 +44,18 @@ type Deps struct {
 	ListEvents   func(*sql.DB, store.EventFilter) ([]store.RepoEvent, error)
 	MarkOrphaned func(repoID repodomain.RepoID) (int64, error)
 
+	RecordEvent func(*sql.DB, store.RepoEvent, string, []store.CommitRewrite, []store.PushRef) (int64, error)
+	SpoolPath   func() string
+
 	// io
 	Printf  func(string, ...any) (int, error)
 	Println func(...any) (int, error)
 	Pager   func(string)
 
 	// misc
-	Now    func() time.Time
-	Getenv func(string) string
+	Now       func() time.Time
+	Getenv    func(string) string
+	ReadStdin func() (string, error)
 
 	// export sync
 	GetExportRepo  func() string
@@ -61,9 +73,15 @@ func DefaultDeps() Deps {
 		GetRemoteURL:   git.GetRemoteURL,
 		HeadCommit:     git.HeadCommit,
 		CurrentBranch:  git.CurrentBranch,
+		PreviousBranch: git.PreviousBranch,
 		CommitMessage:  git.CommitMessage,
 		CommitAuthor:   git.CommitAuthor,
 
+		CountPushedCommits: git.CountPushedCommits,
+		UnpushedCommits:    git.UnpushedCommits,
+		CommitSubject:      commitSubject,
+		RootCommit:         git.RootCommit,
+
 		DeriveID: repodomain.DeriveID,
 
 		DBPath:       store.DBPath,
@@ -74,12 +92,16 @@ func DefaultDeps() Deps {
 		ListEvents:   store.ListEvents,
 		MarkOrphaned: markOrphanedWrapper,
 
+		RecordEvent: store.RecordEvent,
+		SpoolPath:   store.SpoolPath,
+
 		Printf:  ui.Printf,
 		Println: ui.Println,
 		Pager:   ui.Pager,
 
-		Now:    time.Now,
-		Getenv: os.Getenv,
+		Now:       time.Now,
+		Getenv:    os.Getenv,
+		ReadStdin: readStdin,
 
 		GetExportRepo:  getExportRepo,
 		HasRemote:      hasRemote,
@@ -100,6 +122,24 @@ func openDBFresh(path string) (*sql.DB, error) {
 	return s.DB(), nil
 }
 
+// readStdin reads everything piped to fp by git (e.g. post-rewrite pairs).
+// Returns empty when stdin is a terminal so manual runs never block.
+func readStdin() (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// commitSubject reads a commit's subject line from git, or "" if unavailable.
+func commitSubject(repoPath, commit string) string {
+	return git.GetCommitMetadata(repoPath, commit).Subject
+}
+
 // markOrphanedWrapper opens the database, marks events as orphaned, and closes.
 func markOrphanedWrapper(repoID repodomain.RepoID) (int64, error) {
 	s, err := store.New(store.DBPath())
@@ -5,11 +5,14 @@ import (
 	"database/sql"
 	"encoding/csv"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,6 +32,11 @@ const (
 	// CSV filename constants
 	activeCSVName = "commits.csv"
 
+	// CSV event_type values
+	eventTypeCommit = "commit"
+	eventTypeMerge  = "merge"
+	eventTypePush   = "push"
+
 	// Retry configuration for network operations
 	maxRetries     = 3
 	initialBackoff = 1 * time.Second
@@ -55,6 +63,10 @@ var csvHeader = []string{
 	"device",
 }
 
+// annotationColumns are appended to csvHeader when export_annotations is on.
+// Tags are joined with commas.
+var annotationColumns = []string{"tags", "note"}
+
 // Export handles the manual `fp export` command.
 func Export(args []string, flags *dispatchers.ParsedFlags) error {
 	return export(args, flags, DefaultDeps())
@@ -66,6 +78,16 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	openDir := flags.Has("--open")
 	jsonOutput := flags.Has("--json")
 
+	// Date ranges narrow the preview only: an export always takes every
+	// pending event, so none is left behind
+	since, until, err := dateRange(flags, deps.Now)
+	if err != nil {
+		return err
+	}
+	if (since != nil || until != nil) && !dryRun {
+		return errors.New("--since, --until and --during only apply with --dry-run")
+	}
+
 	exportRepo := getExportRepo()
 
 	// Handle --open flag
@@ -86,6 +108,9 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	if err != nil {
 		return fmt.Errorf("could not get pending events: %w", err)
 	}
+	if since != nil || until != nil {
+		events = eventsBetween(events, since, until)
+	}
 
 	if len(events) == 0 {
 		if jsonOutput {
@@ -146,7 +171,7 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
-	_, _ = deps.Printf("Exported %d events to %s\n", count, exportRepo)
+	_, _ = deps.Printf("Exported %d events to %s\n", count, exportDestination(exportRepo, deps))
 	if pushed {
 		_, _ = deps.Println("Pushed to remote")
 	}
@@ -155,6 +180,33 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	return nil
 }
 
+// exportDestination describes where an export went: the export repo, or
+// the configured sinks when export_sinks[] chooses others.
+func exportDestination(exportRepo string, deps Deps) string {
+	targets, err := configuredSinks(deps)
+	if err != nil || len(targets) == 1 && targets[0].sink.Name() == sinkCSVGit+":"+exportRepo {
+		return exportRepo
+	}
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.sink.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
+// eventsBetween keeps the events recorded from since to until, inclusive.
+// Either end may be nil.
+func eventsBetween(events []store.RepoEvent, since, until *time.Time) []store.RepoEvent {
+	var kept []store.RepoEvent
+	for _, e := range events {
+		if since != nil && e.Timestamp.Before(*since) || until != nil && e.Timestamp.After(*until) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
 func exportDryRunJSON(events []store.RepoEvent, deps Deps) error {
 	type eventJSON struct {
 		Commit    string `json:"commit"`
@@ -205,60 +257,129 @@ func exportResultJSON(count int, exportPath string, pushed bool, deps Deps) erro
 	return output.JSON(deps.Println, result)
 }
 
-// doExportWork performs the core export workflow: export events to CSV, commit, update DB.
+// doExportWork performs the core export workflow: hand the events to every
+// configured sink, then mark the ones all required sinks have as exported.
+// An event a required sink failed to take stays pending, and only the sinks
+// that lack it get it on the next export. Returns how many events were
+// written and whether the export repo was pushed.
 func doExportWork(db *sql.DB, events []store.RepoEvent, deps Deps) (int, bool, error) {
-	exportRepo := deps.GetExportRepo()
-
-	if err := ensureExportRepo(exportRepo); err != nil {
-		return 0, false, fmt.Errorf("could not initialize export repo: %w", err)
-	}
-
-	// Check for incomplete merge/rebase state before proceeding
-	if err := checkGitState(exportRepo); err != nil {
+	targets, err := configuredSinks(deps)
+	if err != nil {
 		return 0, false, err
 	}
 
-	// Sync with remote before writing (offline mode: continue if pull fails)
-	if deps.HasRemote(exportRepo) {
-		if err := deps.PullExportRepo(exportRepo); err != nil {
-			log.Warn("export: could not sync with remote, continuing offline: %v", err)
-		}
-	}
-
-	exportedIDs, exportedFiles, err := exportAllEvents(exportRepo, events, deps)
+	// Rewritten commits (amend/rebase) are replaced by their new commits:
+	// their pending events are skipped and rows already exported are dropped
+	superseded, err := store.ListSupersededCommits(db)
 	if err != nil {
-		return 0, false, fmt.Errorf("could not export events: %w", err)
+		log.Warn("export: could not list rewritten commits, exporting all events: %v", err)
+	}
+	events, skippedIDs := splitSuperseded(events, superseded)
+	if err := store.UpdateEventStatuses(db, skippedIDs, store.StatusSkipped); err != nil {
+		log.Warn("export: failed to mark rewritten events as skipped: %v", err)
+	} else if len(skippedIDs) > 0 {
+		log.Info("export: skipped %d rewritten events", len(skippedIDs))
+		_ = store.ClearSinkState(db, skippedIDs)
 	}
 
-	if len(exportedFiles) == 0 {
+	if len(events) == 0 {
 		return 0, false, nil
 	}
 
-	if err := commitExportChanges(exportRepo, exportedFiles); err != nil {
-		return 0, false, fmt.Errorf("could not commit export: %w", err)
+	// Snapshots taken at record time keep rows complete for clones that are gone
+	snapshots := completeSnapshots(events, loadMetadataSnapshots(db, events))
+
+	var annotations map[int64]store.Annotations
+	if exportAnnotations() {
+		annotations = loadAnnotations(db, events)
 	}
+	aliases := loadRepoAliases(db)
 
-	// Try to push if remote exists
+	ids := eventIDs(events)
+	written := make(map[int64]bool)
+	missing := make(map[int64]bool) // events a required sink lacks
 	pushed := false
-	hasRemote := deps.HasRemote(exportRepo)
-	if hasRemote {
-		if err := deps.PushExportRepo(exportRepo); err != nil {
-			// Push failed - don't mark events as exported so they'll be retried
-			// Return count of locally exported events but pushed=false
+	var failures []string
+
+	for _, target := range targets {
+		name := target.sink.Name()
+		done, err := store.SinkExportedEvents(db, name, ids)
+		if err != nil {
+			return 0, false, fmt.Errorf("could not read export state of %s: %w", name, err)
+		}
+
+		var todo []store.RepoEvent
+		for _, e := range events {
+			if !done[e.ID] {
+				todo = append(todo, e)
+			}
+		}
+		if len(todo) == 0 {
+			continue
+		}
+
+		err = target.sink.Export(ExportBatch{
+			Events:      todo,
+			Superseded:  superseded,
+			Snapshots:   snapshots,
+			Annotations: annotations,
+			Aliases:     aliases,
+		})
+		if sink, ok := target.sink.(*csvGitSink); ok && sink.pushed {
+			pushed = true
+		}
+
+		switch {
+		case err == nil:
+			todoIDs := eventIDs(todo)
+			if err := store.MarkSinkExported(db, name, todoIDs, deps.Now()); err != nil {
+				log.Warn("export: could not save export state of %s: %v", name, err)
+			}
+			for _, id := range todoIDs {
+				written[id] = true
+			}
+			continue
+		case errors.Is(err, errNotPushed):
+			// Committed locally: the rows are written, but the events stay
+			// pending so the next export pushes them
 			log.Warn("export: failed to push to remote, events will remain pending: %v", err)
-			return len(exportedIDs), false, nil
+			for _, e := range todo {
+				written[e.ID] = true
+			}
+		case target.optional:
+			log.Warn("export: optional sink %s failed: %v", name, err)
+			continue
+		default:
+			log.Error("export: sink %s failed, events will remain pending: %v", name, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		}
+		for _, e := range todo {
+			missing[e.ID] = true
 		}
-		pushed = true
 	}
 
-	// Only mark as exported after successful push (or if no remote)
+	// Only mark as exported once every required sink has the events.
 	// Note: If this fails, events remain PENDING and will be retried.
-	// The CSV deduplication logic prevents duplicate entries on re-export,
-	// making the system eventually consistent without requiring transactions.
+	// The sinks replace rows by key, which prevents duplicate entries on
+	// re-export, making the system eventually consistent without requiring
+	// transactions.
+	var exportedIDs []int64
+	for _, id := range ids {
+		if !missing[id] {
+			exportedIDs = append(exportedIDs, id)
+		}
+	}
 	if err := store.UpdateEventStatuses(db, exportedIDs, store.StatusExported); err != nil {
 		log.Error("export: failed to update event statuses, events will be retried: %v", err)
 		return 0, false, fmt.Errorf("could not update event statuses: %w", err)
 	}
+	if err := store.ClearSinkState(db, exportedIDs); err != nil {
+		log.Warn("export: could not clear export sink state: %v", err)
+	}
+
+	if len(failures) > 0 {
+		return len(written), pushed, fmt.Errorf("could not export events to %s", strings.Join(failures, "; "))
+	}
 
 	// Clean up orphaned events (from untracked repos)
 	if deleted, err := store.DeleteOrphanedEvents(db); err != nil {
@@ -269,7 +390,7 @@ func doExportWork(db *sql.DB, events []store.RepoEvent, deps Deps) (int, bool, e
 
 	_ = saveExportLast(deps.Now().Unix())
 
-	return len(exportedIDs), pushed, nil
+	return len(written), pushed, nil
 }
 
 // maybeExport checks if it's time to export and does so if needed.
@@ -279,6 +400,9 @@ func maybeExport(db *sql.DB, deps Deps) {
 		return
 	}
 
+	// Retention rides on the export interval so it never runs on every hook
+	defer applyRetention(db, deps)
+
 	events, err := store.GetPendingEvents(db)
 	if err != nil {
 		log.Error("export: failed to get pending events: %v", err)
@@ -305,10 +429,37 @@ func maybeExport(db *sql.DB, deps Deps) {
 	log.Info("export: auto-exported %d events", count)
 }
 
+// splitSuperseded separates events whose commit was rewritten from the rest.
+// Push events are kept: the push happened even if the commit was rewritten later.
+// Superseded is keyed by repo_id:commit. Returns the remaining events and the
+// IDs of the superseded ones.
+func splitSuperseded(events []store.RepoEvent, superseded map[string]bool) ([]store.RepoEvent, []int64) {
+	if len(superseded) == 0 {
+		return events, nil
+	}
+
+	kept := make([]store.RepoEvent, 0, len(events))
+	var skippedIDs []int64
+	for _, e := range events {
+		if e.Source != store.SourcePrePush && (e.SupersededBy != "" || superseded[e.RepoID+":"+e.Commit]) {
+			skippedIDs = append(skippedIDs, e.ID)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept, skippedIDs
+}
+
 // exportAllEvents exports all events to a flat CSV structure with year-based rotation.
 // Uses map-based deduplication: new records replace existing ones with same repo:commit.
+// Rows for superseded (rewritten) commits are removed from every file it touches.
+// Commit metadata comes from snapshots (repo_id:commit) when available, else from git.
+// Annotations fill the optional tags and note columns when export_annotations is on,
+// and repository aliases replace the folder name in repo_name.
 // Returns the IDs of exported events and the files that were modified.
-func exportAllEvents(exportRepo string, events []store.RepoEvent, deps Deps) ([]int64, []string, error) {
+func exportAllEvents(exportRepo string, events []store.RepoEvent, superseded map[string]bool, snapshots map[string]git.CommitMetadata, annotations map[int64]store.Annotations, aliases repoAliases, deps Deps) ([]int64, []string, error) {
+	withAnnotations := exportAnnotations()
+
 	// Build a map of repo paths for metadata enrichment
 	repoPaths := make(map[string]string)
 	for _, e := range events {
@@ -337,15 +488,23 @@ func exportAllEvents(exportRepo string, events []store.RepoEvent, deps Deps) ([]
 			return nil, nil, fmt.Errorf("could not load existing CSV %s: %w", csvPath, err)
 		}
 
+		// Drop rows for commits that were rewritten since they were exported
+		for key := range records {
+			if superseded[key] {
+				delete(records, key)
+			}
+		}
+
 		// Add/replace with new events
 		for _, e := range fileEvents {
-			var meta git.CommitMetadata
-			if repoPath, ok := repoPaths[e.RepoID]; ok {
-				meta = git.GetCommitMetadata(repoPath, e.Commit)
+			meta, ok := snapshots[e.RepoID+":"+e.Commit]
+			if !ok {
+				if repoPath, found := repoPaths[e.RepoID]; found {
+					meta = git.GetCommitMetadata(repoPath, e.Commit)
+				}
 			}
 
-			record := buildRecord(e, meta)
-			key := e.RepoID + ":" + e.Commit
+			key, record := eventRecord(e, meta, withAnnotations, annotations, aliases)
 			records[key] = record
 
 			exportedIDs = append(exportedIDs, e.ID)
@@ -363,6 +522,17 @@ func exportAllEvents(exportRepo string, events []store.RepoEvent, deps Deps) ([]
 	return exportedIDs, modifiedFiles, nil
 }
 
+// eventRecord builds the export row of an event and its deduplication key.
+// The annotation columns are added when withAnnotations is set.
+func eventRecord(e store.RepoEvent, meta git.CommitMetadata, withAnnotations bool, annotations map[int64]store.Annotations, aliases repoAliases) (string, []string) {
+	record := buildRecord(e, meta, aliases[e.RepoID])
+	if withAnnotations {
+		a := annotations[e.ID]
+		record = append(record, strings.Join(a.Tags, ","), a.Note)
+	}
+	return recordKey(e.RepoID, e.Commit, csvEventType(e, meta)), record
+}
+
 // getCSVPath returns the path to the CSV file for an event based on its year.
 func getCSVPath(exportRepo string, eventTime time.Time, currentYear int) string {
 	eventYear := eventTime.Year()
@@ -390,12 +560,59 @@ func findColumnIndices(header []string) (repoIdx, commitIdx int) {
 	return repoIdx, commitIdx
 }
 
+// findEventTypeIndex returns the index of the event_type column, or -1 if missing.
+func findEventTypeIndex(header []string) int {
+	for i, col := range header {
+		if col == "event_type" {
+			return i
+		}
+	}
+	return -1
+}
+
+// recordKey returns the deduplication key for a CSV row. Push rows get their
+// own key so they don't replace the row of the commit that was pushed.
+func recordKey(repoID, commit, eventType string) string {
+	key := repoID + ":" + commit
+	if eventType == eventTypePush {
+		key += ":" + eventTypePush
+	}
+	return key
+}
+
+// rowKey returns the deduplication key for a parsed CSV line.
+func rowKey(line []string, repoIdx, commitIdx, typeIdx int) string {
+	eventType := ""
+	if typeIdx >= 0 && typeIdx < len(line) {
+		eventType = line[typeIdx]
+	}
+	return recordKey(line[repoIdx], line[commitIdx], eventType)
+}
+
 // getDefaultColumnIndices returns the indices of repo_id and commit_hash from the canonical csvHeader.
 // This ensures fallback indices are always in sync with the schema definition.
 func getDefaultColumnIndices() (repoIdx, commitIdx int) {
 	return findColumnIndices(csvHeader)
 }
 
+// readCSVHeader returns the header row of a CSV file, nil when the file is empty.
+func readCSVHeader(csvPath string) ([]string, error) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return nil, fmt.Errorf("open CSV: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	header, err := csv.NewReader(file).Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse CSV: %w", err)
+	}
+	return header, nil
+}
+
 // loadCSVRecords loads existing CSV into a map keyed by repo:commit.
 // Returns an error if the file exists but cannot be parsed (to prevent data loss).
 func loadCSVRecords(csvPath string) (map[string][]string, error) {
@@ -427,6 +644,8 @@ func loadCSVRecords(csvPath string) (map[string][]string, error) {
 		repoIdx, commitIdx = getDefaultColumnIndices()
 	}
 
+	typeIdx := findEventTypeIndex(lines[0])
+
 	// Parse records (skip header)
 	maxIdx := max(repoIdx, commitIdx)
 
@@ -435,15 +654,15 @@ func loadCSVRecords(csvPath string) (map[string][]string, error) {
 			log.Warn("export: skipping malformed CSV line %d (expected at least %d columns)", i+2, maxIdx+1)
 			continue
 		}
-		key := line[repoIdx] + ":" + line[commitIdx]
-		records[key] = line
+		records[rowKey(line, repoIdx, commitIdx, typeIdx)] = line
 	}
 
 	return records, nil
 }
 
-// buildRecord creates a CSV record from an event and its metadata.
-func buildRecord(e store.RepoEvent, meta git.CommitMetadata) []string {
+// buildRecord creates a CSV record from an event and its metadata. The
+// repository alias, when set, is used as repo_name.
+func buildRecord(e store.RepoEvent, meta git.CommitMetadata, alias string) []string {
 	// Normalize message: replace newlines with spaces, remove carriage returns
 	message := strings.TrimSpace(strings.Map(func(r rune) rune {
 		switch r {
@@ -462,14 +681,13 @@ func buildRecord(e store.RepoEvent, meta git.CommitMetadata) []string {
 		timestamp = e.Timestamp.UTC().Format(time.RFC3339)
 	}
 
-	// Derive event_type from parent count (>1 parent = merge)
-	eventType := "commit"
-	if strings.Contains(meta.ParentCommits, " ") {
-		eventType = "merge"
-	}
+	eventType := csvEventType(e, meta)
 
-	// Derive repo_name from path
-	repoName := filepath.Base(e.RepoPath)
+	// Derive repo_name from the alias or path
+	repoName := alias
+	if repoName == "" {
+		repoName = filepath.Base(e.RepoPath)
+	}
 	if repoName == "" || repoName == "." {
 		repoName = e.RepoID
 	}
@@ -497,6 +715,18 @@ func buildRecord(e store.RepoEvent, meta git.CommitMetadata) []string {
 	}
 }
 
+// csvEventType derives the event_type column. Pushes are identified by their
+// source; otherwise more than one parent means a merge.
+func csvEventType(e store.RepoEvent, meta git.CommitMetadata) string {
+	if e.Source == store.SourcePrePush {
+		return eventTypePush
+	}
+	if strings.Contains(meta.ParentCommits, " ") {
+		return eventTypeMerge
+	}
+	return eventTypeCommit
+}
+
 // generateEventID creates a unique UUID for each event.
 func generateEventID() string {
 	return uuid.New().String()
@@ -544,17 +774,21 @@ func writeCSVSorted(csvPath string, records map[string][]string) error {
 	}
 
 	w := csv.NewWriter(file)
-	if err := w.Write(csvHeader); err != nil {
+	header := exportHeader()
+	if err := w.Write(header); err != nil {
 		_ = file.Close()
 		_ = os.Remove(tempPath)
 		return err
 	}
-	expectedFields := len(csvHeader)
+	expectedFields := len(header)
 	for _, line := range lines {
-		if len(line) != expectedFields {
+		if len(line) < len(csvHeader) {
 			log.Warn("export: record has %d fields, expected %d, skipping", len(line), expectedFields)
 			continue
 		}
+		// Rows written before export_annotations changed gain or lose
+		// the annotation columns
+		line = fitColumns(line, expectedFields)
 		if err := w.Write(line); err != nil {
 			_ = file.Close()
 			_ = os.Remove(tempPath)
@@ -590,6 +824,29 @@ func writeCSVSorted(csvPath string, records map[string][]string) error {
 	return nil
 }
 
+// exportHeader returns the CSV header, with the annotation columns when
+// export_annotations is on.
+func exportHeader() []string {
+	if !exportAnnotations() {
+		return csvHeader
+	}
+	return append(slices.Clone(csvHeader), annotationColumns...)
+}
+
+// exportAnnotations reports whether export_annotations is on.
+func exportAnnotations() bool {
+	value, _ := config.Get("export_annotations")
+	return value == "true"
+}
+
+// fitColumns pads a row with empty fields or cuts it to n fields.
+func fitColumns(line []string, n int) []string {
+	if len(line) >= n {
+		return line[:n]
+	}
+	return append(line, make([]string, n-len(line))...)
+}
+
 // checkDiskSpace verifies there's enough space to write the estimated bytes.
 func checkDiskSpace(dir string, requiredBytes int64) error {
 	var stat syscall.Statfs_t
@@ -995,14 +1252,14 @@ func parseCSVIntoMap(content string, records map[string][]string) {
 		repoIdx, commitIdx = getDefaultColumnIndices()
 	}
 
+	typeIdx := findEventTypeIndex(lines[0])
 	maxIdx := max(repoIdx, commitIdx)
 
 	for _, line := range lines[1:] {
 		if len(line) <= maxIdx {
 			continue // skip malformed lines
 		}
-		key := line[repoIdx] + ":" + line[commitIdx]
-		records[key] = line
+		records[rowKey(line, repoIdx, commitIdx, typeIdx)] = line
 	}
 }
 
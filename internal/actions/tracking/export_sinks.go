@@ -0,0 +1,413 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// exportSinksConfigKey is the .fprc array key choosing the export sinks.
+const exportSinksConfigKey = "export_sinks"
+
+// Export sink kinds, as named in export_sinks[]
+const (
+	sinkCSVGit = "csv-git"
+	sinkDir    = "dir"
+	sinkJSONL  = "jsonl"
+	sinkSQLite = "sqlite"
+)
+
+// Default file names of the sinks writing a single file into export_path
+const (
+	jsonlName  = "commits.jsonl"
+	sqliteName = "commits.db"
+)
+
+// ExportBatch is what one export hands to its sinks.
+type ExportBatch struct {
+	Events []store.RepoEvent
+
+	// Superseded holds the repo_id:commit of rewritten commits, whose rows
+	// sinks that keep one row per commit remove
+	Superseded map[string]bool
+
+	// Snapshots holds the metadata of every commit in Events that has any,
+	// keyed by repo_id:commit
+	Snapshots map[string]git.CommitMetadata
+
+	// Annotations is nil unless export_annotations is on
+	Annotations map[int64]store.Annotations
+
+	Aliases repoAliases
+}
+
+// ExportSink is a destination of fp export.
+type ExportSink interface {
+	// Name identifies the sink and where it writes, in messages and in the
+	// state kept for events some sinks have and others still lack
+	Name() string
+
+	// Export writes the events of a batch. An event may arrive again if
+	// the export stopped before its state was saved, so sinks replace rows
+	// by key where they can.
+	Export(batch ExportBatch) error
+}
+
+// exportTarget is a configured sink. Events are marked exported once every
+// required sink has them; optional sinks are best effort.
+type exportTarget struct {
+	sink     ExportSink
+	optional bool
+}
+
+// errNotPushed reports rows committed to the export repository that could
+// not be pushed. They are written, but the sink keeps them pending so the
+// next export pushes them.
+var errNotPushed = errors.New("could not push to remote")
+
+// csvGitSink writes the CSV files into a git repository, synced with
+// export_remote when one is set. It is the default sink.
+type csvGitSink struct {
+	path   string
+	deps   Deps
+	pushed bool
+}
+
+func (s *csvGitSink) Name() string { return sinkCSVGit + ":" + s.path }
+
+func (s *csvGitSink) Export(batch ExportBatch) error {
+	s.pushed = false
+
+	if err := ensureExportRepo(s.path); err != nil {
+		return fmt.Errorf("could not initialize export repo: %w", err)
+	}
+
+	// Check for incomplete merge/rebase state before proceeding
+	if err := checkGitState(s.path); err != nil {
+		return err
+	}
+
+	// Sync with remote before writing (offline mode: continue if pull fails)
+	if s.deps.HasRemote(s.path) {
+		if err := s.deps.PullExportRepo(s.path); err != nil {
+			log.Warn("export: could not sync with remote, continuing offline: %v", err)
+		}
+	}
+
+	_, files, err := exportAllEvents(s.path, batch.Events, batch.Superseded, batch.Snapshots, batch.Annotations, batch.Aliases, s.deps)
+	if err != nil {
+		return fmt.Errorf("could not export events: %w", err)
+	}
+
+	if err := commitExportChanges(s.path, files); err != nil {
+		return fmt.Errorf("could not commit export: %w", err)
+	}
+
+	if s.deps.HasRemote(s.path) {
+		if err := s.deps.PushExportRepo(s.path); err != nil {
+			return fmt.Errorf("%w: %v", errNotPushed, err)
+		}
+		s.pushed = true
+	}
+	return nil
+}
+
+// dirSink writes the same CSV files as csvGitSink into a plain directory.
+type dirSink struct {
+	path string
+	deps Deps
+}
+
+func (s *dirSink) Name() string { return sinkDir + ":" + s.path }
+
+func (s *dirSink) Export(batch ExportBatch) error {
+	if err := os.MkdirAll(s.path, 0700); err != nil {
+		return err
+	}
+	if _, _, err := exportAllEvents(s.path, batch.Events, batch.Superseded, batch.Snapshots, batch.Annotations, batch.Aliases, s.deps); err != nil {
+		return fmt.Errorf("could not export events: %w", err)
+	}
+	return nil
+}
+
+// jsonlSink appends one JSON object per event to a JSON Lines file, with
+// the CSV columns as fields, for ingestion elsewhere. The file is only
+// ever appended to: rows of rewritten commits are not removed.
+type jsonlSink struct {
+	path string
+}
+
+func (s *jsonlSink) Name() string { return sinkJSONL + ":" + s.path }
+
+func (s *jsonlSink) Export(batch ExportBatch) error {
+	header := exportHeader()
+	var buf bytes.Buffer
+	for _, r := range sortedRecords(batchRecords(batch)) {
+		line, err := jsonlRecord(header, r.record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("sync %s: %w", s.path, err)
+	}
+	return file.Close()
+}
+
+// sqliteSink keeps a standalone SQLite database with one row per export
+// row, replaced by key like the CSV rows, so the export can be queried.
+type sqliteSink struct {
+	path string
+}
+
+func (s *sqliteSink) Name() string { return sinkSQLite + ":" + s.path }
+
+func (s *sqliteSink) Export(batch ExportBatch) error {
+	header := exportHeader()
+	records := batchRecords(batch)
+	rows := make([]store.ExportRow, 0, len(records))
+	for _, r := range records {
+		record := fitColumns(r.record, len(header))
+		values := make([]any, len(header))
+		for i, column := range header {
+			values[i] = typedValue(column, record[i])
+		}
+		rows = append(rows, store.ExportRow{Key: r.key, Values: values})
+	}
+
+	drop := make([]string, 0, len(batch.Superseded))
+	for key := range batch.Superseded {
+		drop = append(drop, key)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return store.WriteExportSnapshot(s.path, header, rows, drop)
+}
+
+// exportRecord is the export row of one event.
+type exportRecord struct {
+	key    string
+	record []string
+}
+
+// batchRecords builds the export row of every event in a batch.
+func batchRecords(batch ExportBatch) []exportRecord {
+	withAnnotations := exportAnnotations()
+	records := make([]exportRecord, 0, len(batch.Events))
+	for _, e := range batch.Events {
+		meta := batch.Snapshots[e.RepoID+":"+e.Commit]
+		key, record := eventRecord(e, meta, withAnnotations, batch.Annotations, batch.Aliases)
+		records = append(records, exportRecord{key: key, record: record})
+	}
+	return records
+}
+
+// sortedRecords orders rows by timestamp, as the CSV files are.
+func sortedRecords(records []exportRecord) []exportRecord {
+	const timestampCol = 2
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].record[timestampCol] < records[j].record[timestampCol]
+	})
+	return records
+}
+
+// integerColumns are the export columns holding counts.
+var integerColumns = map[string]bool{
+	"files_changed": true,
+	"insertions":    true,
+	"deletions":     true,
+}
+
+// typedValue converts a CSV field to a number for the count columns.
+func typedValue(column, value string) any {
+	if integerColumns[column] {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+// jsonlRecord encodes a row as a JSON object with the fields in column
+// order. Tags become a list.
+func jsonlRecord(header, record []string) ([]byte, error) {
+	record = fitColumns(record, len(header))
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range header {
+		var value any = typedValue(column, record[i])
+		if column == "tags" {
+			tags := []string{}
+			if record[i] != "" {
+				tags = strings.Split(record[i], ",")
+			}
+			value = tags
+		}
+
+		name, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// parseExportSink parses an export_sinks value: a sink kind, ? after it to
+// make the sink best effort, and a path. The path defaults to export_path
+// for csv-git and dir, and to a file in it for jsonl and sqlite.
+func parseExportSink(value string, deps Deps) (exportTarget, error) {
+	kind, path, _ := strings.Cut(strings.TrimSpace(value), " ")
+	path = strings.TrimSpace(path)
+
+	var target exportTarget
+	if strings.HasSuffix(kind, "?") {
+		kind = strings.TrimSuffix(kind, "?")
+		target.optional = true
+	}
+	switch kind {
+	case sinkCSVGit, sinkDir, sinkJSONL, sinkSQLite:
+	default:
+		return exportTarget{}, fmt.Errorf("invalid export_sinks '%s': unknown sink '%s' (use %s, %s, %s or %s)",
+			value, kind, sinkCSVGit, sinkDir, sinkJSONL, sinkSQLite)
+	}
+
+	if path != "" {
+		if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/') {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return exportTarget{}, fmt.Errorf("invalid export_sinks '%s': %w", value, err)
+			}
+			path = home + rest
+		}
+		if !filepath.IsAbs(path) {
+			return exportTarget{}, fmt.Errorf("invalid export_sinks '%s': the path must be absolute", value)
+		}
+		path = filepath.Clean(path)
+	}
+
+	exportPath := deps.GetExportRepo()
+	switch kind {
+	case sinkCSVGit:
+		if path != "" && path != exportPath {
+			return exportTarget{}, fmt.Errorf("invalid export_sinks '%s': csv-git writes to export_path; set that instead", value)
+		}
+		target.sink = &csvGitSink{path: exportPath, deps: deps}
+	case sinkDir:
+		if path == "" {
+			path = exportPath
+		}
+		target.sink = &dirSink{path: path, deps: deps}
+	case sinkJSONL:
+		if path == "" {
+			path = filepath.Join(exportPath, jsonlName)
+		}
+		target.sink = &jsonlSink{path: path}
+	case sinkSQLite:
+		if path == "" {
+			path = filepath.Join(exportPath, sqliteName)
+		}
+		target.sink = &sqliteSink{path: path}
+	}
+	return target, nil
+}
+
+// configuredSinks returns the sinks chosen with export_sinks[], or csv-git
+// when none are. Unlike most settings, an invalid entry is an error: events
+// must not be marked exported without a sink that was meant to get them.
+func configuredSinks(deps Deps) ([]exportTarget, error) {
+	lines, err := config.ReadLines()
+	if err != nil {
+		return nil, fmt.Errorf("could not read export_sinks: %w", err)
+	}
+	values := config.ParseArray(lines, exportSinksConfigKey)
+	if len(values) == 0 {
+		return []exportTarget{{sink: &csvGitSink{path: deps.GetExportRepo(), deps: deps}}}, nil
+	}
+
+	var targets []exportTarget
+	seen := make(map[string]bool)
+	required := false
+	for _, value := range values {
+		target, err := parseExportSink(value, deps)
+		if err != nil {
+			return nil, err
+		}
+		name := target.sink.Name()
+		if seen[name] {
+			return nil, fmt.Errorf("invalid export_sinks: %s is listed twice", name)
+		}
+		seen[name] = true
+		required = required || !target.optional
+		targets = append(targets, target)
+	}
+	if !required {
+		return nil, errors.New("invalid export_sinks: at least one sink must be required (without ?)")
+	}
+	return targets, nil
+}
+
+// completeSnapshots adds git metadata for the commits without a stored
+// snapshot, so each commit is read once however many sinks write it.
+func completeSnapshots(events []store.RepoEvent, snapshots map[string]git.CommitMetadata) map[string]git.CommitMetadata {
+	complete := make(map[string]git.CommitMetadata, len(events))
+	for key, meta := range snapshots {
+		complete[key] = meta
+	}
+
+	repoPaths := make(map[string]string)
+	for _, e := range events {
+		if e.RepoPath != "" {
+			repoPaths[e.RepoID] = e.RepoPath
+		}
+	}
+
+	for _, e := range events {
+		key := e.RepoID + ":" + e.Commit
+		if _, ok := complete[key]; ok {
+			continue
+		}
+		if repoPath, found := repoPaths[e.RepoID]; found {
+			complete[key] = git.GetCommitMetadata(repoPath, e.Commit)
+		}
+	}
+	return complete
+}
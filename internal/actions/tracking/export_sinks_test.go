@@ -0,0 +1,197 @@
+package tracking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func sinkTestDeps(exportPath string) Deps {
+	return Deps{
+		Now:           func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) },
+		GetExportRepo: func() string { return exportPath },
+	}
+}
+
+func TestConfiguredSinks(t *testing.T) {
+	deps := sinkTestDeps("/data/exports")
+
+	useTestConfig(t, "")
+	targets, err := configuredSinks(deps)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	require.Equal(t, "csv-git:/data/exports", targets[0].sink.Name())
+
+	useTestConfig(t, strings.Join([]string{
+		"export_sinks[]=dir",
+		"export_sinks[]=jsonl /srv/ingest/fp.jsonl",
+		"export_sinks[]=sqlite? ~/fp/commits.db",
+	}, "\n"))
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	targets, err = configuredSinks(deps)
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+	require.Equal(t, "dir:/data/exports", targets[0].sink.Name())
+	require.Equal(t, "jsonl:/srv/ingest/fp.jsonl", targets[1].sink.Name())
+	require.False(t, targets[1].optional)
+	require.Equal(t, "sqlite:"+filepath.Join(home, "fp", "commits.db"), targets[2].sink.Name())
+	require.True(t, targets[2].optional)
+
+	tests := []struct {
+		lines string
+		err   string
+	}{
+		{"export_sinks[]=s3 bucket", "unknown sink 's3'"},
+		{"export_sinks[]=jsonl out.jsonl", "the path must be absolute"},
+		{"export_sinks[]=csv-git /elsewhere", "set that instead"},
+		{"export_sinks[]=jsonl\nexport_sinks[]=jsonl /data/exports/commits.jsonl", "listed twice"},
+		{"export_sinks[]=jsonl?", "at least one sink must be required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lines, func(t *testing.T) {
+			useTestConfig(t, tt.lines)
+			_, err := configuredSinks(deps)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestJSONLRecord(t *testing.T) {
+	line, err := jsonlRecord(
+		[]string{"commit_hash", "insertions", "tags", "note"},
+		[]string{"abc123", "12", "billable,review", ""},
+	)
+	require.NoError(t, err)
+	require.Equal(t, `{"commit_hash":"abc123","insertions":12,"tags":["billable","review"],"note":""}`, string(line))
+
+	line, err = jsonlRecord([]string{"commit_hash", "tags"}, []string{"abc123"})
+	require.NoError(t, err)
+	require.Equal(t, `{"commit_hash":"abc123","tags":[]}`, string(line))
+}
+
+// sinkTestDB creates a database with two pending events.
+func sinkTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := newTestStore(t).DB()
+	for i, commit := range []string{"aaa111", "bbb222"} {
+		// No clone path, so repo_name shows the repo ID
+		e := testEvent("github.com/user/repo", commit, time.Date(2025, 6, 15, 10, i, 0, 0, time.UTC))
+		e.RepoPath = ""
+		insertTestEvents(t, db, e)
+	}
+	return db
+}
+
+func TestDoExportWork_Sinks(t *testing.T) {
+	dir := t.TempDir()
+	useTestConfig(t, strings.Join([]string{
+		"export_sinks[]=dir " + filepath.Join(dir, "csv"),
+		"export_sinks[]=jsonl " + filepath.Join(dir, "out.jsonl"),
+		"export_sinks[]=sqlite " + filepath.Join(dir, "snapshot.db"),
+	}, "\n"))
+
+	db := sinkTestDB(t)
+	events, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+
+	count, pushed, err := doExportWork(db, events, sinkTestDeps(filepath.Join(dir, "unused")))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.False(t, pushed)
+
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	// No git repository is created
+	_, err = os.Stat(filepath.Join(dir, "unused"))
+	require.True(t, os.IsNotExist(err))
+
+	records, err := loadCSVRecords(filepath.Join(dir, "csv", activeCSVName))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	_, err = os.Stat(filepath.Join(dir, "csv", ".git"))
+	require.True(t, os.IsNotExist(err))
+
+	data, err := os.ReadFile(filepath.Join(dir, "out.jsonl"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	require.Equal(t, "aaa111", row["commit_hash"])
+	require.Equal(t, float64(0), row["insertions"])
+
+	snapshot, err := sql.Open("sqlite3", filepath.Join(dir, "snapshot.db"))
+	require.NoError(t, err)
+	defer func() { _ = snapshot.Close() }()
+	var n int
+	require.NoError(t, snapshot.QueryRow(`SELECT COUNT(*) FROM events WHERE repo_id = 'github.com/user/repo'`).Scan(&n))
+	require.Equal(t, 2, n)
+}
+
+func TestDoExportWork_RequiredSinkFails(t *testing.T) {
+	dir := t.TempDir()
+	csvDir := filepath.Join(dir, "csv")
+	jsonlPath := filepath.Join(dir, "out.jsonl")
+	useTestConfig(t, strings.Join([]string{
+		"export_sinks[]=dir " + csvDir,
+		"export_sinks[]=jsonl " + jsonlPath,
+		"export_sinks[]=sqlite? " + filepath.Join(dir, "blocker", "snapshot.db"),
+	}, "\n"))
+	deps := sinkTestDeps(filepath.Join(dir, "unused"))
+
+	// A directory where the JSON Lines file should be makes that sink
+	// fail, and a file where the snapshot's folder should be the other
+	require.NoError(t, os.Mkdir(jsonlPath, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blocker"), nil, 0600))
+
+	db := sinkTestDB(t)
+	events, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+
+	count, _, err := doExportWork(db, events, deps)
+	require.ErrorContains(t, err, "jsonl:"+jsonlPath)
+	require.Equal(t, 2, count)
+
+	// The events stay pending, and the dir sink remembers it has them
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	done, err := store.SinkExportedEvents(db, "dir:"+csvDir, eventIDs(pending))
+	require.NoError(t, err)
+	require.Len(t, done, 2)
+
+	// Once the sink works again only it gets the events; the optional
+	// sink still failing does not hold them back
+	require.NoError(t, os.Remove(jsonlPath))
+	require.NoError(t, os.RemoveAll(csvDir))
+
+	count, _, err = doExportWork(db, pending, deps)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	pending, err = store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	_, err = os.Stat(csvDir)
+	require.True(t, os.IsNotExist(err), "the dir sink already had the events")
+
+	data, err := os.ReadFile(jsonlPath)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 2)
+
+	done, err = store.SinkExportedEvents(db, "dir:"+csvDir, eventIDs(events))
+	require.NoError(t, err)
+	require.Empty(t, done, "state is cleared once events are exported")
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/stretchr/testify/require"
@@ -115,7 +116,7 @@ func TestBuildRecord_CreatesCorrectFormat(t *testing.T) {
 		Subject:        "Fix bug",
 	}
 
-	record := buildRecord(event, meta)
+	record := buildRecord(event, meta, "")
 
 	require.Len(t, record, 16)
 	require.NotEmpty(t, record[colEventID])                        // UUID generated
@@ -137,7 +138,7 @@ func TestBuildRecord_SanitizesNewlines(t *testing.T) {
 		Subject: "Line 1\nLine 2\rLine 3",
 	}
 
-	record := buildRecord(event, meta)
+	record := buildRecord(event, meta, "")
 
 	// \n becomes space, \r is removed
 	require.Equal(t, "Line 1 Line 2Line 3", record[colMessage])
@@ -285,7 +286,7 @@ func TestExportAllEvents_SortsAndGroupsByYear(t *testing.T) {
 		},
 	}
 
-	ids, files, err := exportAllEvents(exportDir, events, deps)
+	ids, files, err := exportAllEvents(exportDir, events, nil, nil, nil, nil, deps)
 
 	require.NoError(t, err)
 	require.Len(t, ids, 3)
@@ -329,7 +330,7 @@ func TestExportAllEvents_MultipleReposInSameFile(t *testing.T) {
 		},
 	}
 
-	ids, files, err := exportAllEvents(exportDir, events, deps)
+	ids, files, err := exportAllEvents(exportDir, events, nil, nil, nil, nil, deps)
 
 	require.NoError(t, err)
 	require.Len(t, ids, 2)
@@ -392,7 +393,7 @@ func TestExportAllEvents_EventsAreSortedByAuthoredAt(t *testing.T) {
 		},
 	}
 
-	_, _, err = exportAllEvents(exportDir, events, deps)
+	_, _, err = exportAllEvents(exportDir, events, nil, nil, nil, nil, deps)
 	require.NoError(t, err)
 
 	file, err := os.Open(filepath.Join(exportDir, "commits.csv"))
@@ -421,7 +422,7 @@ func TestExportAllEvents_EmptyEvents(t *testing.T) {
 		},
 	}
 
-	ids, files, err := exportAllEvents(exportDir, []store.RepoEvent{}, deps)
+	ids, files, err := exportAllEvents(exportDir, []store.RepoEvent{}, nil, nil, nil, nil, deps)
 
 	require.NoError(t, err)
 	require.Empty(t, ids)
@@ -459,7 +460,7 @@ func TestExportAllEvents_YearBoundary(t *testing.T) {
 		},
 	}
 
-	ids, files, err := exportAllEvents(exportDir, events, deps)
+	ids, files, err := exportAllEvents(exportDir, events, nil, nil, nil, nil, deps)
 
 	require.NoError(t, err)
 	require.Len(t, ids, 2)
@@ -510,7 +511,7 @@ func TestExportAllEvents_PreservesExistingRecords(t *testing.T) {
 		},
 	}
 
-	_, _, err = exportAllEvents(exportDir, events1, deps)
+	_, _, err = exportAllEvents(exportDir, events1, nil, nil, nil, nil, deps)
 	require.NoError(t, err)
 
 	// Second batch
@@ -525,7 +526,7 @@ func TestExportAllEvents_PreservesExistingRecords(t *testing.T) {
 		},
 	}
 
-	_, _, err = exportAllEvents(exportDir, events2, deps)
+	_, _, err = exportAllEvents(exportDir, events2, nil, nil, nil, nil, deps)
 	require.NoError(t, err)
 
 	// Verify both commits are present
@@ -571,7 +572,7 @@ func TestExportAllEvents_ReplacesDuplicates(t *testing.T) {
 		},
 	}
 
-	_, _, err = exportAllEvents(exportDir, events1, deps)
+	_, _, err = exportAllEvents(exportDir, events1, nil, nil, nil, nil, deps)
 	require.NoError(t, err)
 
 	// Second export with same repo:commit (should replace)
@@ -586,7 +587,7 @@ func TestExportAllEvents_ReplacesDuplicates(t *testing.T) {
 		},
 	}
 
-	_, _, err = exportAllEvents(exportDir, events2, deps)
+	_, _, err = exportAllEvents(exportDir, events2, nil, nil, nil, nil, deps)
 	require.NoError(t, err)
 
 	// Verify only one record exists and it's the newer one
@@ -602,6 +603,38 @@ func TestExportAllEvents_ReplacesDuplicates(t *testing.T) {
 	require.Equal(t, "feature", records[1][colBranch]) // branch from second export
 }
 
+func TestExportAllEvents_AnnotationColumns(t *testing.T) {
+	useTestConfig(t, "export_annotations=true\n")
+	exportDir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+
+	events := []store.RepoEvent{{
+		ID:        1,
+		RepoID:    "github.com/user/repo",
+		Commit:    "abc123",
+		Branch:    "main",
+		Timestamp: time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC),
+		Source:    store.SourcePostCommit,
+	}}
+	annotations := map[int64]store.Annotations{
+		1: {Tags: []string{"billable", "incident"}, Note: "Paged at 3am"},
+	}
+	deps := Deps{Now: func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) }}
+
+	_, _, err := exportAllEvents(exportDir, events, nil, nil, annotations, nil, deps)
+	require.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(exportDir, "commits.csv"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 2)
+	require.Equal(t, []string{"tags", "note"}, records[0][colDevice+1:])
+	require.Equal(t, []string{"billable,incident", "Paged at 3am"}, records[1][colDevice+1:])
+}
+
 func TestParseCSVIntoMap_LastWriteWins(t *testing.T) {
 	records := make(map[string][]string)
 
@@ -901,3 +934,179 @@ func TestParseCSVIntoMap_HeaderOnly(t *testing.T) {
 
 	require.Empty(t, records)
 }
+
+func TestExportAllEvents_DropsSupersededRows(t *testing.T) {
+	dir := t.TempDir()
+	exportDir := filepath.Join(dir, "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+
+	deps := Deps{
+		Now: func() time.Time {
+			return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+
+	// Original commit exported before it was amended
+	_, _, err := exportAllEvents(exportDir, []store.RepoEvent{
+		{
+			ID:        1,
+			RepoID:    "github.com/user/repo",
+			Commit:    "old111",
+			Branch:    "main",
+			Timestamp: time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC),
+			Source:    store.SourcePostCommit,
+		},
+	}, nil, nil, nil, nil, deps)
+	require.NoError(t, err)
+
+	// Amended commit replaces it
+	superseded := map[string]bool{"github.com/user/repo:old111": true}
+	_, _, err = exportAllEvents(exportDir, []store.RepoEvent{
+		{
+			ID:        2,
+			RepoID:    "github.com/user/repo",
+			Commit:    "new222",
+			Branch:    "main",
+			Timestamp: time.Date(2025, 6, 10, 10, 5, 0, 0, time.UTC),
+			Source:    store.SourcePostRewrite,
+		},
+	}, superseded, nil, nil, nil, deps)
+	require.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(exportDir, "commits.csv"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "new222", records[1][colCommitHash])
+}
+
+func TestSplitSuperseded(t *testing.T) {
+	events := []store.RepoEvent{
+		{ID: 1, RepoID: "r", Commit: "old"},
+		{ID: 2, RepoID: "r", Commit: "new"},
+		{ID: 3, RepoID: "r", Commit: "other", SupersededBy: "x"},
+	}
+
+	kept, skipped := splitSuperseded(events, map[string]bool{"r:old": true, "r:other": true})
+	require.Len(t, kept, 1)
+	require.Equal(t, "new", kept[0].Commit)
+	require.Equal(t, []int64{1, 3}, skipped)
+
+	kept, skipped = splitSuperseded(events, nil)
+	require.Len(t, kept, 3)
+	require.Empty(t, skipped)
+}
+
+func TestCSVEventType(t *testing.T) {
+	push := store.RepoEvent{Source: store.SourcePrePush}
+	require.Equal(t, "push", csvEventType(push, git.CommitMetadata{ParentCommits: "a b"}))
+
+	merge := store.RepoEvent{Source: store.SourcePostMerge}
+	require.Equal(t, "merge", csvEventType(merge, git.CommitMetadata{ParentCommits: "a b"}))
+
+	commit := store.RepoEvent{Source: store.SourcePostCommit}
+	require.Equal(t, "commit", csvEventType(commit, git.CommitMetadata{ParentCommits: "a"}))
+}
+
+func TestExportAllEvents_PushRowKeepsCommitRow(t *testing.T) {
+	dir := t.TempDir()
+	exportDir := filepath.Join(dir, "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+
+	deps := Deps{
+		Now: func() time.Time {
+			return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+
+	ts := time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC)
+	_, _, err := exportAllEvents(exportDir, []store.RepoEvent{
+		{ID: 1, RepoID: "github.com/user/repo", Commit: "abc123", Branch: "main", Timestamp: ts, Source: store.SourcePostCommit},
+	}, nil, nil, nil, nil, deps)
+	require.NoError(t, err)
+
+	// Pushing the same commit in a later export adds a separate row
+	_, _, err = exportAllEvents(exportDir, []store.RepoEvent{
+		{ID: 2, RepoID: "github.com/user/repo", Commit: "abc123", Branch: "main", Timestamp: ts.Add(time.Minute), Source: store.SourcePrePush},
+	}, nil, nil, nil, nil, deps)
+	require.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(exportDir, "commits.csv"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	types := []string{records[1][colEventType], records[2][colEventType]}
+	require.ElementsMatch(t, []string{"commit", "push"}, types)
+}
+
+func TestExportAllEvents_UsesMetadataSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	exportDir := filepath.Join(dir, "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+
+	deps := Deps{
+		Now: func() time.Time {
+			return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+
+	// The clone no longer exists, so only the snapshot has the metadata
+	snapshots := map[string]git.CommitMetadata{
+		"github.com/user/repo:abc123": {
+			AuthorName:   "Test User",
+			AuthorEmail:  "test@example.com",
+			AuthoredAt:   "2025-06-10T10:00:00Z",
+			Subject:      "Fix a bug",
+			FilesChanged: 2,
+		},
+	}
+	_, _, err := exportAllEvents(exportDir, []store.RepoEvent{
+		{
+			ID:        1,
+			RepoID:    "github.com/user/repo",
+			RepoPath:  filepath.Join(dir, "deleted-clone"),
+			Commit:    "abc123",
+			Branch:    "main",
+			Timestamp: time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC),
+			Source:    store.SourcePostCommit,
+		},
+	}, nil, snapshots, nil, nil, deps)
+	require.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(exportDir, "commits.csv"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "Test User", records[1][6])
+	require.Equal(t, "Fix a bug", records[1][11])
+	require.Equal(t, "2", records[1][12])
+}
+
+func TestEventsBetween(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2025, 3, day, 12, 0, 0, 0, time.UTC) }
+	events := []store.RepoEvent{{ID: 1, Timestamp: at(1)}, {ID: 2, Timestamp: at(5)}, {ID: 3, Timestamp: at(9)}}
+
+	since, until := at(5), at(9).Add(-time.Hour)
+	require.Equal(t, []store.RepoEvent{events[1]}, eventsBetween(events, &since, &until))
+	require.Equal(t, events[1:], eventsBetween(events, &since, nil))
+	require.Equal(t, events[:2], eventsBetween(events, nil, &until))
+}
+
+func TestExport_DateRangeNeedsDryRun(t *testing.T) {
+	deps := Deps{Now: time.Now}
+	err := export(nil, dispatchers.NewParsedFlags([]string{"--since=yesterday"}), deps)
+	require.EqualError(t, err, "--since, --until and --during only apply with --dry-run")
+
+	err = export(nil, dispatchers.NewParsedFlags([]string{"--dry-run", "--during=someday"}), deps)
+	require.ErrorContains(t, err, "invalid --during: unrecognized date 'someday'")
+}
@@ -2,6 +2,7 @@ package tracking
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/footprint-tools/cli/internal/format"
 	"github.com/footprint-tools/cli/internal/git"
@@ -24,28 +25,145 @@ var sourceStylers = map[store.Source]func(string) string{
 	store.SourceManual:       style.Color7,
 }
 
-// formatEvent formats a single event for display.
-func formatEvent(e store.RepoEvent, oneline bool) string {
+// formatEvent formats a single event for display, naming the repository by
+// its alias when it has one.
+func formatEvent(e store.RepoEvent, aliases repoAliases, oneline bool) string {
 	if oneline {
 		// source(colored) commit(bold) repo(muted) branch
 		return fmt.Sprintf(
 			"%s %s %s %s",
 			formatSource(e.Source),
 			style.Header(fmt.Sprintf("%.7s", e.Commit)),
-			style.Muted(e.RepoID),
+			style.Muted(aliases.id(e.RepoID)),
 			e.Branch,
-		)
+		) + deviceSuffix(e) + onelineCheckoutSuffix(e)
 	}
 
 	// Multi-line format
 	return fmt.Sprintf(
-		"%s %s %s %s\n%s\n",
+		"%s %s %s %s%s\n%s\n",
 		formatSource(e.Source),
 		style.Header(fmt.Sprintf("%.7s", e.Commit)),
 		e.Branch,
-		style.Muted(e.RepoID),
+		style.Muted(aliases.id(e.RepoID)),
+		deviceSuffix(e),
 		style.Muted(format.Full(e.Timestamp)),
-	)
+	) + multilineCheckoutLine(e)
+}
+
+// deviceSuffix names the device an imported event was recorded on,
+// e.g. " @work-laptop". Events recorded here have no device.
+func deviceSuffix(e store.RepoEvent) string {
+	if e.Device == "" {
+		return ""
+	}
+	return " " + style.Muted("@"+e.Device)
+}
+
+// checkoutSummary describes the checkout context of a post-checkout event,
+// e.g. "switched from main". Returns "" when there is nothing to show.
+func checkoutSummary(e store.RepoEvent) string {
+	if e.Source != store.SourcePostCheckout {
+		return ""
+	}
+	if !e.BranchSwitch {
+		// Events recorded before hooks forwarded arguments have no context
+		if e.PreviousCommit == "" {
+			return ""
+		}
+		return "file checkout"
+	}
+	from := e.PreviousBranch
+	if from == "" && e.PreviousCommit != "" {
+		from = fmt.Sprintf("%.7s", e.PreviousCommit)
+	}
+	if from == "" {
+		return "switched branch"
+	}
+	return "switched from " + from
+}
+
+func onelineCheckoutSuffix(e store.RepoEvent) string {
+	if summary := checkoutSummary(e); summary != "" {
+		return " " + style.Muted("("+summary+")")
+	}
+	return ""
+}
+
+func multilineCheckoutLine(e store.RepoEvent) string {
+	if summary := checkoutSummary(e); summary != "" {
+		return style.Muted(summary) + "\n"
+	}
+	return ""
+}
+
+// formatRewrites describes rewrite context for `fp activity --show-rewrites`:
+// the commit that replaced a superseded event and the old -> new pairs of a
+// post-rewrite event. Returns "" when there is nothing to show.
+func formatRewrites(e store.RepoEvent, rewrites []store.CommitRewrite, oneline bool) string {
+	var lines []string
+	if e.SupersededBy != "" {
+		lines = append(lines, style.Muted(fmt.Sprintf("rewritten as %.7s", e.SupersededBy)))
+	}
+	for _, rw := range rewrites {
+		lines = append(lines, style.Muted(fmt.Sprintf("%s %.7s -> %.7s", rw.RewriteType, rw.OldCommit, rw.NewCommit)))
+	}
+	return formatContextLines(lines, oneline)
+}
+
+// formatPushRefs describes the ref updates of a pre-push event,
+// e.g. "origin refs/heads/main: 3 commits (abc1234..def5678)".
+func formatPushRefs(refs []store.PushRef, oneline bool) string {
+	var lines []string
+	for _, ref := range refs {
+		var change string
+		switch {
+		case git.IsZeroSHA(ref.LocalSHA):
+			change = "deleted"
+		case git.IsZeroSHA(ref.RemoteSHA):
+			change = fmt.Sprintf("%s (new, %.7s)", pluralize(ref.CommitCount, "commit"), ref.LocalSHA)
+		default:
+			change = fmt.Sprintf("%s (%.7s..%.7s)", pluralize(ref.CommitCount, "commit"), ref.RemoteSHA, ref.LocalSHA)
+		}
+		lines = append(lines, style.Muted(fmt.Sprintf("%s %s: %s", ref.RemoteName, ref.RemoteRef, change)))
+	}
+	return formatContextLines(lines, oneline)
+}
+
+// formatAnnotations shows the tags and note of an event below it,
+// e.g. "#billable #incident" and the note text.
+func formatAnnotations(a store.Annotations, oneline bool) string {
+	var lines []string
+	if len(a.Tags) > 0 {
+		lines = append(lines, style.Info("#"+strings.Join(a.Tags, " #")))
+	}
+	if a.Note != "" {
+		for _, line := range strings.Split(a.Note, "\n") {
+			lines = append(lines, style.Muted(line))
+		}
+	}
+	return formatContextLines(lines, oneline)
+}
+
+// formatContextLines indents extra detail lines below an event.
+// Oneline output has no trailing newline, so the lines are prefixed instead.
+func formatContextLines(lines []string, oneline bool) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	if oneline {
+		return "\n    " + strings.Join(lines, "\n    ")
+	}
+	return "    " + strings.Join(lines, "\n    ") + "\n"
+}
+
+// pluralize formats a count with a singular or plural noun ("1 commit", "3 commits").
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
 }
 
 func formatSource(source store.Source) string {
@@ -57,7 +175,7 @@ func formatSource(source store.Source) string {
 
 // formatEventEnriched formats a single event with git metadata (author, commit message).
 // If oneline is true, uses compact single-line format with truncated subject.
-func formatEventEnriched(e store.RepoEvent, meta git.CommitMetadata, oneline bool) string {
+func formatEventEnriched(e store.RepoEvent, meta git.CommitMetadata, aliases repoAliases, oneline bool) string {
 	if oneline {
 		// source commit repo branch "message"
 		subject := meta.Subject
@@ -67,19 +185,21 @@ func formatEventEnriched(e store.RepoEvent, meta git.CommitMetadata, oneline boo
 		return fmt.Sprintf("%s %s %s %s %s",
 			formatSource(e.Source),
 			style.Header(fmt.Sprintf("%.7s", e.Commit)),
-			style.Muted(e.RepoID),
+			style.Muted(aliases.id(e.RepoID)),
 			e.Branch,
 			style.Muted(fmt.Sprintf("\"%s\"", subject)),
-		)
+		) + deviceSuffix(e) + onelineCheckoutSuffix(e)
 	}
 
 	// Multiline enriched
-	return fmt.Sprintf("%s %s %s %s\n%s\n%s <%s>\n\n    %s\n",
+	return fmt.Sprintf("%s %s %s %s%s\n%s\n%s%s <%s>\n\n    %s\n",
 		formatSource(e.Source),
 		style.Header(fmt.Sprintf("%.7s", e.Commit)),
 		e.Branch,
-		style.Muted(e.RepoID),
+		style.Muted(aliases.id(e.RepoID)),
+		deviceSuffix(e),
 		style.Muted(format.Full(e.Timestamp)),
+		multilineCheckoutLine(e),
 		meta.AuthorName,
 		meta.AuthorEmail,
 		meta.Subject,
@@ -20,7 +20,7 @@ func TestFormatEvent_Oneline(t *testing.T) {
 		Timestamp: time.Now(),
 	}
 
-	output := formatEvent(event, true)
+	output := formatEvent(event, nil, true)
 
 	require.Contains(t, output, "abc1234")
 	require.Contains(t, output, "main")
@@ -37,7 +37,7 @@ func TestFormatEvent_Multiline(t *testing.T) {
 		Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 	}
 
-	output := formatEvent(event, false)
+	output := formatEvent(event, nil, false)
 
 	require.Contains(t, output, "abc1234")
 	require.Contains(t, output, "main")
@@ -90,7 +90,7 @@ func TestFormatEventEnriched_Oneline(t *testing.T) {
 		Subject:     "Fix a bug in the code",
 	}
 
-	output := formatEventEnriched(event, meta, true)
+	output := formatEventEnriched(event, meta, nil, true)
 
 	require.Contains(t, output, "abc1234")
 	require.Contains(t, output, "github.com/test/repo")
@@ -113,7 +113,7 @@ func TestFormatEventEnriched_Oneline_LongSubject(t *testing.T) {
 		Subject:     "This is a very long commit message that should be truncated for display purposes",
 	}
 
-	output := formatEventEnriched(event, meta, true)
+	output := formatEventEnriched(event, meta, nil, true)
 
 	// Should be truncated with ...
 	require.Contains(t, output, "...")
@@ -135,7 +135,7 @@ func TestFormatEventEnriched_Multiline(t *testing.T) {
 		Subject:     "Fix a bug",
 	}
 
-	output := formatEventEnriched(event, meta, false)
+	output := formatEventEnriched(event, meta, nil, false)
 
 	require.Contains(t, output, "abc1234")
 	require.Contains(t, output, "github.com/test/repo")
@@ -146,3 +146,74 @@ func TestFormatEventEnriched_Multiline(t *testing.T) {
 	require.Contains(t, output, "Jan")
 	require.Contains(t, output, "15")
 }
+
+func TestCheckoutSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		event store.RepoEvent
+		want  string
+	}{
+		{
+			name:  "not a checkout",
+			event: store.RepoEvent{Source: store.SourcePostCommit, PreviousBranch: "main", BranchSwitch: true},
+			want:  "",
+		},
+		{
+			name:  "branch switch with previous branch",
+			event: store.RepoEvent{Source: store.SourcePostCheckout, PreviousCommit: "abc1234567890", PreviousBranch: "main", BranchSwitch: true},
+			want:  "switched from main",
+		},
+		{
+			name:  "branch switch from detached head",
+			event: store.RepoEvent{Source: store.SourcePostCheckout, PreviousCommit: "abc1234567890", BranchSwitch: true},
+			want:  "switched from abc1234",
+		},
+		{
+			name:  "file checkout",
+			event: store.RepoEvent{Source: store.SourcePostCheckout, PreviousCommit: "abc1234567890"},
+			want:  "file checkout",
+		},
+		{
+			name:  "no recorded context",
+			event: store.RepoEvent{Source: store.SourcePostCheckout},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, checkoutSummary(tt.event))
+		})
+	}
+}
+
+func TestFormatEvent_CheckoutContext(t *testing.T) {
+	event := store.RepoEvent{
+		RepoID:         "github.com/test/repo",
+		Commit:         "abc1234567890",
+		Branch:         "feature",
+		Source:         store.SourcePostCheckout,
+		Timestamp:      time.Now(),
+		PreviousCommit: "def4567890123",
+		PreviousBranch: "main",
+		BranchSwitch:   true,
+	}
+
+	require.Contains(t, formatEvent(event, nil, true), "switched from main")
+	require.Contains(t, formatEvent(event, nil, false), "switched from main")
+}
+
+func TestFormatPushRefs(t *testing.T) {
+	refs := []store.PushRef{
+		{RemoteName: "origin", RemoteRef: "refs/heads/main", LocalSHA: "bbb2222222", RemoteSHA: "aaa1111111", CommitCount: 3},
+		{RemoteName: "origin", RemoteRef: "refs/heads/topic", LocalSHA: "ccc3333333", RemoteSHA: "0000000000000000000000000000000000000000", CommitCount: 1},
+		{RemoteName: "origin", RemoteRef: "refs/heads/old", LocalSHA: "0000000000000000000000000000000000000000", RemoteSHA: "ddd4444444"},
+	}
+
+	output := formatPushRefs(refs, false)
+
+	require.Contains(t, output, "origin refs/heads/main: 3 commits (aaa1111..bbb2222)")
+	require.Contains(t, output, "origin refs/heads/topic: 1 commit (new, ccc3333)")
+	require.Contains(t, output, "origin refs/heads/old: deleted")
+	require.Empty(t, formatPushRefs(nil, true))
+}
@@ -0,0 +1,112 @@
+package tracking
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/heatmap"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// Heatmap draws a contribution calendar of daily events: the last year by
+// default, or a calendar year with --year.
+func Heatmap(args []string, flags *dispatchers.ParsedFlags) error {
+	return showHeatmap(args, flags, DefaultDeps())
+}
+
+func showHeatmap(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	// The store counts events per local day, so the grid is in local time
+	from, to, err := heatmapPeriod(flags.String("--year", ""), deps.Now().Local())
+	if err != nil {
+		return err
+	}
+
+	// The whole last day counts
+	until := to.AddDate(0, 0, 1).Add(-time.Second)
+	filter := store.EventFilter{Since: &from, Until: &until, ExcludeSuperseded: true}
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	days, err := store.EventStats(db, filter, store.StatsByDay)
+	if err != nil {
+		return fmt.Errorf("failed to count events: %w", err)
+	}
+	counts := make(map[string]int, len(days))
+	for _, d := range days {
+		counts[d.Key] = d.Events
+	}
+
+	grid := heatmap.New(counts, from, to)
+	glyphs := heatmapGlyphs()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s %s to %s\n\n", style.Header("Activity"), from.Format("2006-01-02"), to.Format("2006-01-02"))
+	for _, line := range grid.Render(glyphs, style.Heat) {
+		out.WriteString(line + "\n")
+	}
+	out.WriteString("\n")
+
+	summary := fmt.Sprintf("%s on %s", pluralize(grid.Total, "event"), pluralize(grid.ActiveDays, "day"))
+	if grid.Max > 0 {
+		summary += fmt.Sprintf(", busiest %s (%d)", grid.MaxDay.Format("2006-01-02"), grid.Max)
+	}
+	fmt.Fprintf(&out, "%s    %s\n", summary, style.Muted(heatmap.Legend(glyphs, style.Heat)))
+
+	_, _ = deps.Printf("%s", out.String())
+	return nil
+}
+
+// heatmapGlyphs returns the shaded blocks, or ASCII when styling is off so
+// the map still reads under --no-color and in pipes.
+func heatmapGlyphs() [heatmap.Levels + 1]string {
+	if style.Enabled() {
+		return heatmap.Glyphs
+	}
+	return heatmap.ASCII
+}
+
+// heatmapPeriod returns the days to draw: a calendar year up to today, or
+// the 53 weeks ending today.
+func heatmapPeriod(year string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if year == "" {
+		// Whole weeks, starting on Monday, with this week last
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return monday.AddDate(0, 0, -52*7), today, nil
+	}
+
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 1970 || y > now.Year() {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --year '%s': expected a year from 1970 to %d", year, now.Year())
+	}
+	from := time.Date(y, time.January, 1, 0, 0, 0, 0, now.Location())
+	to := time.Date(y, time.December, 31, 0, 0, 0, 0, now.Location())
+	if to.After(today) {
+		to = today
+	}
+	return from, to, nil
+}
@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestHeatmapPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 13, 16, 0, 0, 0, time.UTC) // a Thursday
+
+	from, to, err := heatmapPeriod("", now)
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-11", from.Format("2006-01-02"), "53 weeks starting on a Monday")
+	require.Equal(t, "2025-03-13", to.Format("2006-01-02"))
+
+	from, to, err = heatmapPeriod("2024", now)
+	require.NoError(t, err)
+	require.Equal(t, "2024-01-01", from.Format("2006-01-02"))
+	require.Equal(t, "2024-12-31", to.Format("2006-01-02"))
+
+	_, to, err = heatmapPeriod("2025", now)
+	require.NoError(t, err)
+	require.Equal(t, "2025-03-13", to.Format("2006-01-02"), "the current year stops today")
+
+	_, _, err = heatmapPeriod("2030", now)
+	require.ErrorContains(t, err, "invalid --year '2030'")
+
+	_, _, err = heatmapPeriod("last", now)
+	require.ErrorContains(t, err, "invalid --year 'last'")
+}
+
+// heatmapTestDeps seeds events at local times, as the heatmap counts local
+// days, with the clock on Thursday 2025-03-13.
+func heatmapTestDeps(t *testing.T, out *[]string) Deps {
+	t.Helper()
+	s := newTestStore(t)
+	event := func(repoID, commit string, hour, minute int, source store.Source) store.RepoEvent {
+		e := testEvent(repoID, commit, time.Date(2025, 3, 10, hour, minute, 0, 0, time.Local).UTC())
+		e.Source = source
+		return e
+	}
+	insertTestEvents(t, s.DB(),
+		event("github.com/user/api", "aaa0001", 9, 0, store.SourcePostCommit),
+		event("github.com/user/web", "bbb0001", 9, 10, store.SourcePostCommit),
+		event("github.com/user/api", "aaa0002", 9, 25, store.SourcePostCommit),
+		event("github.com/user/api", "aaa0002", 9, 40, store.SourcePrePush),
+		event("github.com/user/api", "aaa0003", 23, 30, store.SourcePostCommit),
+	)
+	deps := timesheetTestDeps(t, s, out)
+	deps.Now = func() time.Time { return time.Date(2025, 3, 13, 16, 0, 0, 0, time.Local) }
+	return deps
+}
+
+func TestHeatmap_ASCII(t *testing.T) {
+	useTestConfig(t, "")
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--repo=github.com/user/api"})
+	require.NoError(t, showHeatmap(nil, flags, heatmapTestDeps(t, &out)))
+
+	require.Len(t, out, 1)
+	lines := strings.Split(out[0], "\n")
+	require.Equal(t, "Activity 2024-03-11 to 2025-03-13", lines[0])
+
+	// Monday 2025-03-10, in the current week, is the busiest day; the 23:30
+	// commit stays on it whatever the offset from UTC
+	mon := lines[3]
+	require.True(t, strings.HasPrefix(mon, "Mon "), mon)
+	require.Equal(t, ".#", mon[len(mon)-2:])
+	require.NotContains(t, out[0], "\x1b[")
+
+	require.Contains(t, out[0], "4 events on 1 day, busiest 2025-03-10 (4)")
+	require.Contains(t, out[0], "Less . - + * # More")
+}
+
+func TestHeatmap_ClockOutsideUTC(t *testing.T) {
+	useTestConfig(t, "")
+
+	// The grid follows the local zone even when the clock is in another
+	var out []string
+	deps := heatmapTestDeps(t, &out)
+	deps.Now = func() time.Time {
+		return time.Date(2025, 3, 13, 16, 0, 0, 0, time.Local).In(time.FixedZone("PDT", -7*60*60))
+	}
+	require.NoError(t, showHeatmap(nil, dispatchers.NewParsedFlags(nil), deps))
+
+	require.Contains(t, out[0], "Activity 2024-03-11 to 2025-03-13")
+	require.Contains(t, out[0], "5 events on 1 day, busiest 2025-03-10 (5)")
+}
@@ -13,6 +13,7 @@ var statusMap = map[string]store.Status{
 	"exported": store.StatusExported,
 	"orphaned": store.StatusOrphaned,
 	"skipped":  store.StatusSkipped,
+	"imported": store.StatusImported,
 }
 
 var sourceMap = map[string]store.Source{
@@ -0,0 +1,128 @@
+package tracking
+
+import (
+	"database/sql"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+	repodomain "github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// repoIdentityStrategy returns the repo_identity setting. An invalid value
+// is logged and the remote URL is used, as before the setting existed.
+func repoIdentityStrategy() repodomain.Strategy {
+	value, _ := config.Get("repo_identity")
+	strategy, err := repodomain.ParseStrategy(value)
+	if err != nil {
+		log.Warn("%v", err)
+	}
+	return strategy
+}
+
+// identifyRepo returns the id to record a clone's events under. legacyID is
+// the id its remote URL gives. With repo_identity=root-commit or path the
+// clone's identity is stored with its remote URL; the first time, events
+// already recorded under legacyID move to the new id. Failures are logged
+// and fall back to legacyID so recording never fails on them.
+func identifyRepo(db *sql.DB, repoRoot, remoteURL, legacyID string, deps Deps) string {
+	strategy := repoIdentityStrategy()
+
+	stored, found, err := store.GetRepoIdentity(db, repoRoot)
+	if err != nil {
+		log.Warn("identity: could not read identity of %s: %v", repoRoot, err)
+		return legacyID
+	}
+
+	if strategy == repodomain.StrategyRemote {
+		// Switching back to remote ids; 'fp record' points at relink if
+		// the clone was tracked under the other id
+		if found {
+			if err := store.DeleteRepoIdentity(db, repoRoot); err != nil {
+				log.Warn("identity: could not forget identity of %s: %v", repoRoot, err)
+			}
+		}
+		return legacyID
+	}
+
+	rootKnown := strategy != repodomain.StrategyRootCommit || stored.RootCommit != ""
+	if found && stored.Strategy == string(strategy) && stored.LegacyID == legacyID && rootKnown {
+		if stored.RemoteURL != remoteURL {
+			stored.RemoteURL = remoteURL
+			if err := store.SaveRepoIdentity(db, stored, deps.Now()); err != nil {
+				log.Warn("identity: could not update remote of %s: %v", repoRoot, err)
+			}
+		}
+		return stored.RepoID
+	}
+
+	var rootCommit string
+	if strategy == repodomain.StrategyRootCommit {
+		// The root commit never changes, so it is only looked up once per clone
+		rootCommit = stored.RootCommit
+		if rootCommit == "" {
+			if rootCommit, err = deps.RootCommit(repoRoot); err != nil {
+				log.Warn("identity: could not read root commit of %s: %v", repoRoot, err)
+				return legacyID
+			}
+		}
+	}
+
+	id, err := repodomain.Identify(strategy, remoteURL, repoRoot, rootCommit)
+	if err != nil {
+		log.Warn("identity: could not derive %s id of %s: %v", strategy, repoRoot, err)
+		return legacyID
+	}
+
+	ident := store.RepoIdentity{
+		RepoPath:   repoRoot,
+		RepoID:     string(id),
+		LegacyID:   legacyID,
+		Strategy:   string(strategy),
+		RootCommit: rootCommit,
+		RemoteURL:  remoteURL,
+	}
+	if err := store.SaveRepoIdentity(db, ident, deps.Now()); err != nil {
+		log.Warn("identity: could not save identity of %s: %v", repoRoot, err)
+		return legacyID
+	}
+	log.Info("identity: %s is now recorded as %s (%s)", repoRoot, id, strategy)
+
+	if ident.RepoID != legacyID {
+		migrateLegacyEvents(db, repoRoot, legacyID, ident.RepoID, deps)
+	}
+	return ident.RepoID
+}
+
+// migrateLegacyEvents moves the pending events a clone recorded under its
+// legacy id to the id it now derives. Other clones of the same remote keep
+// theirs, as they may derive a different id. Exported events keep matching
+// the export, so they stay and the notice explains how to move them as well.
+func migrateLegacyEvents(db *sql.DB, repoRoot, legacyID, repoID string, deps Deps) {
+	result, err := store.RelinkClone(db, repoRoot, legacyID, repoID, deps.Now())
+	if err != nil {
+		log.Warn("identity: could not move events of %s to %s: %v", legacyID, repoID, err)
+		return
+	}
+	log.Info("identity: moved %d events from %s to %s (%d left)", result.Moved, legacyID, repoID, result.Remaining)
+
+	if result.Remaining > 0 {
+		verb, pronoun := "stay", "them"
+		if result.Remaining == 1 {
+			verb, pronoun = "stays", "it"
+		}
+		_, _ = deps.Printf("fp: this clone is now recorded as %s; %s %s under %s\n",
+			repoID, pluralize(int(result.Remaining), "exported event"), verb, legacyID)
+		_, _ = deps.Printf("fp: move %s too with: fp repos relink %s %s --all-statuses --rewrite-export\n", pronoun, legacyID, repoID)
+	}
+}
+
+// mappedRepoID returns the id a spooled event is stored under, for events
+// spooled with their legacy id before the clone's identity was known.
+func mappedRepoID(db *sql.DB, e store.RepoEvent) string {
+	repoID, err := store.MappedRepoID(db, e.RepoPath, e.RepoID)
+	if err != nil {
+		log.Warn("identity: could not map %s: %v", e.RepoID, err)
+	}
+	return repoID
+}
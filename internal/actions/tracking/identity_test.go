@@ -0,0 +1,105 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestIdentifyRepo(t *testing.T) {
+	const (
+		rootCommit = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+		rootID     = "root:" + rootCommit
+		mirrorID   = "gitlab.com/acme/platform-services-api"
+	)
+	useTestConfig(t, "repo_identity=root-commit\n")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := annotateTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+	lookups := 0
+	deps.RootCommit = func(string) (string, error) {
+		lookups++
+		return rootCommit, nil
+	}
+
+	db := openTestStore(t, path).DB()
+
+	// The pending event moves; the exported one stays and a notice says so
+	id := identifyRepo(db, "/src/repo", "git@github.com:user/repo.git", "github.com/user/repo", deps)
+	require.Equal(t, rootID, id)
+	require.Equal(t, []string{
+		"fp: this clone is now recorded as " + rootID + "; 1 exported event stays under github.com/user/repo\n",
+		"fp: move it too with: fp repos relink github.com/user/repo " + rootID + " --all-statuses --rewrite-export\n",
+	}, out)
+	e, err := store.FindEvent(db, "def7")
+	require.NoError(t, err)
+	require.Equal(t, rootID, e.RepoID)
+	e, err = store.FindEvent(db, "abc1")
+	require.NoError(t, err)
+	require.Equal(t, "github.com/user/repo", e.RepoID)
+
+	// The stored identity is reused and a mirror clone gets the same id
+	require.Equal(t, rootID, identifyRepo(db, "/src/repo", "https://github.com/user/repo", "github.com/user/repo", deps))
+	require.Equal(t, 1, lookups)
+	require.Equal(t, rootID, identifyRepo(db, "/mirror", "git@gitlab.com:acme/platform-services-api.git", mirrorID, deps))
+
+	ident, found, err := store.GetRepoIdentity(db, "/src/repo")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "https://github.com/user/repo", ident.RemoteURL)
+
+	// Back to remote ids: the identity is forgotten
+	require.NoError(t, os.WriteFile(filepath.Join(os.Getenv("HOME"), ".fprc"), []byte("repo_identity=remote\n"), 0600))
+	require.Equal(t, "github.com/user/repo", identifyRepo(db, "/src/repo", "git@github.com:user/repo.git", "github.com/user/repo", deps))
+	_, found, err = store.GetRepoIdentity(db, "/src/repo")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestIdentifyRepo_PathKeepsOtherClones(t *testing.T) {
+	const legacyID = "github.com/user/repo"
+	useTestConfig(t, "repo_identity=path\n")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var out []string
+	deps := pruneTestDeps(filepath.Join(t.TempDir(), "store.db"), now, &out)
+
+	s := newTestStore(t)
+	db := s.DB()
+	for _, clone := range []string{"/src/a", "/src/b"} {
+		e := testEvent(legacyID, "commit"+filepath.Base(clone), now)
+		e.RepoPath = clone
+		insertTestEvents(t, db, e)
+		_, err := db.Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES (?, ?)`, legacyID, clone)
+		require.NoError(t, err)
+	}
+
+	// The first clone to record only takes its own events and registration
+	idA := identifyRepo(db, "/src/a", "git@github.com:user/repo.git", legacyID, deps)
+	require.Equal(t, "local:/src/a", idA)
+	e, err := store.FindEvent(db, "commita")
+	require.NoError(t, err)
+	require.Equal(t, idA, e.RepoID)
+	e, err = store.FindEvent(db, "commitb")
+	require.NoError(t, err)
+	require.Equal(t, legacyID, e.RepoID)
+	tracked, err := store.TrackedRepoID(db, "/src/b")
+	require.NoError(t, err)
+	require.Equal(t, legacyID, tracked)
+
+	// The second clone moves to an id of its own when it records
+	idB := identifyRepo(db, "/src/b", "git@github.com:user/repo.git", legacyID, deps)
+	require.Equal(t, "local:/src/b", idB)
+	e, err = store.FindEvent(db, "commitb")
+	require.NoError(t, err)
+	require.Equal(t, idB, e.RepoID)
+	tracked, err = store.TrackedRepoID(db, "/src/b")
+	require.NoError(t, err)
+	require.Equal(t, idB, tracked)
+	require.Empty(t, out, "nothing was exported, so nothing is left behind")
+}
@@ -0,0 +1,276 @@
+package tracking
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// unknownDevice attributes rows from exports written before the device column.
+const unknownDevice = "unknown"
+
+// importSummary counts what happened to the rows of the imported files.
+type importSummary struct {
+	Files    int            `json:"files"`
+	Rows     int            `json:"rows"`
+	Imported int            `json:"imported"`
+	Existing int            `json:"already_present"`
+	Local    int            `json:"skipped_local"`
+	Invalid  int            `json:"invalid"`
+	Devices  map[string]int `json:"devices"`
+	DryRun   bool           `json:"dry_run"`
+}
+
+// Import reads CSV exports written on other devices into the database.
+func Import(args []string, flags *dispatchers.ParsedFlags) error {
+	return importExports(args, flags, DefaultDeps())
+}
+
+func importExports(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("path")
+	}
+
+	files, err := exportCSVFiles(args[0])
+	if err != nil {
+		return err
+	}
+
+	dryRun := flags.Has("--dry-run")
+	includeLocal := flags.Has("--include-local")
+	device := flags.String("--device", unknownDevice)
+	localDevice := getHostname()
+
+	summary := importSummary{Files: len(files), Devices: map[string]int{}, DryRun: dryRun}
+	var events []store.ImportedEvent
+
+	for _, file := range files {
+		rows, invalid, err := readExportCSV(file, device)
+		if err != nil {
+			return err
+		}
+		summary.Rows += len(rows) + invalid
+		summary.Invalid += invalid
+
+		for _, row := range rows {
+			// This device's rows are already in the database (or were pruned on purpose)
+			if !includeLocal && localDevice != "" && row.Event.Device == localDevice {
+				summary.Local++
+				continue
+			}
+			events = append(events, row)
+			summary.Devices[row.Event.Device]++
+		}
+	}
+
+	if !dryRun && len(events) > 0 {
+		db, err := deps.OpenDB(deps.DBPath())
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer store.CloseDB(db)
+
+		if err := deps.InitDB(db); err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+
+		summary.Imported, err = store.ImportEvents(db, events)
+		if err != nil {
+			return fmt.Errorf("failed to import events: %w", err)
+		}
+		summary.Existing = len(events) - summary.Imported
+	}
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, summary)
+	}
+
+	if dryRun {
+		_, _ = deps.Printf("Would import up to %s from %s\n", pluralize(len(events), "event"), pluralize(len(files), "file"))
+	} else {
+		_, _ = deps.Printf("Imported %s from %s\n", pluralize(summary.Imported, "event"), pluralize(len(files), "file"))
+	}
+
+	devices := make([]string, 0, len(summary.Devices))
+	for d := range summary.Devices {
+		devices = append(devices, d)
+	}
+	sort.Strings(devices)
+	for _, d := range devices {
+		_, _ = deps.Printf("  %-20s %d\n", d, summary.Devices[d])
+	}
+
+	if summary.Existing > 0 {
+		_, _ = deps.Printf("%s already in the database\n", pluralize(summary.Existing, "event"))
+	}
+	if summary.Local > 0 {
+		_, _ = deps.Printf("Skipped %s from this device (%s); use --include-local to import them\n", pluralize(summary.Local, "row"), localDevice)
+	}
+	if summary.Invalid > 0 {
+		_, _ = deps.Printf("Skipped %s that could not be read (see 'fp logs')\n", pluralize(summary.Invalid, "row"))
+	}
+	return nil
+}
+
+// exportCSVFiles returns the CSV files to import: the file itself, or the
+// commits*.csv files of an export directory.
+func exportCSVFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "commits*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no commits*.csv files in %s", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readExportCSV parses an export CSV by column name, so files written with
+// an older header still import. Rows without a device column are attributed
+// to device. Returns the readable rows and the number of rows skipped.
+func readExportCSV(path, device string) ([]store.ImportedEvent, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	repoIdx, commitIdx := findColumnIndices(header)
+	if repoIdx < 0 || commitIdx < 0 {
+		return nil, 0, fmt.Errorf("%s is not an fp export: header has no repo_id or commit_hash column", path)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var events []store.ImportedEvent
+	invalid := 0
+	for line := 2; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		e, err := importedEvent(row, field, device)
+		if err != nil {
+			log.Warn("import: skipping %s line %d: %v", path, line, err)
+			invalid++
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, invalid, nil
+}
+
+// importedEvent builds an event and its metadata snapshot from a CSV row.
+func importedEvent(row []string, field func([]string, string) string, device string) (store.ImportedEvent, error) {
+	repoID := field(row, "repo_id")
+	commit := field(row, "commit_hash")
+	if repoID == "" || commit == "" {
+		return store.ImportedEvent{}, fmt.Errorf("missing repo_id or commit_hash")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, field(row, "timestamp"))
+	if err != nil {
+		return store.ImportedEvent{}, fmt.Errorf("invalid timestamp %q", field(row, "timestamp"))
+	}
+
+	source := store.SourcePostCommit
+	switch field(row, "event_type") {
+	case eventTypeMerge:
+		source = store.SourcePostMerge
+	case eventTypePush:
+		source = store.SourcePrePush
+	}
+
+	if d := field(row, "device"); d != "" {
+		device = d
+	}
+
+	atoi := func(name string) int {
+		n, _ := strconv.Atoi(field(row, name))
+		return n
+	}
+
+	// Exports carry no clone path; a repo_path column, when a file has one,
+	// points git lookups at that clone instead of none
+	e := store.ImportedEvent{
+		Event: store.RepoEvent{
+			RepoID:    repoID,
+			RepoPath:  field(row, "repo_path"),
+			Commit:    commit,
+			Branch:    field(row, "branch"),
+			Timestamp: timestamp.UTC(),
+			Status:    store.StatusImported,
+			Source:    source,
+			Device:    device,
+		},
+	}
+
+	// Annotation columns are only present when export_annotations was on
+	for _, t := range strings.Split(field(row, "tags"), ",") {
+		if tag, err := normalizeTag(t); err == nil {
+			e.Annotations.Tags = append(e.Annotations.Tags, tag)
+		}
+	}
+	e.Annotations.Note = field(row, "note")
+
+	// Rows exported without commit details carry no snapshot, so a later
+	// row for the same commit can still provide one
+	if field(row, "author_name") != "" || field(row, "author_email") != "" || field(row, "message") != "" {
+		e.Metadata = git.CommitMetadata{
+			AuthoredAt:    timestamp.UTC().Format(time.RFC3339),
+			ParentCommits: strings.ReplaceAll(field(row, "parent_hashes"), ",", " "),
+			AuthorName:    field(row, "author_name"),
+			AuthorEmail:   field(row, "author_email"),
+			Subject:       field(row, "message"),
+			FilesChanged:  atoi("files_changed"),
+			Insertions:    atoi("insertions"),
+			Deletions:     atoi("deletions"),
+		}
+	}
+	return e, nil
+}
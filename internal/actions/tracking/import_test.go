@@ -0,0 +1,142 @@
+package tracking
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func writeExportCSV(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+}
+
+func importedEvents(t *testing.T, path string) []store.RepoEvent {
+	t.Helper()
+	events, err := store.ListEvents(openTestStore(t, path).DB(), store.EventFilter{})
+	require.NoError(t, err)
+	return events
+}
+
+func TestImport_ExportDirectory(t *testing.T) {
+	local := getHostname()
+	require.NotEmpty(t, local)
+
+	dir := t.TempDir()
+	writeExportCSV(t, filepath.Join(dir, "commits-2025.csv"),
+		strings.Join(csvHeader, ","),
+		"1,commit,2025-03-01T10:00:00Z,github.com/user/repo,repo,,Dev,dev@example.com,main,aaa111,p1,Fix parser,2,10,1,work-laptop",
+		"2,push,2025-03-01T11:00:00Z,github.com/user/repo,repo,,,,main,aaa111,,,,,,work-laptop",
+		"3,commit,2025-03-02T10:00:00Z,github.com/user/repo,repo,,,,main,bbb222,,,,,,"+local,
+		"4,commit,not-a-date,github.com/user/repo,repo,,,,main,ccc333,,,,,,work-laptop",
+	)
+	// Exports from before the device column existed
+	writeExportCSV(t, filepath.Join(dir, "commits.csv"),
+		"event_id,event_type,timestamp,repo_id,commit_hash,branch",
+		"1,merge,2024-12-01T10:00:00Z,github.com/user/other,ddd444,main",
+	)
+
+	path := newTestStoreFile(t)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--device=old-desktop", "--json"})
+	require.NoError(t, importExports([]string{dir}, flags, pruneTestDeps(path, now, &out)))
+
+	var summary importSummary
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &summary))
+	require.Equal(t, 2, summary.Files)
+	require.Equal(t, 3, summary.Imported)
+	require.Equal(t, 1, summary.Local)
+	require.Equal(t, 1, summary.Invalid)
+	require.Equal(t, map[string]int{"work-laptop": 2, "old-desktop": 1}, summary.Devices)
+
+	events := importedEvents(t, path)
+	require.Len(t, events, 3)
+	for _, e := range events {
+		require.Equal(t, store.StatusImported, e.Status)
+		if e.Commit == "ddd444" {
+			require.Equal(t, "old-desktop", e.Device)
+			require.Equal(t, store.SourcePostMerge, e.Source)
+		} else {
+			require.Equal(t, "work-laptop", e.Device)
+		}
+	}
+
+	// A second import finds everything already present
+	out = nil
+	require.NoError(t, importExports([]string{dir}, flags, pruneTestDeps(path, now, &out)))
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &summary))
+	require.Zero(t, summary.Imported)
+	require.Equal(t, 3, summary.Existing)
+}
+
+func TestImport_DryRun(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "laptop.csv")
+	writeExportCSV(t, csvPath,
+		"repo_id,commit_hash,timestamp,device",
+		"github.com/user/repo,aaa111,2025-03-01T10:00:00Z,work-laptop",
+	)
+
+	path := newTestStoreFile(t)
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--dry-run"})
+	require.NoError(t, importExports([]string{csvPath}, flags, pruneTestDeps(path, time.Now(), &out)))
+	require.Contains(t, out[0], "Would import")
+	require.Empty(t, importedEvents(t, path))
+}
+
+func TestImport_Annotations(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "laptop.csv")
+	writeExportCSV(t, csvPath,
+		"repo_id,commit_hash,timestamp,device,tags,note",
+		`github.com/user/repo,aaa111,2025-03-01T10:00:00Z,work-laptop,"Billable,review",Paged at 3am`,
+	)
+
+	path := newTestStoreFile(t)
+	var out []string
+	require.NoError(t, importExports([]string{csvPath}, dispatchers.NewParsedFlags(nil), pruneTestDeps(path, time.Now(), &out)))
+
+	_, a := eventAnnotations(t, path, "aaa111")
+	require.Equal(t, []string{"billable", "review"}, a.Tags)
+	require.Equal(t, "Paged at 3am", a.Note)
+}
+
+func TestImport_RepoPath(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "laptop.csv")
+	writeExportCSV(t, csvPath,
+		"repo_id,repo_path,commit_hash,timestamp,device",
+		"github.com/user/repo,/src/repo,aaa111,2025-03-01T10:00:00Z,work-laptop",
+		"github.com/user/repo,,bbb222,2025-03-02T10:00:00Z,work-laptop",
+	)
+
+	path := newTestStoreFile(t)
+	var out []string
+	require.NoError(t, importExports([]string{csvPath}, dispatchers.NewParsedFlags(nil), pruneTestDeps(path, time.Now(), &out)))
+
+	paths := map[string]string{}
+	for _, e := range importedEvents(t, path) {
+		paths[e.Commit] = e.RepoPath
+	}
+	require.Equal(t, map[string]string{"aaa111": "/src/repo", "bbb222": ""}, paths)
+
+	// Without a clone the views show the repo ID, not the current directory
+	require.Equal(t, "github.com/user/repo", repoAliases(nil).label(store.RepoEvent{RepoID: "github.com/user/repo"}))
+}
+
+func TestImport_RejectsOtherFiles(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "notes.csv")
+	writeExportCSV(t, csvPath, "name,value", "a,b")
+
+	var out []string
+	deps := pruneTestDeps(newTestStoreFile(t), time.Now(), &out)
+	require.ErrorContains(t, importExports([]string{csvPath}, dispatchers.NewParsedFlags(nil), deps), "not an fp export")
+	require.ErrorContains(t, importExports([]string{t.TempDir()}, dispatchers.NewParsedFlags(nil), deps), "no commits*.csv")
+}
@@ -71,6 +71,15 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		filter.RepoID = &repoID
 	}
 
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	aliases := loadRepoAliases(db)
+
 	// Get current max ID as starting point (we only want new events)
 	lastID, err := store.GetMaxEventID(db)
 	if err != nil {
@@ -114,12 +123,12 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 			for _, event := range events {
 				switch {
 				case jsonOutput:
-					outputEventJSON(event, enrich)
+					outputEventJSON(event, enrich, aliases)
 				case enrich:
 					meta := git.GetCommitMetadata(event.RepoPath, event.Commit)
-					_, _ = fmt.Fprintln(os.Stdout, formatEventEnriched(event, meta, oneline))
+					_, _ = fmt.Fprintln(os.Stdout, formatEventEnriched(event, meta, aliases, oneline))
 				default:
-					_, _ = fmt.Fprintln(os.Stdout, formatEvent(event, oneline))
+					_, _ = fmt.Fprintln(os.Stdout, formatEvent(event, aliases, oneline))
 				}
 				// Note: int64 overflow is not a practical concern (max ~9 quintillion).
 				// A negative ID would indicate database corruption.
@@ -131,10 +140,11 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	}
 }
 
-func outputEventJSON(e store.RepoEvent, enrich bool) {
+func outputEventJSON(e store.RepoEvent, enrich bool, aliases repoAliases) {
 	type jsonEvent struct {
 		ID        int64  `json:"id"`
 		RepoID    string `json:"repo_id"`
+		RepoAlias string `json:"repo_alias,omitempty"`
 		RepoPath  string `json:"repo_path"`
 		Commit    string `json:"commit"`
 		Branch    string `json:"branch"`
@@ -143,17 +153,28 @@ func outputEventJSON(e store.RepoEvent, enrich bool) {
 		Source    string `json:"source"`
 		Author    string `json:"author,omitempty"`
 		Message   string `json:"message,omitempty"`
+
+		PreviousCommit string `json:"previous_commit,omitempty"`
+		PreviousBranch string `json:"previous_branch,omitempty"`
+		BranchSwitch   *bool  `json:"branch_switch,omitempty"`
 	}
 
 	je := jsonEvent{
-		ID:        e.ID,
-		RepoID:    e.RepoID,
-		RepoPath:  e.RepoPath,
-		Commit:    e.Commit,
-		Branch:    e.Branch,
-		Timestamp: e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
-		Status:    e.Status.String(),
-		Source:    e.Source.String(),
+		ID:             e.ID,
+		RepoID:         e.RepoID,
+		RepoAlias:      aliases[e.RepoID],
+		RepoPath:       e.RepoPath,
+		Commit:         e.Commit,
+		Branch:         e.Branch,
+		Timestamp:      e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		Status:         e.Status.String(),
+		Source:         e.Source.String(),
+		PreviousCommit: e.PreviousCommit,
+		PreviousBranch: e.PreviousBranch,
+	}
+	if e.Source == store.SourcePostCheckout {
+		branchSwitch := e.BranchSwitch
+		je.BranchSwitch = &branchSwitch
 	}
 	if enrich {
 		meta := git.GetCommitMetadata(e.RepoPath, e.Commit)
@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"database/sql"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// metadataSnapshotsEnabled reports whether record and backfill should copy
+// commit metadata into the database (snapshot_metadata=true).
+func metadataSnapshotsEnabled() bool {
+	value, _ := config.Get("snapshot_metadata")
+	return value == "true"
+}
+
+// snapshotCommitMetadata stores git metadata for an event's commit unless a
+// snapshot already exists. Failures are logged; recording never fails on them.
+func snapshotCommitMetadata(db *sql.DB, e store.RepoEvent) {
+	exists, err := store.HasCommitMetadata(db, e.RepoID, e.Commit)
+	if err != nil {
+		log.Warn("record: could not check metadata snapshot for %.7s: %v", e.Commit, err)
+		return
+	}
+	if exists {
+		return
+	}
+
+	meta := git.GetCommitMetadata(e.RepoPath, e.Commit)
+	if meta.AuthoredAt == "" {
+		log.Debug("record: no git metadata for %.7s in %s, skipping snapshot", e.Commit, e.RepoPath)
+		return
+	}
+
+	if err := store.SaveCommitMetadata(db, e.RepoID, e.Commit, meta); err != nil {
+		log.Warn("record: could not save metadata snapshot for %.7s: %v", e.Commit, err)
+	}
+}
+
+// loadMetadataSnapshots returns stored snapshots for the events, keyed by
+// repo_id:commit. Errors are logged and yield an empty map so callers fall back to git.
+func loadMetadataSnapshots(db *sql.DB, events []store.RepoEvent) map[string]git.CommitMetadata {
+	snapshots, err := store.ListCommitMetadata(db, events)
+	if err != nil {
+		log.Warn("could not read metadata snapshots, falling back to git: %v", err)
+		return nil
+	}
+	return snapshots
+}
+
+// commitMetadataFor returns the snapshot for an event's commit if one exists,
+// otherwise reads it from the repository.
+func commitMetadataFor(e store.RepoEvent, snapshots map[string]git.CommitMetadata) git.CommitMetadata {
+	if meta, ok := snapshots[e.RepoID+":"+e.Commit]; ok {
+		return meta
+	}
+	return git.GetCommitMetadata(e.RepoPath, e.Commit)
+}
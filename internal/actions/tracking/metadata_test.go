@@ -0,0 +1,40 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestSnapshotCommitMetadata_SkipsWhenGitHasNoData(t *testing.T) {
+	s := newTestStore(t)
+
+	event := store.RepoEvent{RepoID: "github.com/user/repo", RepoPath: t.TempDir(), Commit: "abc1234"}
+	snapshotCommitMetadata(s.DB(), event)
+
+	exists, err := store.HasCommitMetadata(s.DB(), event.RepoID, event.Commit)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestCommitMetadataFor_PrefersSnapshot(t *testing.T) {
+	s := newTestStore(t)
+
+	event := store.RepoEvent{RepoID: "github.com/user/repo", RepoPath: t.TempDir(), Commit: "abc1234"}
+	require.NoError(t, store.SaveCommitMetadata(s.DB(), event.RepoID, event.Commit, git.CommitMetadata{
+		AuthorName: "Test User",
+		Subject:    "Fix a bug",
+	}))
+
+	snapshots := loadMetadataSnapshots(s.DB(), []store.RepoEvent{event})
+	meta := commitMetadataFor(event, snapshots)
+	require.Equal(t, "Test User", meta.AuthorName)
+	require.Equal(t, "Fix a bug", meta.Subject)
+
+	// Without a snapshot the repository is asked, which has nothing here
+	other := store.RepoEvent{RepoID: "github.com/user/repo", RepoPath: t.TempDir(), Commit: "def5678"}
+	require.Empty(t, commitMetadataFor(other, snapshots).Subject)
+}
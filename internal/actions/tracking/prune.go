@@ -0,0 +1,169 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// Prune deletes old events from the database.
+func Prune(args []string, flags *dispatchers.ParsedFlags) error {
+	return prune(args, flags, DefaultDeps())
+}
+
+func prune(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+
+	olderThan := flags.String("--older-than", "")
+	if olderThan == "" {
+		days := retentionDays()
+		if days <= 0 {
+			return fmt.Errorf("no age given: use --older-than (e.g. 365d) or set retention_days")
+		}
+		olderThan = strconv.Itoa(days) + "d"
+	}
+
+	before, err := pruneCutoff(olderThan, deps.Now())
+	if err != nil {
+		return err
+	}
+
+	filter := store.PruneFilter{Before: before}
+
+	if statusStr := flags.String("--status", ""); statusStr != "" {
+		for _, name := range strings.Split(statusStr, ",") {
+			status, ok := parseStatus(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("invalid status '%s': valid values are %s", name, strings.Join(validStatuses(), ", "))
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	} else if retentionKeepExportedOnly() {
+		filter.Statuses = []store.Status{store.StatusExported}
+	}
+
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	var count int64
+	if dryRun {
+		count, err = s.CountPrunable(filter)
+	} else {
+		count, err = s.Prune(filter)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prune events: %w", err)
+	}
+
+	if !dryRun && count > 0 {
+		if err := s.Compact(); err != nil {
+			return fmt.Errorf("deleted %d events but could not compact the database: %w", count, err)
+		}
+	}
+
+	if jsonOutput {
+		return output.JSON(deps.Println, map[string]any{
+			"dry_run":  dryRun,
+			"before":   filter.Before.UTC().Format(time.RFC3339),
+			"statuses": statusNames(filter.Statuses),
+			"events":   count,
+		})
+	}
+
+	cutoff := format.Date(filter.Before)
+	switch {
+	case count == 0:
+		_, _ = deps.Printf("No events recorded before %s to prune\n", cutoff)
+	case dryRun:
+		_, _ = deps.Printf("Would delete %s recorded before %s\n", pluralize(int(count), "event"), cutoff)
+	default:
+		_, _ = deps.Printf("Deleted %s recorded before %s\n", pluralize(int(count), "event"), cutoff)
+	}
+	return nil
+}
+
+// applyRetention deletes events older than retention_days. It runs alongside
+// the automatic export, so it only checkpoints the WAL; 'fp prune' also vacuums.
+func applyRetention(db *sql.DB, deps Deps) {
+	days := retentionDays()
+	if days <= 0 {
+		return
+	}
+
+	before, err := pruneCutoff(strconv.Itoa(days)+"d", deps.Now())
+	if err != nil {
+		log.Error("retention: %v", err)
+		return
+	}
+
+	filter := store.PruneFilter{Before: before}
+	if retentionKeepExportedOnly() {
+		filter.Statuses = []store.Status{store.StatusExported}
+	}
+
+	s := store.NewWithDB(db)
+	deleted, err := s.Prune(filter)
+	if err != nil {
+		log.Error("retention: prune failed: %v", err)
+		return
+	}
+	if deleted == 0 {
+		return
+	}
+	log.Info("retention: deleted %d events older than %d days", deleted, days)
+
+	if err := s.Checkpoint(); err != nil {
+		log.Warn("retention: %v", err)
+	}
+}
+
+// retentionDays returns retention_days, or 0 (keep forever) when unset or invalid.
+func retentionDays() int {
+	value, _ := config.Get("retention_days")
+	days, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || days < 0 {
+		return 0
+	}
+	return days
+}
+
+// retentionKeepExportedOnly reports whether pruning is limited to events that
+// are already in the export (retention_keep_exported_only, default true).
+func retentionKeepExportedOnly() bool {
+	value, _ := config.Get("retention_keep_exported_only")
+	return value != "false"
+}
+
+// pruneCutoff reads --older-than like --since: an age such as 90d, 12w, 6mo
+// or 1y, or a date. Events recorded before it are pruned.
+func pruneCutoff(value string, now time.Time) (time.Time, error) {
+	p, err := format.ParsePeriod(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --older-than: %w", err)
+	}
+	if !p.Start.Before(now) {
+		return time.Time{}, fmt.Errorf("invalid --older-than '%s': it must be in the past", value)
+	}
+	return p.Start, nil
+}
+
+func statusNames(statuses []store.Status) []string {
+	names := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		names = append(names, strings.ToLower(s.String()))
+	}
+	return names
+}
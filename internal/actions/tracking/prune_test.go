@@ -0,0 +1,177 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// useTestConfig points the config file at a temporary ~/.fprc with the given lines.
+func useTestConfig(t *testing.T, lines string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".fprc"), []byte(lines), 0600))
+}
+
+// pruneTestStore creates a database file with old and recent events.
+// prune closes the store it opens, so tests reopen the file to inspect it.
+func pruneTestStore(t *testing.T, now time.Time) string {
+	t.Helper()
+	event := func(commit string, daysAgo int, status store.Status) store.RepoEvent {
+		e := testEvent("github.com/user/repo", commit, now.AddDate(0, 0, -daysAgo))
+		e.Status = status
+		return e
+	}
+	return newTestStoreFile(t,
+		event("old-exported", 400, store.StatusExported),
+		event("old-pending", 400, store.StatusPending),
+		event("old-orphaned", 400, store.StatusOrphaned),
+		event("recent", 5, store.StatusExported),
+	)
+}
+
+func pruneTestDeps(path string, now time.Time, out *[]string) Deps {
+	deps := DefaultDeps()
+	deps.DBPath = func() string { return path }
+	deps.Now = func() time.Time { return now }
+	deps.Println = func(a ...any) (int, error) {
+		*out = append(*out, fmt.Sprint(a...))
+		return 0, nil
+	}
+	deps.Printf = func(f string, a ...any) (int, error) {
+		*out = append(*out, fmt.Sprintf(f, a...))
+		return 0, nil
+	}
+	return deps
+}
+
+func remainingCommits(t *testing.T, path string) []string {
+	t.Helper()
+	events, err := store.ListEvents(openTestStore(t, path).DB(), store.EventFilter{})
+	require.NoError(t, err)
+	var commits []string
+	for _, e := range events {
+		commits = append(commits, e.Commit)
+	}
+	return commits
+}
+
+func TestPruneCutoff(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  time.Time
+		err   string
+	}{
+		{"365d", now.AddDate(0, 0, -365), ""},
+		{"2w", now.AddDate(0, 0, -14), ""},
+		{"6mo", now.AddDate(0, -6, 0), ""},
+		{"1Y", now.AddDate(-1, 0, 0), ""},
+		{"2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"6m", time.Time{}, "use mo for months"},
+		{"0d", time.Time{}, "must be in the past"},
+		{"now", time.Time{}, "must be in the past"},
+		{"soon", time.Time{}, "unrecognized date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := pruneCutoff(tt.input, now)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrune_DryRunJSON(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := pruneTestStore(t, now)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--older-than=365d", "--dry-run", "--json"})
+	require.NoError(t, prune(nil, flags, pruneTestDeps(path, now, &out)))
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &result))
+	require.Equal(t, true, result["dry_run"])
+	require.Equal(t, float64(1), result["events"])
+	require.Equal(t, []any{"exported"}, result["statuses"])
+
+	require.Len(t, remainingCommits(t, path), 4)
+}
+
+func TestPrune_StatusFlag(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := pruneTestStore(t, now)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--older-than=1y", "--status=exported,orphaned"})
+	require.NoError(t, prune(nil, flags, pruneTestDeps(path, now, &out)))
+
+	require.Equal(t, []string{"Deleted 2 events recorded before " + format.Date(now.AddDate(-1, 0, 0)) + "\n"}, out)
+	require.ElementsMatch(t, []string{"old-pending", "recent"}, remainingCommits(t, path))
+}
+
+func TestPrune_UsesRetentionConfig(t *testing.T) {
+	useTestConfig(t, "retention_days=30\nretention_keep_exported_only=false\n")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := pruneTestStore(t, now)
+
+	var out []string
+	require.NoError(t, prune(nil, dispatchers.NewParsedFlags(nil), pruneTestDeps(path, now, &out)))
+
+	require.Equal(t, []string{"Deleted 3 events recorded before " + format.Date(now.AddDate(0, 0, -30)) + "\n"}, out)
+	require.Equal(t, []string{"recent"}, remainingCommits(t, path))
+
+	out = nil
+	require.NoError(t, prune(nil, dispatchers.NewParsedFlags([]string{"--dry-run"}), pruneTestDeps(path, now, &out)))
+	require.Equal(t, []string{"No events recorded before " + format.Date(now.AddDate(0, 0, -30)) + " to prune\n"}, out)
+}
+
+func TestPrune_InvalidInput(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := pruneTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.ErrorContains(t, prune(nil, dispatchers.NewParsedFlags(nil), deps), "retention_days")
+	require.ErrorContains(t, prune(nil, dispatchers.NewParsedFlags([]string{"--older-than=soon"}), deps), "invalid --older-than")
+	require.ErrorContains(t, prune(nil, dispatchers.NewParsedFlags([]string{"--older-than=1y", "--status=gone"}), deps), "invalid status")
+}
+
+func TestApplyRetention(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		useTestConfig(t, "")
+		path := pruneTestStore(t, now)
+		var out []string
+		applyRetention(openTestStore(t, path).DB(), pruneTestDeps(path, now, &out))
+		require.Len(t, remainingCommits(t, path), 4)
+	})
+
+	t.Run("keeps unexported events", func(t *testing.T) {
+		useTestConfig(t, "retention_days=365\n")
+		path := pruneTestStore(t, now)
+		var out []string
+		applyRetention(openTestStore(t, path).DB(), pruneTestDeps(path, now, &out))
+		require.ElementsMatch(t, []string{"old-pending", "old-orphaned", "recent"}, remainingCommits(t, path))
+	})
+}
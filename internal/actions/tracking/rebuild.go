@@ -0,0 +1,259 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// notExported lists what the CSV export never contains, so a rebuild cannot
+// bring it back.
+var notExported = []string{
+	"checkout and rewrite events",
+	"rewrite pairs and pushed refs",
+	"commit bodies (only the first line is exported)",
+}
+
+// rebuildSummary describes a rebuild, including what could not be recovered.
+type rebuildSummary struct {
+	Source       string   `json:"source"`
+	Files        int      `json:"files"`
+	Rows         int      `json:"rows"`
+	Restored     int      `json:"restored"`
+	Existing     int      `json:"already_present"`
+	Duplicates   int      `json:"duplicates"`
+	Invalid      int      `json:"invalid"`
+	OtherDevices int      `json:"other_devices"`
+	NoDetails    int      `json:"rows_without_details"`
+	TrackedRepos []string `json:"tracked_repos"`
+	UnknownPaths []string `json:"repos_without_path"`
+	NotExported  []string `json:"not_exported"`
+	DryRun       bool     `json:"dry_run"`
+}
+
+// RebuildFromExport reconstructs the database from the CSV export.
+func RebuildFromExport(args []string, flags *dispatchers.ParsedFlags) error {
+	return rebuildFromExport(args, flags, DefaultDeps())
+}
+
+func rebuildFromExport(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if !flags.Has("--from-export") {
+		return fmt.Errorf("nothing to rebuild from: use 'fp db rebuild --from-export'")
+	}
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+
+	dir := deps.GetExportRepo()
+	if len(args) > 0 {
+		dir = args[0]
+	} else if !dryRun && deps.HasRemote(dir) {
+		if err := deps.PullExportRepo(dir); err != nil {
+			log.Warn("rebuild: pull failed: %v", err)
+			if !jsonOutput {
+				_, _ = deps.Printf("Could not pull the export remote (%v); using the local copy\n", err)
+			}
+		}
+	}
+
+	files, err := exportCSVFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	summary := rebuildSummary{Source: dir, Files: len(files), NotExported: notExported, DryRun: dryRun}
+	localDevice := getHostname()
+
+	seen := make(map[string]int)
+	repoIDs := make(map[string]bool)
+	var events []store.ImportedEvent
+
+	for _, file := range files {
+		// Exports from before the device column were written on this machine
+		rows, invalid, err := readExportCSV(file, localDevice)
+		if err != nil {
+			return err
+		}
+		summary.Rows += len(rows) + invalid
+		summary.Invalid += invalid
+
+		for _, row := range rows {
+			key := fmt.Sprintf("%s:%s:%d", row.Event.RepoID, row.Event.Commit, row.Event.Source)
+			if i, ok := seen[key]; ok {
+				// Keep the commit details of whichever copy has them
+				if events[i].Metadata.AuthoredAt == "" {
+					events[i].Metadata = row.Metadata
+				}
+				summary.Duplicates++
+				continue
+			}
+			seen[key] = len(events)
+
+			if row.Event.Device == localDevice || row.Event.Device == "" {
+				row.Event.Status = store.StatusExported
+				row.Event.Device = ""
+			} else {
+				row.Event.Status = store.StatusImported
+				summary.OtherDevices++
+			}
+			repoIDs[row.Event.RepoID] = true
+			events = append(events, row)
+		}
+	}
+
+	for _, e := range events {
+		if e.Metadata.AuthoredAt == "" {
+			summary.NoDetails++
+		}
+	}
+
+	paths := rebuildRepoPaths(repoIDs, flags, deps)
+	for i := range events {
+		events[i].Event.RepoPath = paths[events[i].Event.RepoID]
+	}
+	for id := range repoIDs {
+		path, ok := paths[id]
+		if !ok {
+			summary.UnknownPaths = append(summary.UnknownPaths, id)
+			continue
+		}
+		if hooks.InspectRepo(path).FpInstalled {
+			summary.TrackedRepos = append(summary.TrackedRepos, path)
+		}
+	}
+	sort.Strings(summary.UnknownPaths)
+	sort.Strings(summary.TrackedRepos)
+
+	if !dryRun {
+		if err := restoreRebuiltEvents(events, &summary, deps); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		return output.JSON(deps.Println, summary)
+	}
+	printRebuildSummary(summary, deps)
+	return nil
+}
+
+// restoreRebuiltEvents writes the events and re-registers tracked repos, then
+// checks that every event read from the export is in the database.
+func restoreRebuiltEvents(events []store.ImportedEvent, summary *rebuildSummary, deps Deps) error {
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	summary.Restored, err = store.RestoreEvents(s.DB(), events)
+	if err != nil {
+		return fmt.Errorf("failed to restore events: %w", err)
+	}
+	summary.Existing = len(events) - summary.Restored
+
+	for _, path := range summary.TrackedRepos {
+		if err := s.AddRepo(path); err != nil {
+			return fmt.Errorf("failed to register %s: %w", path, err)
+		}
+	}
+
+	missing, err := store.CountMissingEvents(s.DB(), events)
+	if err != nil {
+		return fmt.Errorf("failed to verify the rebuild: %w", err)
+	}
+	if missing > 0 {
+		return fmt.Errorf("verification failed: %s read from the export are not in the database", pluralize(missing, "event"))
+	}
+	return nil
+}
+
+// rebuildRepoPaths finds a local path for each repository ID. Local IDs carry
+// their path; other repositories are matched against the clones found under
+// --root, when given.
+func rebuildRepoPaths(repoIDs map[string]bool, flags *dispatchers.ParsedFlags, deps Deps) map[string]string {
+	paths := make(map[string]string)
+	for id := range repoIDs {
+		if path, ok := strings.CutPrefix(id, "local:"); ok {
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				paths[id] = path
+			}
+		}
+	}
+
+	root := flags.String("--root", "")
+	if root == "" {
+		return paths
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		log.Warn("rebuild: invalid root %s: %v", root, err)
+		return paths
+	}
+
+	clones, err := scanForRepos(absRoot, flags.Int("--depth", 25))
+	if err != nil {
+		log.Warn("rebuild: scan %s: %v", absRoot, err)
+		return paths
+	}
+	for _, clone := range clones {
+		remoteURL, _ := deps.OriginURL(clone.Path)
+		id, err := deps.DeriveID(remoteURL, clone.Path)
+		if err != nil {
+			continue
+		}
+		if _, found := paths[string(id)]; repoIDs[string(id)] && !found {
+			paths[string(id)] = clone.Path
+		}
+	}
+	return paths
+}
+
+func printRebuildSummary(summary rebuildSummary, deps Deps) {
+	if summary.DryRun {
+		restorable := summary.Rows - summary.Invalid - summary.Duplicates
+		_, _ = deps.Printf("Would restore up to %s from %s in %s\n", pluralize(restorable, "event"), pluralize(summary.Files, "file"), summary.Source)
+	} else {
+		_, _ = deps.Printf("Restored %s from %s in %s\n", pluralize(summary.Restored, "event"), pluralize(summary.Files, "file"), summary.Source)
+	}
+	if summary.OtherDevices > 0 {
+		_, _ = deps.Printf("  %s recorded on other devices (kept as imported)\n", pluralize(summary.OtherDevices, "event"))
+	}
+	if summary.Existing > 0 {
+		_, _ = deps.Printf("  %s already in the database\n", pluralize(summary.Existing, "event"))
+	}
+	if summary.Duplicates > 0 {
+		_, _ = deps.Printf("  %s repeated across files\n", pluralize(summary.Duplicates, "row"))
+	}
+	if len(summary.TrackedRepos) > 0 {
+		_, _ = deps.Printf("  %s with fp hooks registered again\n", pluralize(len(summary.TrackedRepos), "repo"))
+	}
+	if !summary.DryRun {
+		_, _ = deps.Println("Verified: every event read from the export is in the database")
+	}
+
+	_, _ = deps.Println("")
+	_, _ = deps.Println("Not recoverable from the export:")
+	for _, item := range summary.NotExported {
+		_, _ = deps.Printf("  - %s\n", item)
+	}
+	if summary.NoDetails > 0 {
+		_, _ = deps.Printf("  - author and message of %s (not in their rows)\n", pluralize(summary.NoDetails, "event"))
+	}
+	if summary.Invalid > 0 {
+		_, _ = deps.Printf("  - %s that could not be read (see 'fp logs')\n", pluralize(summary.Invalid, "row"))
+	}
+	if len(summary.UnknownPaths) > 0 {
+		_, _ = deps.Printf("  - local paths of %s; pass --root <dir> to find their clones:\n", pluralize(len(summary.UnknownPaths), "repo"))
+		for _, id := range summary.UnknownPaths {
+			_, _ = deps.Printf("      %s\n", id)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package tracking
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func rebuildTestDeps(t *testing.T, exportDir string, out *[]string) (Deps, string) {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+	deps := pruneTestDeps(dbPath, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), out)
+	deps.GetExportRepo = func() string { return exportDir }
+	deps.HasRemote = func(string) bool { return false }
+	return deps, dbPath
+}
+
+func TestRebuildFromExport(t *testing.T) {
+	local := getHostname()
+	localRepo := t.TempDir()
+
+	exportDir := t.TempDir()
+	writeExportCSV(t, filepath.Join(exportDir, "commits.csv"),
+		strings.Join(csvHeader, ","),
+		"1,commit,2025-03-01T10:00:00Z,github.com/user/repo,repo,,Dev,dev@example.com,main,aaa111,,Fix parser,1,2,0,"+local,
+		"2,push,2025-03-01T11:00:00Z,local:"+localRepo+",app,,,,main,bbb222,,,,,,work-laptop",
+	)
+	// The same event in another file, without its commit details
+	writeExportCSV(t, filepath.Join(exportDir, "commits-2024.csv"),
+		"event_id,event_type,timestamp,repo_id,commit_hash,branch",
+		"1,commit,2025-03-01T10:00:00Z,github.com/user/repo,aaa111,main",
+		"2,commit,2024-05-01T10:00:00Z,github.com/user/repo,ccc333,main",
+	)
+
+	var out []string
+	deps, dbPath := rebuildTestDeps(t, exportDir, &out)
+	flags := dispatchers.NewParsedFlags([]string{"--from-export", "--json"})
+	require.NoError(t, rebuildFromExport(nil, flags, deps))
+
+	var summary rebuildSummary
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &summary))
+	require.Equal(t, 2, summary.Files)
+	require.Equal(t, 3, summary.Restored)
+	require.Equal(t, 1, summary.Duplicates)
+	require.Equal(t, 1, summary.OtherDevices)
+	require.Equal(t, 2, summary.NoDetails)
+	require.Equal(t, []string{"github.com/user/repo"}, summary.UnknownPaths)
+	require.NotEmpty(t, summary.NotExported)
+
+	events := importedEvents(t, dbPath)
+	require.Len(t, events, 3)
+	for _, e := range events {
+		switch e.Commit {
+		case "bbb222":
+			require.Equal(t, store.StatusImported, e.Status)
+			require.Equal(t, "work-laptop", e.Device)
+			require.Equal(t, localRepo, e.RepoPath)
+		default:
+			require.Equal(t, store.StatusExported, e.Status)
+			require.Empty(t, e.Device)
+		}
+	}
+}
+
+func TestRebuildFromExport_RequiresSource(t *testing.T) {
+	var out []string
+	deps, dbPath := rebuildTestDeps(t, t.TempDir(), &out)
+
+	err := rebuildFromExport(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.ErrorContains(t, err, "--from-export")
+
+	_, err = os.Stat(dbPath)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRebuildFromExport_DryRun(t *testing.T) {
+	exportDir := t.TempDir()
+	writeExportCSV(t, filepath.Join(exportDir, "commits.csv"),
+		"repo_id,commit_hash,timestamp",
+		"github.com/user/repo,aaa111,2025-03-01T10:00:00Z",
+	)
+
+	var out []string
+	deps, dbPath := rebuildTestDeps(t, exportDir, &out)
+	require.NoError(t, rebuildFromExport(nil, dispatchers.NewParsedFlags([]string{"--from-export", "--dry-run"}), deps))
+	require.Contains(t, out[0], "Would restore up to")
+
+	_, err := os.Stat(dbPath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -1,8 +1,13 @@
 package tracking
 
 import (
+	"database/sql"
+	"strings"
+
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/log"
+	repodomain "github.com/footprint-tools/cli/internal/repo"
 	"github.com/footprint-tools/cli/internal/store"
 )
 
@@ -10,13 +15,20 @@ func Record(args []string, flags *dispatchers.ParsedFlags) error {
 	return record(args, flags, DefaultDeps())
 }
 
-func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+func record(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if flags.Has("--replay") {
+		return replay(deps)
+	}
+
 	verbose := flags.Has("--verbose")
 	manual := flags.Has("--manual")
 
-	// Show note when running manually (no FP_SOURCE env var)
-	isFromHook := deps.Getenv("FP_SOURCE") != ""
-	log.Debug("record: starting (source=%s, fromHook=%v)", deps.Getenv("FP_SOURCE"), isFromHook)
+	// Hook managers (Husky, lefthook) call "fp record <hook-name> [args...]"
+	hookName, hookArgs := splitHookArgs(args)
+
+	// Show note when running manually (no FP_SOURCE env var or hook name)
+	isFromHook := deps.Getenv("FP_SOURCE") != "" || hookName != ""
+	log.Debug("record: starting (source=%s, hook=%s, fromHook=%v)", deps.Getenv("FP_SOURCE"), hookName, isFromHook)
 
 	if !isFromHook && !manual {
 		_, _ = deps.Println("Note: fp record is usually executed automatically by git hooks.")
@@ -65,12 +77,37 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	branch, _ := deps.CurrentBranch()
 	log.Debug("record: repo=%s, commit=%.7s, branch=%s, path=%s", repoID, commit, branch, repoRoot)
 
+	source := resolveSource(deps, hookName)
+
+	event := store.RepoEvent{
+		RepoID:    string(repoID),
+		RepoPath:  repoRoot,
+		Commit:    commit,
+		Branch:    branch,
+		Timestamp: deps.Now().UTC(),
+		Status:    store.StatusPending,
+		Source:    source,
+	}
+	if source == store.SourcePostCheckout {
+		applyCheckoutArgs(&event, hookArgs, deps)
+	}
+
+	pending := store.SpooledEvent{Event: event}
+	if source == store.SourcePostRewrite {
+		pending.Rewrites = readRewrites(deps)
+		pending.RewriteType = rewriteType(hookArgs)
+	}
+	if source == store.SourcePrePush {
+		pending.PushRefs = readPushRefs(repoRoot, hookArgs, deps)
+	}
+
 	db, err := deps.OpenDB(deps.DBPath())
 	if err != nil {
 		// Critical error: log it always
 		log.Error("fp record: failed to open database: %v (repo=%s, commit=%.7s)", err, repoID, commit)
+		spooled := spoolEvent(pending, err, deps)
 		if showErrors {
-			_, _ = deps.Println("could not open store db")
+			_, _ = deps.Println("could not open store db" + spoolNote(spooled))
 		}
 		return nil
 	}
@@ -79,55 +116,210 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	if err := deps.InitDB(db); err != nil {
 		// Critical error: DB initialization failed
 		log.Error("fp record: failed to initialize database: %v (repo=%s, commit=%.7s)", err, repoID, commit)
+		spooled := spoolEvent(pending, err, deps)
 		if showErrors {
-			_, _ = deps.Printf("failed to initialize database: %v\n", err)
+			_, _ = deps.Printf("failed to initialize database: %v%s\n", err, spoolNote(spooled))
 		}
 		return nil
 	}
 
-	source := resolveSource(deps)
+	repoID = repodomain.RepoID(identifyRepo(db, repoRoot, remoteURL, string(repoID), deps))
+	pending.Event.RepoID = string(repoID)
 
-	err = deps.InsertEvent(db, store.RepoEvent{
-		RepoID:    string(repoID),
-		RepoPath:  repoRoot,
-		Commit:    commit,
-		Branch:    branch,
-		Timestamp: deps.Now().UTC(),
-		Status:    store.StatusPending,
-		Source:    source,
-	})
+	checkRepoIDChange(db, repoRoot, remoteURL, string(repoID), deps)
+
+	err = writeEvent(db, pending, deps)
 
 	if err != nil {
 		// Critical error: failed to record event
 		log.Error("fp record: failed to insert event: %v (repo=%s, commit=%.7s, source=%s)", err, repoID, commit, source.String())
-	} else {
-		log.Info("record: event saved (repo=%s, commit=%.7s, source=%s)", repoID, commit, source.String())
+		spooled := spoolEvent(pending, err, deps)
+		if showErrors {
+			_, _ = deps.Printf("failed to record event: %v%s\n", err, spoolNote(spooled))
+		}
+		return nil
 	}
 
+	log.Info("record: event saved (repo=%s, commit=%.7s, source=%s)", repoID, commit, source.String())
+
 	if showErrors {
-		if err != nil {
-			_, _ = deps.Printf("failed to record event: %v\n", err)
-		} else {
-			_, _ = deps.Printf(
-				"recorded %.7s on %s (%s) [%s]\n",
-				commit,
-				branch,
-				repoID,
-				source.String(),
-			)
-		}
+		_, _ = deps.Printf(
+			"recorded %.7s on %s (%s) [%s]\n",
+			commit,
+			branch,
+			repoID,
+			source.String(),
+		)
 	}
 
+	// The database works again, so events that failed earlier can go in
+	replaySpool(db, deps)
+
 	// Check if we should auto-export
-	if err == nil {
-		maybeExport(db, deps)
-	}
+	maybeExport(db, deps)
 
 	return nil
 }
 
-func resolveSource(deps Deps) store.Source {
-	switch deps.Getenv("FP_SOURCE") {
+// writeEvent inserts an event with its rewrite pairs and pushed refs in one
+// transaction, so an event that fails is spooled whole and written nowhere.
+// Metadata snapshots are best effort and never fail the write.
+func writeEvent(db *sql.DB, pending store.SpooledEvent, deps Deps) error {
+	e := pending.Event
+	superseded, err := deps.RecordEvent(db, e, pending.RewriteType, pending.Rewrites, pending.PushRefs)
+	if err != nil {
+		log.Error("record: failed to save event: %v (repo=%s, commit=%.7s)", err, e.RepoID, e.Commit)
+		return err
+	}
+	if len(pending.Rewrites) > 0 {
+		log.Info("record: saved %d rewrites, %d events superseded (repo=%s, type=%s)", len(pending.Rewrites), superseded, e.RepoID, pending.RewriteType)
+	}
+	if len(pending.PushRefs) > 0 {
+		log.Info("record: saved %d pushed refs (repo=%s, remote=%s)", len(pending.PushRefs), e.RepoID, pending.PushRefs[0].RemoteName)
+	}
+	if metadataSnapshotsEnabled() {
+		snapshotCommitMetadata(db, pending.Event)
+	}
+	indexRecordedEvent(db, pending.Event)
+	return nil
+}
+
+// splitHookArgs separates a leading hook name from the hook arguments.
+// Installed hook scripts pass only git's arguments; hook managers usually
+// pass the hook name first.
+func splitHookArgs(args []string) (string, []string) {
+	if len(args) > 0 && hookSource(args[0]) != store.SourceManual {
+		return args[0], args[1:]
+	}
+	return "", args
+}
+
+// resolveSource determines the event source from FP_SOURCE, falling back
+// to the hook name given as the first argument.
+func resolveSource(deps Deps, hookName string) store.Source {
+	if name := deps.Getenv("FP_SOURCE"); name != "" {
+		return hookSource(name)
+	}
+	return hookSource(hookName)
+}
+
+// applyCheckoutArgs fills checkout context from post-checkout arguments:
+// <previous HEAD> <new HEAD> <flag>, where flag is 1 for a branch checkout
+// and 0 for a file checkout.
+func applyCheckoutArgs(event *store.RepoEvent, args []string, deps Deps) {
+	if len(args) < 3 {
+		return
+	}
+
+	// Git passes the null SHA as previous HEAD on the initial clone checkout
+	if !git.IsZeroSHA(args[0]) {
+		event.PreviousCommit = args[0]
+	}
+	event.BranchSwitch = args[2] == "1"
+
+	if !event.BranchSwitch {
+		return
+	}
+
+	previous, err := deps.PreviousBranch()
+	if err != nil {
+		log.Debug("record: could not resolve previous branch: %v", err)
+		return
+	}
+	// rev-parse prints HEAD when the previous checkout was detached
+	if previous != "HEAD" {
+		event.PreviousBranch = previous
+	}
+}
+
+// readRewrites reads the "<old-sha> <new-sha>" pairs git pipes to post-rewrite.
+func readRewrites(deps Deps) []store.CommitRewrite {
+	input, err := deps.ReadStdin()
+	if err != nil {
+		log.Warn("record: could not read rewrite pairs from stdin: %v", err)
+		return nil
+	}
+	return parseRewrites(input)
+}
+
+// parseRewrites parses post-rewrite stdin. Each line holds the old and new
+// SHA, optionally followed by extra info that is ignored.
+func parseRewrites(input string) []store.CommitRewrite {
+	var rewrites []store.CommitRewrite
+	for _, line := range strings.Split(input, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		rewrites = append(rewrites, store.CommitRewrite{
+			OldCommit: fields[0],
+			NewCommit: fields[1],
+		})
+	}
+	return rewrites
+}
+
+// rewriteType returns the post-rewrite type (amend or rebase), which git
+// passes as the first hook argument.
+func rewriteType(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "unknown"
+}
+
+// readPushRefs reads the ref updates git pipes to pre-push, one per line:
+// <local ref> <local sha> <remote ref> <remote sha>. The hook arguments are
+// the remote name and URL.
+func readPushRefs(repoRoot string, args []string, deps Deps) []store.PushRef {
+	input, err := deps.ReadStdin()
+	if err != nil {
+		log.Warn("record: could not read pushed refs from stdin: %v", err)
+		return nil
+	}
+
+	var remoteName, remoteURL string
+	if len(args) > 0 {
+		remoteName = args[0]
+	}
+	if len(args) > 1 {
+		remoteURL = args[1]
+	}
+
+	refs := parsePushRefs(input, remoteName, remoteURL)
+	for i := range refs {
+		count, err := deps.CountPushedCommits(repoRoot, remoteName, refs[i].LocalSHA, refs[i].RemoteSHA)
+		if err != nil {
+			log.Debug("record: could not count pushed commits for %s: %v", refs[i].RemoteRef, err)
+			continue
+		}
+		refs[i].CommitCount = count
+	}
+	return refs
+}
+
+// parsePushRefs parses pre-push stdin into ref updates for the given remote.
+func parsePushRefs(input, remoteName, remoteURL string) []store.PushRef {
+	var refs []store.PushRef
+	for _, line := range strings.Split(input, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		refs = append(refs, store.PushRef{
+			RemoteName: remoteName,
+			RemoteURL:  remoteURL,
+			LocalRef:   fields[0],
+			LocalSHA:   fields[1],
+			RemoteRef:  fields[2],
+			RemoteSHA:  fields[3],
+		})
+	}
+	return refs
+}
+
+func hookSource(name string) store.Source {
+	switch name {
 	case "post-commit":
 		return store.SourcePostCommit
 	case "post-rewrite":
@@ -3,6 +3,7 @@ package tracking
 import (
 	"database/sql"
 	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -43,6 +44,7 @@ func TestRecord_SuccessFromHook(t *testing.T) {
 		DBPath: func() string {
 			return ":memory:"
 		},
+		SpoolPath: func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
 		OpenDB: func(path string) (*sql.DB, error) {
 			db, _ := sql.Open("sqlite3", ":memory:")
 			return db, nil
@@ -50,9 +52,9 @@ func TestRecord_SuccessFromHook(t *testing.T) {
 		InitDB: func(db *sql.DB) error {
 			return nil
 		},
-		InsertEvent: func(db *sql.DB, event store.RepoEvent) error {
+		RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, _ []store.PushRef) (int64, error) {
 			insertedEvent = event
-			return nil
+			return 0, nil
 		},
 		Now: func() time.Time {
 			return fixedNow
@@ -104,6 +106,7 @@ func TestRecord_SuccessWithManualFlag(t *testing.T) {
 		DBPath: func() string {
 			return ":memory:"
 		},
+		SpoolPath: func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
 		OpenDB: func(path string) (*sql.DB, error) {
 			db, _ := sql.Open("sqlite3", ":memory:")
 			return db, nil
@@ -111,8 +114,8 @@ func TestRecord_SuccessWithManualFlag(t *testing.T) {
 		InitDB: func(db *sql.DB) error {
 			return nil
 		},
-		InsertEvent: func(db *sql.DB, event store.RepoEvent) error {
-			return nil
+		RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, _ []store.PushRef) (int64, error) {
+			return 0, nil
 		},
 		Now: func() time.Time {
 			return fixedNow
@@ -159,6 +162,7 @@ func TestRecord_SuccessWithVerboseFlag(t *testing.T) {
 		DBPath: func() string {
 			return ":memory:"
 		},
+		SpoolPath: func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
 		OpenDB: func(path string) (*sql.DB, error) {
 			db, _ := sql.Open("sqlite3", ":memory:")
 			return db, nil
@@ -166,8 +170,8 @@ func TestRecord_SuccessWithVerboseFlag(t *testing.T) {
 		InitDB: func(db *sql.DB) error {
 			return nil
 		},
-		InsertEvent: func(db *sql.DB, event store.RepoEvent) error {
-			return nil
+		RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, _ []store.PushRef) (int64, error) {
+			return 0, nil
 		},
 		Now: time.Now,
 		Println: func(a ...any) (int, error) {
@@ -308,6 +312,7 @@ func TestRecord_DifferentSourceTypes(t *testing.T) {
 				DBPath: func() string {
 					return ":memory:"
 				},
+				SpoolPath: func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
 				OpenDB: func(path string) (*sql.DB, error) {
 					db, _ := sql.Open("sqlite3", ":memory:")
 					return db, nil
@@ -315,11 +320,14 @@ func TestRecord_DifferentSourceTypes(t *testing.T) {
 				InitDB: func(db *sql.DB) error {
 					return nil
 				},
-				InsertEvent: func(db *sql.DB, event store.RepoEvent) error {
+				RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, _ []store.PushRef) (int64, error) {
 					insertedEvent = event
-					return nil
+					return 0, nil
 				},
 				Now: time.Now,
+				ReadStdin: func() (string, error) {
+					return "", nil
+				},
 				Println: func(a ...any) (int, error) {
 					return 0, nil
 				},
@@ -339,6 +347,7 @@ func TestRecord_DifferentSourceTypes(t *testing.T) {
 
 func TestRecord_DatabaseOpenError(t *testing.T) {
 	var capturedPrintln string
+	spoolPath := filepath.Join(t.TempDir(), "spool.jsonl")
 
 	deps := Deps{
 		Getenv: func(key string) string {
@@ -363,9 +372,11 @@ func TestRecord_DatabaseOpenError(t *testing.T) {
 		DBPath: func() string {
 			return "/invalid/path/db.sqlite"
 		},
+		SpoolPath: func() string { return spoolPath },
 		OpenDB: func(path string) (*sql.DB, error) {
 			return nil, errors.New("failed to open db")
 		},
+		Now: time.Now,
 		Println: func(a ...any) (int, error) {
 			capturedPrintln = "called"
 			return 0, nil
@@ -388,6 +399,13 @@ func TestRecord_DatabaseOpenError(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotEmpty(t, capturedPrintln)
+
+	// Both attempts were kept for replay
+	spooled, err := store.ReadSpool(spoolPath)
+	require.NoError(t, err)
+	require.Len(t, spooled, 2)
+	require.Equal(t, "abc123", spooled[0].Event.Commit)
+	require.Equal(t, "failed to open db", spooled[0].Error)
 }
 
 func TestRecord_InsertEventError(t *testing.T) {
@@ -416,6 +434,7 @@ func TestRecord_InsertEventError(t *testing.T) {
 		DBPath: func() string {
 			return ":memory:"
 		},
+		SpoolPath: func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
 		OpenDB: func(path string) (*sql.DB, error) {
 			db, _ := sql.Open("sqlite3", ":memory:")
 			return db, nil
@@ -423,8 +442,8 @@ func TestRecord_InsertEventError(t *testing.T) {
 		InitDB: func(db *sql.DB) error {
 			return nil
 		},
-		InsertEvent: func(db *sql.DB, event store.RepoEvent) error {
-			return errors.New("insert failed")
+		RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, _ []store.PushRef) (int64, error) {
+			return 0, errors.New("insert failed")
 		},
 		Now: time.Now,
 		Println: func(a ...any) (int, error) {
@@ -470,8 +489,238 @@ func TestResolveSource(t *testing.T) {
 				},
 			}
 
-			got := resolveSource(deps)
+			got := resolveSource(deps, "")
 			require.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestRecord_PostCheckoutArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantPrevCommit string
+		wantPrevBranch string
+		wantSwitch     bool
+	}{
+		{
+			name:           "branch switch",
+			args:           []string{"1111111111", "abc123def456", "1"},
+			wantPrevCommit: "1111111111",
+			wantPrevBranch: "feature",
+			wantSwitch:     true,
+		},
+		{
+			name:           "file checkout",
+			args:           []string{"abc123def456", "abc123def456", "0"},
+			wantPrevCommit: "abc123def456",
+		},
+		{
+			name:       "initial clone uses null sha",
+			args:       []string{"0000000000000000000000000000000000000000", "abc123def456", "1"},
+			wantSwitch: true,
+			// previous branch is still resolved from the reflog stub
+			wantPrevBranch: "feature",
+		},
+		{
+			name: "missing args",
+			args: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var insertedEvent store.RepoEvent
+
+			deps := Deps{
+				Getenv: func(key string) string {
+					if key == "FP_SOURCE" {
+						return "post-checkout"
+					}
+					return ""
+				},
+				GitIsAvailable: func() bool { return true },
+				RepoRoot:       func(path string) (string, error) { return "/path/to/repo", nil },
+				OriginURL:      func(repoRoot string) (string, error) { return "", nil },
+				DeriveID: func(remoteURL, repoRoot string) (repo.RepoID, error) {
+					return "local:/path/to/repo", nil
+				},
+				HeadCommit:     func() (string, error) { return "abc123def456", nil },
+				CurrentBranch:  func() (string, error) { return "main", nil },
+				PreviousBranch: func() (string, error) { return "feature", nil },
+				DBPath:         func() string { return ":memory:" },
+				SpoolPath:      func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
+				OpenDB: func(path string) (*sql.DB, error) {
+					return sql.Open("sqlite3", ":memory:")
+				},
+				InitDB: func(db *sql.DB) error { return nil },
+				RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, _ []store.PushRef) (int64, error) {
+					insertedEvent = event
+					return 0, nil
+				},
+				Now:     time.Now,
+				Println: func(a ...any) (int, error) { return 0, nil },
+				Printf:  func(format string, a ...any) (int, error) { return 0, nil },
+			}
+
+			err := record(tt.args, dispatchers.NewParsedFlags([]string{}), deps)
+
+			require.NoError(t, err)
+			require.Equal(t, store.SourcePostCheckout, insertedEvent.Source)
+			require.Equal(t, tt.wantPrevCommit, insertedEvent.PreviousCommit)
+			require.Equal(t, tt.wantPrevBranch, insertedEvent.PreviousBranch)
+			require.Equal(t, tt.wantSwitch, insertedEvent.BranchSwitch)
+		})
+	}
+}
+
+func TestSplitHookArgs(t *testing.T) {
+	name, args := splitHookArgs([]string{"post-checkout", "aaa", "bbb", "1"})
+	require.Equal(t, "post-checkout", name)
+	require.Equal(t, []string{"aaa", "bbb", "1"}, args)
+
+	name, args = splitHookArgs([]string{"aaa", "bbb", "1"})
+	require.Empty(t, name)
+	require.Equal(t, []string{"aaa", "bbb", "1"}, args)
+
+	name, args = splitHookArgs(nil)
+	require.Empty(t, name)
+	require.Empty(t, args)
+}
+
+func TestResolveSource_HookNameFallback(t *testing.T) {
+	deps := Deps{Getenv: func(string) string { return "" }}
+	require.Equal(t, store.SourcePostCheckout, resolveSource(deps, "post-checkout"))
+
+	deps = Deps{Getenv: func(string) string { return "post-merge" }}
+	require.Equal(t, store.SourcePostMerge, resolveSource(deps, "post-checkout"))
+}
+
+func TestRecord_PostRewriteSavesPairs(t *testing.T) {
+	var (
+		insertedEvent  store.RepoEvent
+		gotType        string
+		gotRewrites    []store.CommitRewrite
+		rewritesCalled bool
+	)
+
+	deps := Deps{
+		Getenv: func(key string) string {
+			if key == "FP_SOURCE" {
+				return "post-rewrite"
+			}
+			return ""
+		},
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(path string) (string, error) { return "/path/to/repo", nil },
+		OriginURL:      func(repoRoot string) (string, error) { return "", nil },
+		DeriveID: func(remoteURL, repoRoot string) (repo.RepoID, error) {
+			return "local:/path/to/repo", nil
+		},
+		HeadCommit:    func() (string, error) { return "bbb222", nil },
+		CurrentBranch: func() (string, error) { return "main", nil },
+		DBPath:        func() string { return ":memory:" },
+		SpoolPath:     func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
+		OpenDB: func(path string) (*sql.DB, error) {
+			return sql.Open("sqlite3", ":memory:")
+		},
+		InitDB: func(db *sql.DB) error { return nil },
+		RecordEvent: func(db *sql.DB, event store.RepoEvent, rewriteType string, rewrites []store.CommitRewrite, _ []store.PushRef) (int64, error) {
+			insertedEvent = event
+			rewritesCalled = true
+			gotType = rewriteType
+			gotRewrites = rewrites
+			return int64(len(rewrites)), nil
+		},
+		ReadStdin: func() (string, error) {
+			return "aaa111 bbb222\nccc333 ddd444 extra\n\n", nil
+		},
+		Now:     time.Now,
+		Println: func(a ...any) (int, error) { return 0, nil },
+		Printf:  func(format string, a ...any) (int, error) { return 0, nil },
+	}
+
+	err := record([]string{"rebase"}, dispatchers.NewParsedFlags([]string{}), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, store.SourcePostRewrite, insertedEvent.Source)
+	require.True(t, rewritesCalled)
+	require.Equal(t, "rebase", gotType)
+	require.Equal(t, []store.CommitRewrite{
+		{OldCommit: "aaa111", NewCommit: "bbb222"},
+		{OldCommit: "ccc333", NewCommit: "ddd444"},
+	}, gotRewrites)
+}
+
+func TestParseRewrites(t *testing.T) {
+	require.Empty(t, parseRewrites(""))
+	require.Empty(t, parseRewrites("garbage\n"))
+	require.Equal(t, []store.CommitRewrite{
+		{OldCommit: "a1", NewCommit: "b1"},
+	}, parseRewrites("a1 b1\n"))
+}
+
+func TestRecord_PrePushSavesRefs(t *testing.T) {
+	var (
+		gotRefs     []store.PushRef
+		countedRepo string
+	)
+
+	deps := Deps{
+		Getenv: func(key string) string {
+			if key == "FP_SOURCE" {
+				return "pre-push"
+			}
+			return ""
+		},
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(path string) (string, error) { return "/path/to/repo", nil },
+		OriginURL:      func(repoRoot string) (string, error) { return "", nil },
+		DeriveID: func(remoteURL, repoRoot string) (repo.RepoID, error) {
+			return "local:/path/to/repo", nil
+		},
+		HeadCommit:    func() (string, error) { return "bbb222", nil },
+		CurrentBranch: func() (string, error) { return "main", nil },
+		CountPushedCommits: func(repoPath, remote, localSHA, remoteSHA string) (int, error) {
+			countedRepo = repoPath
+			if localSHA == "bbb222" {
+				return 2, nil
+			}
+			return 0, errors.New("unknown object")
+		},
+		DBPath:    func() string { return ":memory:" },
+		SpoolPath: func() string { return filepath.Join(t.TempDir(), "spool.jsonl") },
+		OpenDB: func(path string) (*sql.DB, error) {
+			return sql.Open("sqlite3", ":memory:")
+		},
+		InitDB: func(db *sql.DB) error { return nil },
+		RecordEvent: func(db *sql.DB, event store.RepoEvent, _ string, _ []store.CommitRewrite, refs []store.PushRef) (int64, error) {
+			gotRefs = refs
+			return 0, nil
+		},
+		ReadStdin: func() (string, error) {
+			return "refs/heads/main bbb222 refs/heads/main aaa111\n" +
+				"refs/heads/topic ccc333 refs/heads/topic ddd444\n", nil
+		},
+		Now:     time.Now,
+		Println: func(a ...any) (int, error) { return 0, nil },
+		Printf:  func(format string, a ...any) (int, error) { return 0, nil },
+	}
+
+	err := record([]string{"origin", "git@github.com:user/repo.git"}, dispatchers.NewParsedFlags([]string{}), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, "/path/to/repo", countedRepo)
+	require.Len(t, gotRefs, 2)
+	require.Equal(t, store.PushRef{
+		RemoteName:  "origin",
+		RemoteURL:   "git@github.com:user/repo.git",
+		LocalRef:    "refs/heads/main",
+		LocalSHA:    "bbb222",
+		RemoteRef:   "refs/heads/main",
+		RemoteSHA:   "aaa111",
+		CommitCount: 2,
+	}, gotRefs[0])
+	// Count failures are logged and leave the count at zero
+	require.Equal(t, 0, gotRefs[1].CommitCount)
+}
@@ -0,0 +1,187 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	repodomain "github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// ReposRelink moves the history of a repository to the id it derives after
+// its remote moved (an organization rename or a migration to another host).
+func ReposRelink(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposRelink(args, flags, DefaultDeps())
+}
+
+func reposRelink(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 2 {
+		return usage.MissingArgument("new-id")
+	}
+	oldID := strings.TrimSpace(args[0])
+	newID := strings.TrimSpace(args[1])
+	if newID == "" {
+		return usage.MissingArgument("new-id")
+	}
+	if oldID == newID {
+		return fmt.Errorf("old and new repo ids are the same: %s", oldID)
+	}
+
+	allStatuses := flags.Has("--all-statuses")
+	rewriteExport := flags.Has("--rewrite-export")
+	if rewriteExport && !allStatuses {
+		// Exported events left under the old id would not match the rewritten rows
+		return fmt.Errorf("--rewrite-export needs --all-statuses so exported events move with their rows")
+	}
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	if err := requireKnownRepo(db, oldID); err != nil {
+		return err
+	}
+
+	result, err := store.RelinkRepo(db, oldID, newID, allStatuses, deps.Now())
+	if err != nil {
+		return fmt.Errorf("failed to relink %s: %w", oldID, err)
+	}
+	log.Info("repos relink: %s -> %s (moved=%d, merged=%d, remaining=%d)", oldID, newID, result.Moved, result.Merged, result.Remaining)
+
+	_, _ = deps.Printf("Relinked %s to %s\n", oldID, newID)
+	_, _ = deps.Printf("  %s moved\n", pluralize(int(result.Moved), "event"))
+	if result.Merged > 0 {
+		_, _ = deps.Printf("  %s already recorded under the new id\n", pluralize(int(result.Merged), "event"))
+	}
+	if result.TrackedRepos > 0 {
+		_, _ = deps.Printf("  %s updated\n", pluralize(int(result.TrackedRepos), "tracked clone"))
+	}
+	if result.Remaining > 0 {
+		_, _ = deps.Printf("  %s left under the old id; move them with --all-statuses\n", pluralize(int(result.Remaining), "exported event"))
+	}
+
+	if !rewriteExport {
+		return nil
+	}
+
+	files, err := rewriteExportRepoID(deps.GetExportRepo(), oldID, newID, deps)
+	if err != nil {
+		return fmt.Errorf("events were relinked but the export was not rewritten: %w", err)
+	}
+	_, _ = deps.Printf("  %s rewritten\n", pluralize(files, "export file"))
+	return nil
+}
+
+// rewriteExportRepoID replaces oldID with newID in the repo_id and repo_name
+// columns of the export CSVs, located from each file's own header, and commits
+// the result. Rows already exported under newID win over the old copy of the
+// same event. Returns the number of files changed.
+func rewriteExportRepoID(exportRepo, oldID, newID string, deps Deps) (int, error) {
+	if _, err := os.Stat(exportRepo); err != nil {
+		return 0, fmt.Errorf("no export repository at %s", exportRepo)
+	}
+	if err := checkGitState(exportRepo); err != nil {
+		return 0, err
+	}
+	if deps.HasRemote(exportRepo) {
+		if err := deps.PullExportRepo(exportRepo); err != nil {
+			log.Warn("repos relink: could not sync export with remote, continuing offline: %v", err)
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(exportRepo, "commits*.csv"))
+	if err != nil {
+		return 0, err
+	}
+
+	var changed []string
+	for _, path := range paths {
+		header, err := readCSVHeader(path)
+		if err != nil {
+			return 0, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+		}
+		repoIdx, commitIdx := findColumnIndices(header)
+		if repoIdx < 0 || commitIdx < 0 {
+			repoIdx, commitIdx = getDefaultColumnIndices()
+		}
+		typeIdx := findEventTypeIndex(header)
+		// repo_name falls back to the repo ID when there is no alias or path
+		nameIdx := slices.Index(header, "repo_name")
+
+		records, err := loadCSVRecords(path)
+		if err != nil {
+			return 0, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+		}
+
+		rewritten := make(map[string][]string, len(records))
+		moved := false
+		for key, line := range records {
+			if line[repoIdx] != oldID {
+				rewritten[key] = line
+				continue
+			}
+			line[repoIdx] = newID
+			if nameIdx >= 0 && nameIdx < len(line) && line[nameIdx] == oldID {
+				line[nameIdx] = newID
+			}
+			key = rowKey(line, repoIdx, commitIdx, typeIdx)
+			if _, exists := records[key]; !exists {
+				rewritten[key] = line
+			}
+			moved = true
+		}
+		if !moved {
+			continue
+		}
+
+		if err := writeCSVSorted(path, rewritten); err != nil {
+			return 0, fmt.Errorf("write %s: %w", filepath.Base(path), err)
+		}
+		changed = append(changed, filepath.Base(path))
+	}
+
+	if err := commitExportChanges(exportRepo, changed); err != nil {
+		return 0, err
+	}
+	if len(changed) > 0 && deps.HasRemote(exportRepo) {
+		if err := deps.PushExportRepo(exportRepo); err != nil {
+			log.Warn("repos relink: failed to push rewritten export, it will go out with the next export: %v", err)
+		}
+	}
+	return len(changed), nil
+}
+
+// checkRepoIDChange warns when a registered clone now derives a different
+// repo id than the one it was registered with, which happens when its remote
+// moves. New events go to the new id; the notice explains how to bring the
+// old history along. Clones registered under the id older fp versions gave
+// the same remote URL move their pending events right away, as nothing
+// moved but the parsing. Lookup failures are ignored: this is only a hint.
+func checkRepoIDChange(db *sql.DB, repoRoot, remoteURL, repoID string, deps Deps) {
+	registered, err := store.TrackedRepoID(db, repoRoot)
+	if err != nil {
+		log.Debug("record: could not look up tracked repo %s: %v", repoRoot, err)
+		return
+	}
+	if registered == "" || registered == repoID {
+		return
+	}
+
+	if former, ok := repodomain.FormerRemoteID(remoteURL); ok && string(former) == registered {
+		migrateLegacyEvents(db, repoRoot, registered, repoID, deps)
+		return
+	}
+
+	log.Warn("record: repo id of %s changed from %s to %s", repoRoot, registered, repoID)
+	_, _ = deps.Printf("fp: the repo id of this clone changed from %s to %s (did its remote move?)\n", registered, repoID)
+	_, _ = deps.Printf("fp: keep its history together with: fp repos relink %s %s\n", registered, repoID)
+}
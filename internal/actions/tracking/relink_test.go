@@ -0,0 +1,172 @@
+package tracking
+
+import (
+	"encoding/csv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const testMovedAPIRepo = "github.com/acme-labs/platform-services-api"
+
+func TestReposRelink(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := repoMetaTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, reposRelink([]string{testAPIRepo, testMovedAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{
+		"Relinked " + testAPIRepo + " to " + testMovedAPIRepo + "\n",
+		"  1 event moved\n",
+	}, out)
+
+	events, err := store.ListEvents(openTestStore(t, path).DB(), store.EventFilter{})
+	require.NoError(t, err)
+	var repoIDs []string
+	for _, e := range events {
+		repoIDs = append(repoIDs, e.RepoID)
+	}
+	require.ElementsMatch(t, []string{testMovedAPIRepo, testWebRepo}, repoIDs)
+
+	require.ErrorContains(t, reposRelink([]string{testAPIRepo, testMovedAPIRepo}, dispatchers.NewParsedFlags(nil), deps), "unknown repository")
+	require.ErrorContains(t, reposRelink([]string{testWebRepo, testWebRepo}, dispatchers.NewParsedFlags(nil), deps), "the same")
+	require.ErrorContains(t, reposRelink([]string{testWebRepo, "github.com/acme/site"}, dispatchers.NewParsedFlags([]string{"--rewrite-export"}), deps), "--all-statuses")
+}
+
+func TestReposRelink_MergesAndLeavesExported(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	exported := testEvent(testAPIRepo, "ccc3333333", now.Add(-3*time.Hour))
+	exported.Status = store.StatusExported
+	path := newTestStoreFile(t,
+		testEvent(testAPIRepo, "aaa1111111", now.Add(-time.Hour)),
+		// Recorded again after the remote moved, before the relink
+		testEvent(testMovedAPIRepo, "aaa1111111", now.Add(-time.Hour)),
+		exported,
+	)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, reposRelink([]string{testAPIRepo, testMovedAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{
+		"Relinked " + testAPIRepo + " to " + testMovedAPIRepo + "\n",
+		"  0 events moved\n",
+		"  1 event already recorded under the new id\n",
+		"  1 exported event left under the old id; move them with --all-statuses\n",
+	}, out)
+}
+
+func TestRewriteExportRepoID(t *testing.T) {
+	useTestConfig(t, "")
+	exportDir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+
+	// Configure git user for CI environment (no global config)
+	for _, kv := range [][]string{{"user.name", "Test User"}, {"user.email", "test@example.com"}} {
+		cmd := exec.Command("git", "config", kv[0], kv[1])
+		cmd.Dir = exportDir
+		_ = cmd.Run()
+	}
+
+	ts := time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC)
+	events := []store.RepoEvent{
+		{ID: 1, RepoID: testAPIRepo, Commit: "aaa111", Branch: "main", Timestamp: ts, Source: store.SourcePostCommit},
+		{ID: 2, RepoID: testAPIRepo, Commit: "bbb222", Branch: "main", Timestamp: ts.Add(time.Hour), Source: store.SourcePostCommit},
+		// Already exported under the new id from another branch; this copy wins
+		{ID: 3, RepoID: testMovedAPIRepo, Commit: "bbb222", Branch: "release", Timestamp: ts.Add(time.Hour), Source: store.SourcePostCommit},
+		{ID: 4, RepoID: testWebRepo, Commit: "ccc333", Branch: "main", Timestamp: ts.Add(2 * time.Hour), Source: store.SourcePostCommit},
+	}
+	deps := Deps{
+		Now:       func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) },
+		HasRemote: func(string) bool { return false },
+	}
+	_, _, err := exportAllEvents(exportDir, events, nil, nil, nil, nil, deps)
+	require.NoError(t, err)
+
+	files, err := rewriteExportRepoID(exportDir, testAPIRepo, testMovedAPIRepo, deps)
+	require.NoError(t, err)
+	require.Equal(t, 1, files)
+
+	file, err := os.Open(filepath.Join(exportDir, "commits.csv"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	require.Equal(t, csvHeader, records[0])
+	var rows []string
+	for _, r := range records[1:] {
+		rows = append(rows, strings.Join([]string{r[colRepoID], r[colRepoName], r[colCommitHash], r[colBranch]}, " "))
+	}
+	require.Equal(t, []string{
+		testMovedAPIRepo + " " + testMovedAPIRepo + " aaa111 main",
+		testMovedAPIRepo + " " + testMovedAPIRepo + " bbb222 release",
+		testWebRepo + " " + testWebRepo + " ccc333 main",
+	}, rows)
+}
+
+func TestCheckRepoIDChange(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := repoMetaTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	s := openTestStore(t, path)
+	_, err := s.DB().Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES (?, '/src/api')`, testAPIRepo)
+	require.NoError(t, err)
+
+	checkRepoIDChange(s.DB(), "/src/api", "", testAPIRepo, deps)
+	checkRepoIDChange(s.DB(), "/src/untracked", "", testMovedAPIRepo, deps)
+	require.Empty(t, out)
+
+	checkRepoIDChange(s.DB(), "/src/api", "", testMovedAPIRepo, deps)
+	require.Equal(t, []string{
+		"fp: the repo id of this clone changed from " + testAPIRepo + " to " + testMovedAPIRepo + " (did its remote move?)\n",
+		"fp: keep its history together with: fp repos relink " + testAPIRepo + " " + testMovedAPIRepo + "\n",
+	}, out)
+}
+
+func TestCheckRepoIDChange_FormerRemoteID(t *testing.T) {
+	const (
+		remoteURL = "https://bot@git.acme.dev:8443/team/api.git"
+		formerID  = "bot@git.acme.dev:8443/team/api"
+		repoID    = "git.acme.dev/team/api"
+	)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var out []string
+	deps := pruneTestDeps(filepath.Join(t.TempDir(), "store.db"), now, &out)
+
+	s := newTestStore(t)
+	db := s.DB()
+	for clone, commit := range map[string]string{"/src/api": "aaa111", "/src/api-copy": "bbb222"} {
+		e := testEvent(formerID, commit, now)
+		e.RepoPath = clone
+		insertTestEvents(t, db, e)
+		_, err := db.Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES (?, ?)`, formerID, clone)
+		require.NoError(t, err)
+	}
+
+	// Only the parsing changed, so the clone's history moves without a hint
+	checkRepoIDChange(db, "/src/api", remoteURL, repoID, deps)
+	require.Empty(t, out)
+
+	e, err := store.FindEvent(db, "aaa111")
+	require.NoError(t, err)
+	require.Equal(t, repoID, e.RepoID)
+	tracked, err := store.TrackedRepoID(db, "/src/api")
+	require.NoError(t, err)
+	require.Equal(t, repoID, tracked)
+
+	// The other clone moves when it records
+	e, err = store.FindEvent(db, "bbb222")
+	require.NoError(t, err)
+	require.Equal(t, formerID, e.RepoID)
+}
@@ -0,0 +1,308 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// maxRepoNameLength keeps aliases and group names short enough for the
+// repository columns of the interactive views.
+const maxRepoNameLength = 40
+
+// repoAliases maps repository ids to the aliases set with 'fp repos alias'.
+type repoAliases map[string]string
+
+// id returns the alias of a repository, or its id when it has none.
+func (a repoAliases) id(repoID string) string {
+	if alias, ok := a[repoID]; ok {
+		return alias
+	}
+	return repoID
+}
+
+// label returns the short repository name used by the interactive views:
+// the alias, or the name of the repository folder. Imported events have no
+// folder and show their ID.
+func (a repoAliases) label(e store.RepoEvent) string {
+	if alias, ok := a[e.RepoID]; ok {
+		return alias
+	}
+	if e.RepoPath == "" {
+		return e.RepoID
+	}
+	return filepath.Base(e.RepoPath)
+}
+
+// loadRepoAliases returns the repository aliases. Aliases only change how
+// repositories are shown, so a failure is logged and ids are shown instead.
+func loadRepoAliases(db *sql.DB) repoAliases {
+	aliases, err := store.RepoAliases(db)
+	if err != nil {
+		log.Warn("could not read repository aliases: %v", err)
+		return nil
+	}
+	return aliases
+}
+
+// groupFilter validates a --group value. Unknown groups are an error rather
+// than an empty result, since they are most likely a typo.
+func groupFilter(db *sql.DB, group string) (*string, error) {
+	ids, err := store.GroupRepoIDs(db, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group %s: %w", group, err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no repositories in group '%s': add some with 'fp repos group add %s <repo-id>'", group, group)
+	}
+	return &group, nil
+}
+
+// ReposAlias sets, shows or clears the display alias of a repository.
+func ReposAlias(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposAlias(args, flags, DefaultDeps())
+}
+
+func reposAlias(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	clearAlias := flags.Has("--clear")
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	if len(args) == 0 {
+		if clearAlias {
+			return usage.MissingArgument("repo-id")
+		}
+		return listRepoMeta(db, flags.Has("--json"), deps)
+	}
+
+	repoID := args[0]
+	if err := requireKnownRepo(db, repoID); err != nil {
+		return err
+	}
+	aliases, err := store.RepoAliases(db)
+	if err != nil {
+		return fmt.Errorf("failed to read aliases: %w", err)
+	}
+
+	switch {
+	case clearAlias:
+		if len(args) > 1 {
+			return fmt.Errorf("--clear takes no alias")
+		}
+		if err := store.SetRepoAlias(db, repoID, "", deps.Now()); err != nil {
+			return fmt.Errorf("failed to clear alias: %w", err)
+		}
+		_, _ = deps.Printf("Cleared the alias of %s\n", repoID)
+	case len(args) > 1:
+		alias, err := normalizeRepoName("alias", strings.Join(args[1:], " "))
+		if err != nil {
+			return err
+		}
+		for id, existing := range aliases {
+			if existing == alias && id != repoID {
+				return fmt.Errorf("alias '%s' is already used by %s", alias, id)
+			}
+		}
+		if err := store.SetRepoAlias(db, repoID, alias, deps.Now()); err != nil {
+			return fmt.Errorf("failed to set alias: %w", err)
+		}
+		_, _ = deps.Printf("%s is now shown as %s\n", repoID, alias)
+	case aliases[repoID] == "":
+		_, _ = deps.Printf("%s has no alias\n", repoID)
+	default:
+		_, _ = deps.Println(aliases[repoID])
+	}
+	return nil
+}
+
+// ReposGroupAdd moves repositories into a group.
+func ReposGroupAdd(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposGroupAdd(args, flags, DefaultDeps())
+}
+
+func reposGroupAdd(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 2 {
+		return usage.MissingArgument("repo-id")
+	}
+	group, err := normalizeRepoName("group name", args[0])
+	if err != nil {
+		return err
+	}
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	repoIDs := args[1:]
+	for _, repoID := range repoIDs {
+		if err := requireKnownRepo(db, repoID); err != nil {
+			return err
+		}
+	}
+
+	current := make(map[string]string)
+	meta, err := store.ListRepoMeta(db)
+	if err != nil {
+		return fmt.Errorf("failed to read groups: %w", err)
+	}
+	for _, m := range meta {
+		current[m.RepoID] = m.Group
+	}
+
+	if err := store.SetRepoGroup(db, repoIDs, group, deps.Now()); err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+
+	for _, repoID := range repoIDs {
+		if previous := current[repoID]; previous != "" && previous != group {
+			_, _ = deps.Printf("Moved %s from %s\n", repoID, previous)
+		}
+	}
+	_, _ = deps.Printf("Added %s to %s\n", pluralize(len(repoIDs), "repo"), group)
+	return nil
+}
+
+// ReposGroupRemove takes repositories out of a group.
+func ReposGroupRemove(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposGroupRemove(args, flags, DefaultDeps())
+}
+
+func reposGroupRemove(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 2 {
+		return usage.MissingArgument("repo-id")
+	}
+	group := strings.TrimSpace(args[0])
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	members, err := store.GroupRepoIDs(db, group)
+	if err != nil {
+		return fmt.Errorf("failed to read group %s: %w", group, err)
+	}
+
+	var removed []string
+	for _, repoID := range args[1:] {
+		if !slices.Contains(members, repoID) {
+			_, _ = deps.Printf("%s is not in %s\n", repoID, group)
+			continue
+		}
+		removed = append(removed, repoID)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	if err := store.SetRepoGroup(db, removed, "", deps.Now()); err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+	_, _ = deps.Printf("Removed %s from %s\n", pluralize(len(removed), "repo"), group)
+	return nil
+}
+
+// ReposGroupList lists groups with their repositories.
+func ReposGroupList(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposGroupList(args, flags, DefaultDeps())
+}
+
+func reposGroupList(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	return listRepoMeta(db, flags.Has("--json"), deps)
+}
+
+// listRepoMeta prints every repository with an alias or group, grouped.
+func listRepoMeta(db *sql.DB, jsonOutput bool, deps Deps) error {
+	meta, err := store.ListRepoMeta(db)
+	if err != nil {
+		return fmt.Errorf("failed to read repository aliases and groups: %w", err)
+	}
+
+	if jsonOutput {
+		type jsonRepoMeta struct {
+			RepoID string `json:"repo_id"`
+			Alias  string `json:"alias,omitempty"`
+			Group  string `json:"group,omitempty"`
+		}
+		out := make([]jsonRepoMeta, 0, len(meta))
+		for _, m := range meta {
+			out = append(out, jsonRepoMeta{RepoID: m.RepoID, Alias: m.Alias, Group: m.Group})
+		}
+		return output.JSON(deps.Println, out)
+	}
+
+	if len(meta) == 0 {
+		_, _ = deps.Println("No aliases or groups yet. Set one with: fp repos alias <repo-id> <name>")
+		return nil
+	}
+
+	group := "\x00"
+	for _, m := range meta {
+		if m.Group != group {
+			group = m.Group
+			if group == "" {
+				_, _ = deps.Println(style.Header("(no group)"))
+			} else {
+				_, _ = deps.Println(style.Header(group))
+			}
+		}
+		line := "  " + m.RepoID
+		if m.Alias != "" {
+			line += " " + style.Muted("as "+m.Alias)
+		}
+		_, _ = deps.Println(line)
+	}
+	return nil
+}
+
+// requireKnownRepo rejects repo ids with no recorded events and no tracked
+// clone, which are most likely typos.
+func requireKnownRepo(db *sql.DB, repoID string) error {
+	ok, err := store.RepoIDExists(db, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", repoID, err)
+	}
+	if !ok {
+		return fmt.Errorf("unknown repository '%s': use an id shown by 'fp activity'", repoID)
+	}
+	return nil
+}
+
+// normalizeRepoName validates an alias or group name: non-empty, short and
+// on a single line.
+func normalizeRepoName(kind, value string) (string, error) {
+	name := strings.TrimSpace(value)
+	if name == "" {
+		return "", fmt.Errorf("invalid %s: cannot be empty", kind)
+	}
+	if len(name) > maxRepoNameLength {
+		return "", fmt.Errorf("invalid %s '%s': at most %d characters", kind, name, maxRepoNameLength)
+	}
+	if strings.IndexFunc(name, func(r rune) bool { return unicode.IsControl(r) }) >= 0 {
+		return "", fmt.Errorf("invalid %s '%s': cannot contain line breaks or control characters", kind, name)
+	}
+	return name, nil
+}
@@ -0,0 +1,156 @@
+package tracking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	testAPIRepo = "github.com/acme/platform-services-api"
+	testWebRepo = "github.com/acme/web"
+)
+
+// repoMetaTestStore creates a database file with one event in each of two repositories.
+func repoMetaTestStore(t *testing.T, now time.Time) string {
+	t.Helper()
+	return newTestStoreFile(t,
+		testEvent(testAPIRepo, "aaa1111111", now.Add(-time.Hour)),
+		testEvent(testWebRepo, "bbb2222222", now.Add(-2*time.Hour)),
+	)
+}
+
+// storedRepoMeta reads back the aliases and groups saved in the database file.
+func storedRepoMeta(t *testing.T, path string) []store.RepoMeta {
+	t.Helper()
+	meta, err := store.ListRepoMeta(openTestStore(t, path).DB())
+	require.NoError(t, err)
+	return meta
+}
+
+func TestReposAlias(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := repoMetaTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, reposAlias([]string{testAPIRepo, "api"}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{testAPIRepo + " is now shown as api\n"}, out)
+	require.Equal(t, []store.RepoMeta{{RepoID: testAPIRepo, Alias: "api"}}, storedRepoMeta(t, path))
+
+	out = nil
+	require.NoError(t, reposAlias([]string{testAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"api"}, out)
+
+	require.ErrorContains(t, reposAlias([]string{testWebRepo, "api"}, dispatchers.NewParsedFlags(nil), deps), "already used")
+	require.ErrorContains(t, reposAlias([]string{"github.com/acme/typo", "x"}, dispatchers.NewParsedFlags(nil), deps), "unknown repository")
+	require.Equal(t, []store.RepoMeta{{RepoID: testAPIRepo, Alias: "api"}}, storedRepoMeta(t, path), "refused aliases are not saved")
+
+	out = nil
+	require.NoError(t, reposAlias([]string{testAPIRepo}, dispatchers.NewParsedFlags([]string{"--clear"}), deps))
+	require.Equal(t, []string{"Cleared the alias of " + testAPIRepo + "\n"}, out)
+	require.Empty(t, storedRepoMeta(t, path))
+
+	out = nil
+	require.NoError(t, reposAlias([]string{testAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{testAPIRepo + " has no alias\n"}, out)
+
+	out = nil
+	require.NoError(t, reposAlias(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+	require.Equal(t, []string{"[]"}, out)
+}
+
+func TestReposGroup(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := repoMetaTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+
+	require.NoError(t, reposGroupAdd([]string{"payments", testAPIRepo, testWebRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"Added 2 repos to payments\n"}, out)
+
+	out = nil
+	require.NoError(t, reposGroupAdd([]string{"storefront", testWebRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{
+		"Moved " + testWebRepo + " from payments\n",
+		"Added 1 repo to storefront\n",
+	}, out)
+	require.Equal(t, []store.RepoMeta{
+		{RepoID: testAPIRepo, Group: "payments"},
+		{RepoID: testWebRepo, Group: "storefront"},
+	}, storedRepoMeta(t, path))
+
+	out = nil
+	require.NoError(t, reposGroupRemove([]string{"payments", testWebRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{testWebRepo + " is not in payments\n"}, out)
+
+	out = nil
+	require.NoError(t, reposGroupRemove([]string{"payments", testAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"Removed 1 repo from payments\n"}, out)
+	require.Equal(t, []store.RepoMeta{{RepoID: testWebRepo, Group: "storefront"}}, storedRepoMeta(t, path))
+	require.NoError(t, reposGroupAdd([]string{"payments", testAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+
+	out = nil
+	require.NoError(t, reposGroupList(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+	var groups []map[string]string
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &groups))
+	require.Equal(t, []map[string]string{
+		{"repo_id": testAPIRepo, "group": "payments"},
+		{"repo_id": testWebRepo, "group": "storefront"},
+	}, groups)
+
+	require.ErrorContains(t, reposGroupAdd([]string{"payments", "github.com/acme/typo"}, dispatchers.NewParsedFlags(nil), deps), "unknown repository")
+}
+
+func TestActivity_GroupAndAliases(t *testing.T) {
+	useTestConfig(t, "")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := repoMetaTestStore(t, now)
+	var out []string
+	deps := pruneTestDeps(path, now, &out)
+	var paged string
+	deps.Pager = func(s string) { paged = s }
+
+	require.NoError(t, reposAlias([]string{testAPIRepo, "api"}, dispatchers.NewParsedFlags(nil), deps))
+	require.NoError(t, reposGroupAdd([]string{"payments", testAPIRepo}, dispatchers.NewParsedFlags(nil), deps))
+
+	out = nil
+	require.NoError(t, activity(nil, dispatchers.NewParsedFlags([]string{"--group=payments", "--json"}), deps))
+	var events []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &events))
+	require.Len(t, events, 1)
+	require.Equal(t, testAPIRepo, events[0]["repo_id"])
+	require.Equal(t, "api", events[0]["repo_alias"])
+
+	require.NoError(t, activity(nil, dispatchers.NewParsedFlags([]string{"--oneline"}), deps))
+	require.Contains(t, paged, "api")
+	require.NotContains(t, paged, testAPIRepo)
+	require.Contains(t, paged, testWebRepo)
+
+	require.ErrorContains(t, activity(nil, dispatchers.NewParsedFlags([]string{"--group=billing"}), deps), "no repositories in group")
+}
+
+func TestRepoAliases(t *testing.T) {
+	aliases := repoAliases{testAPIRepo: "api"}
+	api := store.RepoEvent{RepoID: testAPIRepo, RepoPath: "/src/platform-services-api"}
+	web := store.RepoEvent{RepoID: testWebRepo, RepoPath: "/src/web"}
+
+	require.Equal(t, "api", aliases.id(api.RepoID))
+	require.Equal(t, testWebRepo, aliases.id(web.RepoID))
+	require.Equal(t, "api", aliases.label(api))
+	require.Equal(t, "web", aliases.label(web))
+
+	// A nil map shows ids and folder names
+	var none repoAliases
+	require.Equal(t, testAPIRepo, none.id(api.RepoID))
+	require.Equal(t, "platform-services-api", none.label(api))
+
+	record := buildRecord(api, git.CommitMetadata{}, aliases[api.RepoID])
+	require.Equal(t, "api", record[colRepoName])
+}
@@ -42,13 +42,15 @@ func reposList(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 
 	if jsonOutput {
 		type repoJSON struct {
-			Path     string `json:"path"`
-			AddedAt  string `json:"added_at,omitempty"`
-			LastSeen string `json:"last_seen,omitempty"`
+			Path      string `json:"path"`
+			RepoID    string `json:"repo_id,omitempty"`
+			RemoteURL string `json:"remote_url,omitempty"`
+			AddedAt   string `json:"added_at,omitempty"`
+			LastSeen  string `json:"last_seen,omitempty"`
 		}
 		out := make([]repoJSON, 0, len(repos))
 		for _, r := range repos {
-			out = append(out, repoJSON{Path: r.Path, AddedAt: r.AddedAt, LastSeen: r.LastSeen})
+			out = append(out, repoJSON{Path: r.Path, RepoID: r.RepoID, RemoteURL: r.RemoteURL, AddedAt: r.AddedAt, LastSeen: r.LastSeen})
 		}
 		return output.JSON(deps.Println, out)
 	}
@@ -3,7 +3,9 @@ package tracking
 import (
 	"database/sql"
 	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
@@ -28,6 +30,48 @@ func newTestStore(t *testing.T) *store.Store {
 	return store.NewWithDB(db)
 }
 
+// newTestStoreFile creates a database file holding events and returns its
+// path, for commands that open and close the database themselves.
+func newTestStoreFile(t *testing.T, events ...store.RepoEvent) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "store.db")
+	s, err := store.New(path)
+	require.NoError(t, err)
+	insertTestEvents(t, s.DB(), events...)
+	require.NoError(t, s.Close())
+	return path
+}
+
+// openTestStore opens a database file to inspect what a command left in it.
+func openTestStore(t *testing.T, path string) *store.Store {
+	t.Helper()
+	s, err := store.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// testEvent returns a pending post-commit event on main, recorded at ts in
+// the clone /src/<repository name>.
+func testEvent(repoID, commit string, ts time.Time) store.RepoEvent {
+	return store.RepoEvent{
+		RepoID:    repoID,
+		RepoPath:  "/src/" + filepath.Base(repoID),
+		Commit:    commit,
+		Branch:    "main",
+		Timestamp: ts,
+		Status:    store.StatusPending,
+		Source:    store.SourcePostCommit,
+	}
+}
+
+func insertTestEvents(t *testing.T, db *sql.DB, events ...store.RepoEvent) {
+	t.Helper()
+	for _, e := range events {
+		require.NoError(t, store.InsertEvent(db, e))
+	}
+}
+
 func TestReposList_Empty(t *testing.T) {
 	s := newTestStore(t)
 	var printedLines []string
@@ -0,0 +1,191 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// Search finds recorded commits by words in their message, branch or repository.
+func Search(args []string, flags *dispatchers.ParsedFlags) error {
+	return search(args, flags, DefaultDeps())
+}
+
+func search(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return usage.MissingArgument("query")
+	}
+	if store.MatchExpression(query) == "" {
+		return fmt.Errorf("search query '%s' has no words to match", query)
+	}
+
+	var filter store.SearchFilter
+
+	since, until, err := dateRange(flags, deps.Now)
+	if err != nil {
+		return err
+	}
+	filter.Since, filter.Until = since, until
+
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	if limitStr := flags.String("--limit", ""); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return fmt.Errorf("invalid limit value '%s': must be a positive integer", limitStr)
+		}
+		if limit <= 0 {
+			return fmt.Errorf("invalid limit value %d: must be greater than 0", limit)
+		}
+		filter.Limit = limit
+	}
+
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	indexPendingEvents(context.Background(), db)
+
+	results, err := store.SearchEvents(db, query, filter)
+	if err != nil {
+		return fmt.Errorf("failed to search events: %w", err)
+	}
+
+	jsonOutput := flags.Has("--json")
+
+	if len(results) == 0 {
+		if jsonOutput {
+			output.JSONEmpty(deps.Println)
+		} else {
+			_, _ = deps.Println("no matches")
+		}
+		return nil
+	}
+
+	aliases := loadRepoAliases(db)
+	if jsonOutput {
+		return outputSearchJSON(results, aliases, deps)
+	}
+
+	var out bytes.Buffer
+	for _, r := range results {
+		out.WriteString(formatSearchResult(r, aliases))
+		out.WriteString("\n")
+	}
+	deps.Pager(out.String())
+	return nil
+}
+
+// indexPendingEvents adds search entries for events that have none: events
+// recorded before the index existed or whose entry failed at record time.
+// Commit messages come from metadata snapshots, or from git when there is
+// none. Events whose clone is gone are indexed by branch and repo only. It
+// stops early once ctx is cancelled.
+func indexPendingEvents(ctx context.Context, db *sql.DB) {
+	events, err := store.ListUnindexedEvents(db)
+	if err != nil {
+		log.Warn("search: could not list unindexed events: %v", err)
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	snapshots := loadMetadataSnapshots(db, events)
+	indexed := 0
+	for _, e := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		meta := commitMetadataFor(e, snapshots)
+		if err := store.IndexEvent(db, e, meta.Subject, meta.Body); err != nil {
+			log.Warn("search: could not index event %d: %v", e.ID, err)
+			continue
+		}
+		indexed++
+	}
+	log.Debug("search: indexed %d events", indexed)
+}
+
+// indexRecordedEvent makes an event searchable as it is recorded, from its
+// metadata snapshot or git. Checkouts and pushes are skipped before git is
+// asked. Failures are logged; the next search or activity view indexes the
+// event instead.
+func indexRecordedEvent(db *sql.DB, e store.RepoEvent) {
+	if !store.IsSearchable(e.Source) {
+		return
+	}
+	meta := commitMetadataFor(e, loadMetadataSnapshots(db, []store.RepoEvent{e}))
+	if err := store.IndexRecordedEvent(db, e, meta.Subject, meta.Body); err != nil {
+		log.Warn("record: could not index %.7s for search: %v", e.Commit, err)
+	}
+}
+
+// formatSearchResult renders a match as: commit date repo branch subject
+func formatSearchResult(r store.SearchResult, aliases repoAliases) string {
+	return fmt.Sprintf(
+		"%s %s %s %s %s",
+		style.Header(fmt.Sprintf("%.7s", r.Event.Commit)),
+		style.Muted(format.DateTimeShort(r.Event.Timestamp)),
+		style.Muted(aliases.id(r.Event.RepoID)),
+		r.Event.Branch,
+		r.Subject,
+	)
+}
+
+func outputSearchJSON(results []store.SearchResult, aliases repoAliases, deps Deps) error {
+	type jsonResult struct {
+		ID        int64   `json:"id"`
+		RepoID    string  `json:"repo_id"`
+		RepoAlias string  `json:"repo_alias,omitempty"`
+		RepoPath  string  `json:"repo_path"`
+		Commit    string  `json:"commit"`
+		Branch    string  `json:"branch"`
+		Timestamp string  `json:"timestamp"`
+		Source    string  `json:"source"`
+		Subject   string  `json:"subject"`
+		Score     float64 `json:"score"`
+	}
+
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, jsonResult{
+			ID:        r.Event.ID,
+			RepoID:    r.Event.RepoID,
+			RepoAlias: aliases[r.Event.RepoID],
+			RepoPath:  r.Event.RepoPath,
+			Commit:    r.Event.Commit,
+			Branch:    r.Event.Branch,
+			Timestamp: r.Event.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+			Source:    r.Event.Source.String(),
+			Subject:   r.Subject,
+			Score:     r.Score,
+		})
+	}
+
+	return output.JSON(deps.Println, out)
+}
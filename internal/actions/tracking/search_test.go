@@ -0,0 +1,88 @@
+package tracking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func searchTestDeps(db *sql.DB, out *[]string) Deps {
+	deps := DefaultDeps()
+	deps.DBPath = func() string { return ":memory:" }
+	deps.OpenDB = func(string) (*sql.DB, error) { return db, nil }
+	deps.Println = func(a ...any) (int, error) {
+		*out = append(*out, a[0].(string))
+		return 0, nil
+	}
+	deps.Pager = func(s string) { *out = append(*out, s) }
+	return deps
+}
+
+// insertSnapshottedEvent records a commit with a metadata snapshot, so
+// indexing reads its message without a git repository.
+func insertSnapshottedEvent(t *testing.T, db *sql.DB, repoID, commit, subject string, ts time.Time) {
+	t.Helper()
+	e := testEvent(repoID, commit, ts)
+	e.RepoPath = t.TempDir()
+	insertTestEvents(t, db, e)
+	require.NoError(t, store.SaveCommitMetadata(db, repoID, commit, git.CommitMetadata{Subject: subject}))
+}
+
+func TestSearch_IndexesAndOutputsJSON(t *testing.T) {
+	s := newTestStore(t)
+	ts := time.Date(2025, 4, 2, 10, 0, 0, 0, time.UTC)
+	insertSnapshottedEvent(t, s.DB(), "github.com/user/api", "aaa1111", "Fix auth timeout on login", ts)
+	insertSnapshottedEvent(t, s.DB(), "github.com/user/web", "bbb2222", "Raise session timeout", ts.Add(time.Hour))
+	insertSnapshottedEvent(t, s.DB(), "github.com/user/api", "ccc3333", "Update README", ts)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--json"})
+	require.NoError(t, search([]string{"auth", "timeout"}, flags, searchTestDeps(s.DB(), &out)))
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &results))
+	require.Len(t, results, 1)
+	require.Equal(t, "aaa1111", results[0]["commit"])
+	require.Equal(t, "Fix auth timeout on login", results[0]["subject"])
+}
+
+func TestSearch_RepoFilter(t *testing.T) {
+	s := newTestStore(t)
+	ts := time.Date(2025, 4, 2, 10, 0, 0, 0, time.UTC)
+	insertSnapshottedEvent(t, s.DB(), "github.com/user/api", "aaa1111", "Fix auth timeout", ts)
+	insertSnapshottedEvent(t, s.DB(), "github.com/user/web", "bbb2222", "Raise session timeout", ts)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--repo=github.com/user/web"})
+	require.NoError(t, search([]string{"timeout"}, flags, searchTestDeps(s.DB(), &out)))
+
+	require.Len(t, out, 1)
+	require.Contains(t, out[0], "bbb2222")
+	require.NotContains(t, out[0], "aaa1111")
+}
+
+func TestSearch_NoMatches(t *testing.T) {
+	s := newTestStore(t)
+
+	var out []string
+	require.NoError(t, search([]string{"nothing"}, dispatchers.NewParsedFlags(nil), searchTestDeps(s.DB(), &out)))
+	require.Equal(t, []string{"no matches"}, out)
+}
+
+func TestSearch_InvalidInput(t *testing.T) {
+	s := newTestStore(t)
+	var out []string
+	deps := searchTestDeps(s.DB(), &out)
+
+	require.Error(t, search(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.ErrorContains(t, search([]string{"--"}, dispatchers.NewParsedFlags(nil), deps), "no words")
+	require.ErrorContains(t, search([]string{"fix"}, dispatchers.NewParsedFlags([]string{"--since=last-week"}), deps), "--since")
+	require.ErrorContains(t, search([]string{"fix"}, dispatchers.NewParsedFlags([]string{"--limit=0"}), deps), "limit")
+}
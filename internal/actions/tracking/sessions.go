@@ -0,0 +1,159 @@
+package tracking
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/sessions"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// Sessions lists work sessions: runs of events with no idle gap longer
+// than session_gap.
+func Sessions(args []string, flags *dispatchers.ParsedFlags) error {
+	return listSessions(args, flags, DefaultDeps())
+}
+
+func listSessions(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	filter := store.EventFilter{ExcludeSuperseded: true}
+
+	since, until, err := dateRange(flags, deps.Now)
+	if err != nil {
+		return err
+	}
+	filter.Since, filter.Until = since, until
+
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	gap := sessions.ConfiguredGap()
+	if gapStr := flags.String("--gap", ""); gapStr != "" {
+		var err error
+		if gap, err = sessions.ParseGap(gapStr); err != nil {
+			return err
+		}
+	}
+
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	events, err := deps.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	// Per repo by default; --global keeps switching between repos one session
+	var list []sessions.Session
+	if flags.Has("--global") {
+		list = sessions.Group(events, gap)
+	} else {
+		list = sessions.GroupByRepo(events, gap)
+	}
+
+	jsonOutput := flags.Has("--json")
+	if len(list) == 0 {
+		if jsonOutput {
+			output.JSONEmpty(deps.Println)
+		} else {
+			_, _ = deps.Println("no sessions")
+		}
+		return nil
+	}
+
+	// Newest first, like fp activity
+	slices.Reverse(list)
+
+	aliases := loadRepoAliases(db)
+	if jsonOutput {
+		return outputSessionsJSON(list, gap, aliases, deps)
+	}
+
+	var out bytes.Buffer
+	var total time.Duration
+	for _, s := range list {
+		out.WriteString(formatSession(s, aliases))
+		out.WriteString("\n")
+		total += s.Duration()
+	}
+	fmt.Fprintf(&out, "\n%s, %s in total (idle gap %s)\n",
+		pluralize(len(list), "session"), format.Duration(total), format.Duration(gap))
+
+	deps.Pager(out.String())
+	return nil
+}
+
+// formatSession returns the one-line summary of a session.
+func formatSession(s sessions.Session, aliases repoAliases) string {
+	repos := make([]string, len(s.Repos))
+	for i, id := range s.Repos {
+		repos[i] = aliases.id(id)
+	}
+
+	return fmt.Sprintf(
+		"%s %s-%s %s %s %s",
+		style.Muted(format.Date(s.Start)),
+		format.Time(s.Start),
+		format.Time(s.End),
+		style.Header(fmt.Sprintf("%8s", format.Duration(s.Duration()))),
+		style.Muted(fmt.Sprintf("%-12s", pluralize(s.Commits, "commit"))),
+		strings.Join(repos, ", "),
+	)
+}
+
+func outputSessionsJSON(list []sessions.Session, gap time.Duration, aliases repoAliases, deps Deps) error {
+	type jsonRepo struct {
+		RepoID    string `json:"repo_id"`
+		RepoAlias string `json:"repo_alias,omitempty"`
+	}
+
+	type jsonSession struct {
+		Start           string     `json:"start"`
+		End             string     `json:"end"`
+		DurationSeconds int64      `json:"duration_seconds"`
+		Events          int        `json:"events"`
+		Commits         int        `json:"commits"`
+		Repos           []jsonRepo `json:"repos"`
+		GapSeconds      int64      `json:"gap_seconds"`
+	}
+
+	out := make([]jsonSession, 0, len(list))
+	for _, s := range list {
+		js := jsonSession{
+			Start:           s.Start.Format(time.RFC3339),
+			End:             s.End.Format(time.RFC3339),
+			DurationSeconds: int64(s.Duration() / time.Second),
+			Events:          len(s.Events),
+			Commits:         s.Commits,
+			GapSeconds:      int64(gap / time.Second),
+		}
+		for _, id := range s.Repos {
+			js.Repos = append(js.Repos, jsonRepo{RepoID: id, RepoAlias: aliases[id]})
+		}
+		out = append(out, js)
+	}
+
+	return output.JSON(deps.Println, out)
+}
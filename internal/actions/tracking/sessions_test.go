@@ -0,0 +1,102 @@
+package tracking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// sessionsTestStore records two bursts of work in api and one in web that
+// overlaps the first.
+func sessionsTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s := newTestStore(t)
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	event := func(repoID, commit string, minutes int, source store.Source) store.RepoEvent {
+		e := testEvent(repoID, commit, start.Add(time.Duration(minutes)*time.Minute))
+		e.Source = source
+		return e
+	}
+	insertTestEvents(t, s.DB(),
+		event("github.com/user/api", "aaa0001", 0, store.SourcePostCommit),
+		event("github.com/user/web", "bbb0001", 10, store.SourcePostCommit),
+		event("github.com/user/api", "aaa0002", 25, store.SourcePostCommit),
+		event("github.com/user/api", "aaa0002", 40, store.SourcePrePush),
+		event("github.com/user/api", "aaa0003", 180, store.SourcePostCommit),
+	)
+	return s
+}
+
+func TestSessions_JSON(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--json"})
+	require.NoError(t, listSessions(nil, flags, searchTestDeps(s.DB(), &out)))
+
+	var got []struct {
+		Start           string `json:"start"`
+		DurationSeconds int64  `json:"duration_seconds"`
+		Events          int    `json:"events"`
+		Commits         int    `json:"commits"`
+		Repos           []struct {
+			RepoID string `json:"repo_id"`
+		} `json:"repos"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &got))
+	require.Len(t, got, 3)
+
+	// Newest first; the push extends the morning api session
+	require.Equal(t, "2025-03-10T12:00:00Z", got[0].Start)
+	require.Equal(t, "github.com/user/web", got[1].Repos[0].RepoID)
+	require.Equal(t, int64(40*60), got[2].DurationSeconds)
+	require.Equal(t, 3, got[2].Events)
+	require.Equal(t, 2, got[2].Commits)
+}
+
+func TestSessions_GlobalAndGap(t *testing.T) {
+	useTestConfig(t, "session_gap=10m\n")
+	s := sessionsTestStore(t)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--json", "--global", "--gap=20m"})
+	require.NoError(t, listSessions(nil, flags, searchTestDeps(s.DB(), &out)))
+
+	var got []struct {
+		Repos []struct {
+			RepoID string `json:"repo_id"`
+		} `json:"repos"`
+		GapSeconds int64 `json:"gap_seconds"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &got))
+	require.Len(t, got, 2)
+	require.Len(t, got[1].Repos, 2)
+	require.Equal(t, int64(20*60), got[1].GapSeconds)
+}
+
+func TestSessions_Text(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--repo=github.com/user/api"})
+	require.NoError(t, listSessions(nil, flags, searchTestDeps(s.DB(), &out)))
+
+	require.Len(t, out, 1)
+	require.Contains(t, out[0], "2 sessions, 40m in total (idle gap 30m)")
+	require.NotContains(t, out[0], "github.com/user/web")
+}
+
+func TestSessions_InvalidGap(t *testing.T) {
+	useTestConfig(t, "")
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--gap=soon"})
+	err := listSessions(nil, flags, searchTestDeps(nil, &out))
+	require.ErrorContains(t, err, "invalid session gap 'soon'")
+}
@@ -0,0 +1,194 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// spoolEvent saves an event that could not be written to the database so a
+// later record can replay it. Returns false if the event could not be saved
+// either, in which case it is lost and only the log knows about it.
+func spoolEvent(pending store.SpooledEvent, cause error, deps Deps) bool {
+	pending.Error = cause.Error()
+	pending.SpooledAt = deps.Now().UTC()
+
+	path := deps.SpoolPath()
+	if err := store.AppendSpool(path, pending); err != nil {
+		log.Error("record: event lost, could not spool it: %v (repo=%s, commit=%.7s)", err, pending.Event.RepoID, pending.Event.Commit)
+		return false
+	}
+	log.Warn("record: event spooled to %s for replay (repo=%s, commit=%.7s)", path, pending.Event.RepoID, pending.Event.Commit)
+	return true
+}
+
+// spoolNote is appended to record's error output to say where the event went.
+func spoolNote(spooled bool) string {
+	if spooled {
+		return " (saved to spool, will retry)"
+	}
+	return " (event lost: could not write the spool)"
+}
+
+// replaySpool writes spooled events to the database. Events that still
+// fail go back to the spool. Returns how many were written and how many
+// remain spooled.
+func replaySpool(db *sql.DB, deps Deps) (replayed, remaining int) {
+	path := deps.SpoolPath()
+
+	claims, err := store.ClaimSpool(path)
+	if err != nil {
+		log.Error("spool: %v", err)
+		return 0, 0
+	}
+
+	for _, claim := range claims {
+		// Unreadable lines are set aside, as the claim is removed below
+		events, err := store.ReadSpoolClaim(claim, store.BadSpoolPath(path))
+		if err != nil {
+			log.Error("spool: %v", err)
+			continue
+		}
+
+		keep := false
+		for _, pending := range events {
+			pending.Event.RepoID = mappedRepoID(db, pending.Event)
+			if err := writeEvent(db, pending, deps); err != nil {
+				remaining++
+				pending.Error = err.Error()
+				if err := store.AppendSpool(path, pending); err != nil {
+					log.Error("spool: could not respool event: %v", err)
+					keep = true
+				}
+				continue
+			}
+			replayed++
+		}
+
+		// A claim that could not be fully respooled is kept for the next replay
+		if !keep {
+			if err := os.Remove(claim); err != nil && !os.IsNotExist(err) {
+				log.Warn("spool: could not remove %s: %v", claim, err)
+			}
+		}
+	}
+
+	if replayed > 0 || remaining > 0 {
+		log.Info("spool: replayed %d events, %d still spooled", replayed, remaining)
+	}
+	return replayed, remaining
+}
+
+// replay handles 'fp record --replay'.
+func replay(deps Deps) error {
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	if err := deps.InitDB(db); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	replayed, remaining := replaySpool(db, deps)
+	switch {
+	case replayed == 0 && remaining == 0:
+		_, _ = deps.Println("Spool is empty")
+	case remaining == 0:
+		_, _ = deps.Printf("Replayed %s\n", pluralize(replayed, "event"))
+	default:
+		_, _ = deps.Printf("Replayed %s, %d still failing (see 'fp spool status')\n", pluralize(replayed, "event"), remaining)
+	}
+	return nil
+}
+
+// SpoolStatus shows events waiting in the spool.
+func SpoolStatus(args []string, flags *dispatchers.ParsedFlags) error {
+	return spoolStatus(args, flags, DefaultDeps())
+}
+
+func spoolStatus(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	path := deps.SpoolPath()
+
+	files := []string{path}
+	claims, err := store.SpoolClaims(path)
+	if err != nil {
+		return err
+	}
+	files = append(files, claims...)
+
+	var events []store.SpooledEvent
+	for _, file := range files {
+		spooled, err := store.ReadSpool(file)
+		if err != nil {
+			return err
+		}
+		events = append(events, spooled...)
+	}
+	unreadable := unreadableSpoolLines(path)
+
+	if flags.Has("--json") {
+		items := make([]map[string]any, 0, len(events))
+		for _, e := range events {
+			items = append(items, map[string]any{
+				"spooled_at": e.SpooledAt.UTC().Format(time.RFC3339),
+				"repo_id":    e.Event.RepoID,
+				"commit":     e.Event.Commit,
+				"branch":     e.Event.Branch,
+				"source":     strings.ToLower(e.Event.Source.String()),
+				"error":      e.Error,
+			})
+		}
+		return output.JSON(deps.Println, map[string]any{
+			"path":             path,
+			"events":           items,
+			"unreadable_lines": unreadable,
+		})
+	}
+
+	if unreadable > 0 {
+		_, _ = deps.Printf("%s kept in %s; fix or remove them by hand\n",
+			pluralize(unreadable, "unreadable line"), store.BadSpoolPath(path))
+	}
+	if len(events) == 0 {
+		_, _ = deps.Println("Spool is empty: every event reached the database")
+		return nil
+	}
+
+	_, _ = deps.Printf("%s waiting in %s\n", pluralize(len(events), "event"), path)
+	_, _ = deps.Println(style.Muted("They are replayed by the next successful record, or now with 'fp record --replay'."))
+	_, _ = deps.Println()
+
+	for _, e := range events {
+		_, _ = deps.Printf("  %s  %s  %.7s  %s\n",
+			style.Muted(format.DateTimeShort(e.SpooledAt.Local())),
+			e.Event.RepoID,
+			e.Event.Commit,
+			strings.ToLower(e.Event.Source.String()),
+		)
+		if e.Error != "" {
+			_, _ = deps.Printf("    %s\n", style.Error(e.Error))
+		}
+	}
+	return nil
+}
+
+// unreadableSpoolLines counts the spool lines replays set aside because
+// they could not be parsed.
+func unreadableSpoolLines(path string) int {
+	data, err := os.ReadFile(store.BadSpoolPath(path))
+	if err != nil {
+		return 0
+	}
+	return strings.Count(string(data), "\n")
+}
@@ -0,0 +1,134 @@
+package tracking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func spoolTestEvent(commit string) store.SpooledEvent {
+	return store.SpooledEvent{
+		Event: store.RepoEvent{
+			RepoID:    "github.com/user/repo",
+			RepoPath:  "/repo",
+			Commit:    commit,
+			Branch:    "main",
+			Timestamp: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			Status:    store.StatusPending,
+			Source:    store.SourcePostCommit,
+		},
+		Error:     "database is locked",
+		SpooledAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestReplaySpool(t *testing.T) {
+	useTestConfig(t, "")
+	s := newTestStore(t)
+	spoolPath := filepath.Join(t.TempDir(), "spool.jsonl")
+	require.NoError(t, store.AppendSpool(spoolPath, spoolTestEvent("aaa111")))
+	require.NoError(t, store.AppendSpool(spoolPath, spoolTestEvent("bbb222")))
+
+	deps := DefaultDeps()
+	deps.SpoolPath = func() string { return spoolPath }
+
+	replayed, remaining := replaySpool(s.DB(), deps)
+	require.Equal(t, 2, replayed)
+	require.Equal(t, 0, remaining)
+
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	spooled, err := store.ReadSpool(spoolPath)
+	require.NoError(t, err)
+	require.Empty(t, spooled)
+	claims, err := store.SpoolClaims(spoolPath)
+	require.NoError(t, err)
+	require.Empty(t, claims)
+}
+
+func TestReplaySpool_KeepsFailures(t *testing.T) {
+	useTestConfig(t, "")
+	s := newTestStore(t)
+	spoolPath := filepath.Join(t.TempDir(), "spool.jsonl")
+	require.NoError(t, store.AppendSpool(spoolPath, spoolTestEvent("aaa111")))
+	require.NoError(t, store.AppendSpool(spoolPath, spoolTestEvent("bbb222")))
+
+	deps := DefaultDeps()
+	deps.SpoolPath = func() string { return spoolPath }
+	deps.RecordEvent = func(db *sql.DB, e store.RepoEvent, rewriteType string, rewrites []store.CommitRewrite, refs []store.PushRef) (int64, error) {
+		if e.Commit == "bbb222" {
+			return 0, errors.New("disk I/O error")
+		}
+		return store.RecordEvent(db, e, rewriteType, rewrites, refs)
+	}
+
+	replayed, remaining := replaySpool(s.DB(), deps)
+	require.Equal(t, 1, replayed)
+	require.Equal(t, 1, remaining)
+
+	spooled, err := store.ReadSpool(spoolPath)
+	require.NoError(t, err)
+	require.Len(t, spooled, 1)
+	require.Equal(t, "bbb222", spooled[0].Event.Commit)
+	require.Equal(t, "disk I/O error", spooled[0].Error)
+}
+
+func TestReplaySpool_KeepsUnreadableLines(t *testing.T) {
+	useTestConfig(t, "")
+	s := newTestStore(t)
+	spoolPath := filepath.Join(t.TempDir(), "spool.jsonl")
+	require.NoError(t, os.WriteFile(spoolPath, []byte("{\"source\":\"nope\"}\n"), 0600))
+	require.NoError(t, store.AppendSpool(spoolPath, spoolTestEvent("aaa111")))
+
+	deps := DefaultDeps()
+	deps.SpoolPath = func() string { return spoolPath }
+
+	replayed, remaining := replaySpool(s.DB(), deps)
+	require.Equal(t, 1, replayed)
+	require.Equal(t, 0, remaining)
+
+	claims, err := store.SpoolClaims(spoolPath)
+	require.NoError(t, err)
+	require.Empty(t, claims)
+	kept, err := os.ReadFile(store.BadSpoolPath(spoolPath))
+	require.NoError(t, err)
+	require.Equal(t, "{\"source\":\"nope\"}\n", string(kept))
+	require.Equal(t, 1, unreadableSpoolLines(spoolPath))
+}
+
+func TestSpoolStatus_JSON(t *testing.T) {
+	spoolPath := filepath.Join(t.TempDir(), "spool.jsonl")
+	require.NoError(t, store.AppendSpool(spoolPath, spoolTestEvent("aaa111")))
+
+	var out []string
+	deps := DefaultDeps()
+	deps.SpoolPath = func() string { return spoolPath }
+	deps.Println = func(a ...any) (int, error) {
+		out = append(out, a[0].(string))
+		return 0, nil
+	}
+
+	require.NoError(t, spoolStatus(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+
+	var result struct {
+		Events     []map[string]any `json:"events"`
+		Unreadable int              `json:"unreadable_lines"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &result))
+	require.Len(t, result.Events, 1)
+	require.Equal(t, "aaa111", result.Events[0]["commit"])
+	require.Equal(t, "post-commit", result.Events[0]["source"])
+	require.Equal(t, "database is locked", result.Events[0]["error"])
+	require.Zero(t, result.Unreadable)
+}
@@ -0,0 +1,383 @@
+package tracking
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// defaultWorkDays is used when work_days is not set or invalid.
+const defaultWorkDays = "mon-fri"
+
+// Standup summarizes the commits made since the start of the previous
+// working day, per repository and branch, ready to paste into a chat.
+func Standup(args []string, flags *dispatchers.ParsedFlags) error {
+	return standup(args, flags, DefaultDeps())
+}
+
+func standup(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	now := deps.Now()
+
+	since := previousWorkDay(now, configuredWorkDays())
+	period, err := dateFlag(flags, "--since", deps.Now)
+	if err != nil {
+		return err
+	}
+	if period != nil {
+		since = period.Start
+	}
+
+	jsonOutput := flags.Has("--json")
+	plain := flags.Has("--plain")
+	if jsonOutput && plain {
+		return errors.New("use either --json or --plain")
+	}
+
+	// Amended and rebased commits only show in their final form
+	filter := store.EventFilter{Since: &since, Until: &now, ExcludeSuperseded: true}
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	events, err := deps.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	snapshots := loadMetadataSnapshots(db, events)
+	subject := func(e store.RepoEvent) string {
+		if meta, ok := snapshots[e.RepoID+":"+e.Commit]; ok && meta.Subject != "" {
+			return meta.Subject
+		}
+		return deps.CommitSubject(e.RepoPath, e.Commit)
+	}
+	repos := buildStandup(events, loadRepoAliases(db), subject)
+
+	switch {
+	case jsonOutput:
+		return outputStandupJSON(since, now, repos, deps)
+	case len(repos) == 0:
+		_, _ = deps.Println("no commits since " + standupDay(since))
+		return nil
+	case plain:
+		_, _ = deps.Printf("%s", formatStandupPlain(since, repos))
+	default:
+		_, _ = deps.Printf("%s", formatStandupMarkdown(since, repos))
+	}
+	return nil
+}
+
+// standupRepo is one repository's work in a standup, branches in the order
+// they were first committed to.
+type standupRepo struct {
+	RepoID   string
+	Name     string
+	Branches []*standupBranch
+}
+
+type standupBranch struct {
+	Name    string
+	Commits []standupCommit
+}
+
+type standupCommit struct {
+	Hash      string
+	Subject   string
+	Timestamp time.Time
+	Pushed    bool
+}
+
+// buildStandup groups events by repository and branch, oldest commit first.
+// A commit recorded by several events is listed once; checkouts are left
+// out. Pushes only mark commits as pushed: the commit pushed itself, and
+// those of its branch recorded before the push, which it carried along.
+func buildStandup(events []store.RepoEvent, aliases repoAliases, subject func(store.RepoEvent) string) []standupRepo {
+	sorted := make([]store.RepoEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	byRepo := make(map[string]*standupRepo)
+	var order []string
+	branch := func(e store.RepoEvent) *standupBranch {
+		r, ok := byRepo[e.RepoID]
+		if !ok {
+			r = &standupRepo{RepoID: e.RepoID, Name: aliases.id(e.RepoID)}
+			byRepo[e.RepoID] = r
+			order = append(order, e.RepoID)
+		}
+		for _, b := range r.Branches {
+			if b.Name == e.Branch {
+				return b
+			}
+		}
+		b := &standupBranch{Name: e.Branch}
+		r.Branches = append(r.Branches, b)
+		return b
+	}
+
+	// The last push of each branch, and every commit pushed by name
+	lastPush := make(map[string]time.Time)
+	pushedCommits := make(map[string]bool)
+	for _, e := range sorted {
+		if e.Source == store.SourcePrePush {
+			lastPush[e.RepoID+":"+e.Branch] = e.Timestamp
+			pushedCommits[e.RepoID+":"+e.Commit] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range sorted {
+		if e.Source == store.SourcePostCheckout || e.Source == store.SourcePrePush {
+			continue
+		}
+		key := e.RepoID + ":" + e.Commit
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		pushedAt, ok := lastPush[e.RepoID+":"+e.Branch]
+		pushed := pushedCommits[key] || (ok && !pushedAt.Before(e.Timestamp))
+
+		b := branch(e)
+		b.Commits = append(b.Commits, standupCommit{Hash: e.Commit, Subject: subject(e), Timestamp: e.Timestamp, Pushed: pushed})
+	}
+
+	out := make([]standupRepo, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byRepo[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// line returns a commit's subject and short hash.
+func (c standupCommit) line() string {
+	hash := c.Hash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	if c.Subject == "" {
+		return hash
+	}
+	return c.Subject + " (" + hash + ")"
+}
+
+// commitLine returns a commit's line within its branch. When only some of
+// the branch was pushed, the commits still to push say so.
+func (b standupBranch) commitLine(c standupCommit) string {
+	if !c.Pushed && b.pushedCount() > 0 {
+		return c.line() + " (not pushed)"
+	}
+	return c.line()
+}
+
+// label names a branch, or a detached HEAD.
+func (b standupBranch) label() string {
+	if b.Name == "" {
+		return "(detached)"
+	}
+	return b.Name
+}
+
+// pushedCount returns how many of a branch's commits were pushed.
+func (b standupBranch) pushedCount() int {
+	n := 0
+	for _, c := range b.Commits {
+		if c.Pushed {
+			n++
+		}
+	}
+	return n
+}
+
+// pushed notes whether a branch's commits were pushed, all or some of them.
+func (b standupBranch) pushed() string {
+	switch b.pushedCount() {
+	case 0:
+		return ""
+	case len(b.Commits):
+		return " (pushed)"
+	default:
+		return " (partly pushed)"
+	}
+}
+
+// standupDay names the first day a standup covers, e.g. "Friday, Mar 07".
+func standupDay(since time.Time) string {
+	return since.Format("Monday, Jan 02")
+}
+
+func formatStandupMarkdown(since time.Time, repos []standupRepo) string {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "**Since %s**\n", standupDay(since))
+	for _, r := range repos {
+		fmt.Fprintf(&out, "\n**%s**\n", r.Name)
+		for _, b := range r.Branches {
+			fmt.Fprintf(&out, "- `%s`%s\n", b.label(), b.pushed())
+			for _, c := range b.Commits {
+				fmt.Fprintf(&out, "  - %s\n", b.commitLine(c))
+			}
+		}
+	}
+	return out.String()
+}
+
+func formatStandupPlain(since time.Time, repos []standupRepo) string {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "Since %s\n", standupDay(since))
+	for _, r := range repos {
+		fmt.Fprintf(&out, "\n%s\n", r.Name)
+		for _, b := range r.Branches {
+			fmt.Fprintf(&out, "  %s%s\n", b.label(), b.pushed())
+			for _, c := range b.Commits {
+				fmt.Fprintf(&out, "    - %s\n", b.commitLine(c))
+			}
+		}
+	}
+	return out.String()
+}
+
+func outputStandupJSON(since, until time.Time, repos []standupRepo, deps Deps) error {
+	type jsonCommit struct {
+		Commit    string `json:"commit"`
+		Subject   string `json:"subject"`
+		Timestamp string `json:"timestamp"`
+		Pushed    bool   `json:"pushed"`
+	}
+
+	type jsonBranch struct {
+		Branch  string       `json:"branch"`
+		Pushed  bool         `json:"pushed"`
+		Commits []jsonCommit `json:"commits"`
+	}
+
+	type jsonRepo struct {
+		RepoID    string       `json:"repo_id"`
+		RepoAlias string       `json:"repo_alias,omitempty"`
+		Branches  []jsonBranch `json:"branches"`
+	}
+
+	out := struct {
+		Since string     `json:"since"`
+		Until string     `json:"until"`
+		Repos []jsonRepo `json:"repos"`
+	}{
+		Since: since.Format(time.RFC3339),
+		Until: until.Format(time.RFC3339),
+		Repos: make([]jsonRepo, 0, len(repos)),
+	}
+	for _, r := range repos {
+		jr := jsonRepo{RepoID: r.RepoID}
+		if r.Name != r.RepoID {
+			jr.RepoAlias = r.Name
+		}
+		for _, b := range r.Branches {
+			jb := jsonBranch{Branch: b.Name, Pushed: b.pushedCount() == len(b.Commits)}
+			for _, c := range b.Commits {
+				jb.Commits = append(jb.Commits, jsonCommit{Commit: c.Hash, Subject: c.Subject, Timestamp: c.Timestamp.Format(time.RFC3339), Pushed: c.Pushed})
+			}
+			jr.Branches = append(jr.Branches, jb)
+		}
+		out.Repos = append(out.Repos, jr)
+	}
+	return output.JSON(deps.Println, out)
+}
+
+// previousWorkDay returns midnight at the start of the last working day
+// before today, so Monday reaches back to Friday with the default days.
+func previousWorkDay(now time.Time, workDays [7]bool) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for range 7 {
+		day = day.AddDate(0, 0, -1)
+		if workDays[day.Weekday()] {
+			return day
+		}
+	}
+	return day
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWorkDays parses working days: comma-separated day names or ranges
+// such as mon-fri or sun-thu. Names need only their first three letters.
+func parseWorkDays(value string) ([7]bool, error) {
+	var days [7]bool
+	invalid := fmt.Errorf("invalid work days '%s': use day names or ranges like mon-fri or sun,tue,thu", value)
+
+	parse := func(name string) (time.Weekday, bool) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if len(name) < 3 {
+			return 0, false
+		}
+		d, ok := weekdayNames[name[:3]]
+		return d, ok
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, ok := parse(first)
+		if !ok {
+			return days, invalid
+		}
+		to := from
+		if isRange {
+			if to, ok = parse(last); !ok {
+				return days, invalid
+			}
+		}
+		// Ranges may wrap around the weekend, as in fri-mon
+		for d := from; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// configuredWorkDays returns the work_days setting. An invalid value is
+// logged and Monday to Friday is used.
+func configuredWorkDays() [7]bool {
+	value, _ := config.Get("work_days")
+	if value == "" {
+		value = defaultWorkDays
+	}
+	days, err := parseWorkDays(value)
+	if err != nil {
+		log.Warn("%v", err)
+		days, _ = parseWorkDays(defaultWorkDays)
+	}
+	return days
+}
@@ -0,0 +1,159 @@
+package tracking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestParseWorkDays(t *testing.T) {
+	days, err := parseWorkDays("mon-fri")
+	require.NoError(t, err)
+	require.Equal(t, [7]bool{false, true, true, true, true, true, false}, days)
+
+	days, err = parseWorkDays("Sunday-Thu")
+	require.NoError(t, err)
+	require.Equal(t, [7]bool{true, true, true, true, true, false, false}, days)
+
+	days, err = parseWorkDays("fri-mon, wed")
+	require.NoError(t, err)
+	require.Equal(t, [7]bool{true, true, false, true, false, true, true}, days)
+
+	for _, value := range []string{"", "mon-", "weekdays", "mo,tu"} {
+		_, err = parseWorkDays(value)
+		require.ErrorContains(t, err, "invalid work days", value)
+	}
+}
+
+func TestPreviousWorkDay(t *testing.T) {
+	monFri, _ := parseWorkDays("mon-fri")
+	sunThu, _ := parseWorkDays("sun-thu")
+	at := func(day int) time.Time { return time.Date(2025, 3, day, 9, 30, 0, 0, time.UTC) }
+
+	require.Equal(t, time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC), previousWorkDay(at(10), monFri), "Monday reaches back to Friday")
+	require.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), previousWorkDay(at(12), monFri))
+	require.Equal(t, time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), previousWorkDay(at(16), monFri), "Sunday reaches back to Friday")
+	require.Equal(t, time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC), previousWorkDay(at(16), sunThu))
+}
+
+func standupTestDeps(t *testing.T, s *store.Store, now time.Time, out *[]string) Deps {
+	t.Helper()
+	deps := timesheetTestDeps(t, s, out)
+	deps.Now = func() time.Time { return now }
+	deps.CommitSubject = func(_, commit string) string { return "Work on " + commit }
+	return deps
+}
+
+func TestStandup_Markdown(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+	require.NoError(t, store.SetRepoAlias(s.DB(), "github.com/user/api", "api", time.Now()))
+	require.NoError(t, store.SaveCommitMetadata(s.DB(), "github.com/user/api", "aaa0001", git.CommitMetadata{Subject: "Fix login"}))
+
+	var out []string
+	tuesday := time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, standup(nil, dispatchers.NewParsedFlags(nil), standupTestDeps(t, s, tuesday, &out)))
+
+	// aaa0003 was committed after the push of aaa0002
+	require.Equal(t, "**Since Monday, Mar 10**\n"+
+		"\n**api**\n"+
+		"- `main` (partly pushed)\n"+
+		"  - Fix login (aaa0001)\n"+
+		"  - Work on aaa0002 (aaa0002)\n"+
+		"  - Work on aaa0003 (aaa0003) (not pushed)\n"+
+		"\n**github.com/user/web**\n"+
+		"- `main`\n"+
+		"  - Work on bbb0001 (bbb0001)\n", out[0])
+}
+
+func TestStandup_JSONSkipsWeekend(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	var out []string
+	friday := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, standup(nil, dispatchers.NewParsedFlags([]string{"--json"}), standupTestDeps(t, s, friday, &out)))
+
+	var got struct {
+		Since string `json:"since"`
+		Repos []any  `json:"repos"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &got))
+	require.Equal(t, "2025-03-13T00:00:00Z", got.Since)
+	require.Empty(t, got.Repos)
+}
+
+func TestStandup_PlainSince(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	var out []string
+	monday := time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, standup(nil, dispatchers.NewParsedFlags([]string{"--plain", "--since=2025-03-10"}), standupTestDeps(t, s, monday, &out)))
+	require.Contains(t, out[0], "Since Monday, Mar 10\n")
+	require.Contains(t, out[0], "\n  main (partly pushed)\n    - Work on aaa0001 (aaa0001)\n")
+}
+
+func TestBuildStandup_PushedThenCommitted(t *testing.T) {
+	at := func(minutes int) time.Time { return time.Date(2025, 3, 10, 9, minutes, 0, 0, time.UTC) }
+	event := func(commit, branch string, minutes int, source store.Source) store.RepoEvent {
+		return store.RepoEvent{RepoID: "github.com/user/api", Commit: commit, Branch: branch, Timestamp: at(minutes), Source: source}
+	}
+	subject := func(e store.RepoEvent) string { return "" }
+
+	repos := buildStandup([]store.RepoEvent{
+		event("aaa0001", "main", 0, store.SourcePostCommit),
+		event("aaa0001", "main", 5, store.SourcePrePush),
+		event("aaa0002", "main", 10, store.SourcePostCommit),
+		// Pushed by hash from a branch that is named differently upstream
+		event("bbb0001", "feature", 20, store.SourcePostCommit),
+		event("bbb0001", "release", 25, store.SourcePrePush),
+	}, nil, subject)
+
+	require.Len(t, repos, 1)
+	main, feature := repos[0].Branches[0], repos[0].Branches[1]
+	require.Equal(t, []bool{true, false}, []bool{main.Commits[0].Pushed, main.Commits[1].Pushed})
+	require.Equal(t, " (partly pushed)", main.pushed())
+	require.Equal(t, "aaa0002 (not pushed)", main.commitLine(main.Commits[1]))
+	require.True(t, feature.Commits[0].Pushed)
+	require.Equal(t, " (pushed)", feature.pushed())
+
+	// Once the branch is pushed again it is all out
+	repos = buildStandup([]store.RepoEvent{
+		event("aaa0001", "main", 0, store.SourcePostCommit),
+		event("aaa0001", "main", 5, store.SourcePrePush),
+		event("aaa0002", "main", 10, store.SourcePostCommit),
+		event("aaa0002", "main", 15, store.SourcePrePush),
+	}, nil, subject)
+	require.Equal(t, " (pushed)", repos[0].Branches[0].pushed())
+}
+
+func TestStandup_Empty(t *testing.T) {
+	useTestConfig(t, "work_days=sun-thu\n")
+	var out []string
+	sunday := time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, standup(nil, dispatchers.NewParsedFlags(nil), standupTestDeps(t, newTestStore(t), sunday, &out)))
+	require.Equal(t, []string{"no commits since Thursday, Mar 13"}, out)
+}
+
+func TestStandup_LocalClock(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	// At UTC-10, Monday starts at 10:00 UTC: the commits and the push made
+	// before then were on Sunday evening there.
+	var out []string
+	tuesday := time.Date(2025, 3, 11, 9, 0, 0, 0, time.FixedZone("HST", -10*60*60))
+	require.NoError(t, standup(nil, dispatchers.NewParsedFlags([]string{"--plain"}), standupTestDeps(t, s, tuesday, &out)))
+
+	require.Equal(t, "Since Monday, Mar 10\n"+
+		"\ngithub.com/user/api\n"+
+		"  main\n"+
+		"    - Work on aaa0003 (aaa0003)\n", out[0])
+}
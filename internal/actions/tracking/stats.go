@@ -0,0 +1,337 @@
+package tracking
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// statsTopRows bounds the repository and source lists of the summary.
+const statsTopRows = 5
+
+// Stats summarizes recorded activity: totals, streaks, busiest times and
+// breakdowns by day, week, repository, branch, source, hour or weekday.
+func Stats(args []string, flags *dispatchers.ParsedFlags) error {
+	return stats(args, flags, DefaultDeps())
+}
+
+func stats(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	filter := store.EventFilter{ExcludeSuperseded: true}
+
+	since, until, err := dateRange(flags, deps.Now)
+	if err != nil {
+		return err
+	}
+	filter.Since, filter.Until = since, until
+
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	var groupBy store.StatsDimension
+	if value := flags.String("--group-by", ""); value != "" {
+		var ok bool
+		if groupBy, ok = store.ParseStatsDimension(value); !ok {
+			names := make([]string, len(store.StatsDimensions))
+			for i, d := range store.StatsDimensions {
+				names[i] = string(d)
+			}
+			return fmt.Errorf("invalid --group-by '%s': use %s", value, strings.Join(names, ", "))
+		}
+	}
+
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	aliases := loadRepoAliases(db)
+	jsonOutput := flags.Has("--json")
+
+	if groupBy != "" {
+		rows, err := store.EventStats(db, filter, groupBy)
+		if err != nil {
+			return fmt.Errorf("failed to compute stats: %w", err)
+		}
+		if jsonOutput {
+			return outputStatsRowsJSON(groupBy, rows, aliases, deps)
+		}
+		if len(rows) == 0 {
+			_, _ = deps.Println("no events")
+			return nil
+		}
+		deps.Pager(formatStatsRows(groupBy, rows, aliases))
+		return nil
+	}
+
+	summary, err := loadStatsSummary(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to compute stats: %w", err)
+	}
+	if jsonOutput {
+		return outputStatsSummaryJSON(summary, aliases, deps)
+	}
+	if summary.Totals.Events == 0 {
+		_, _ = deps.Println("no events")
+		return nil
+	}
+	deps.Pager(formatStatsSummary(summary, aliases))
+	return nil
+}
+
+// statsSummary is the overview shown when no --group-by is given.
+type statsSummary struct {
+	Totals   store.StatsRow
+	Days     []store.StatsRow
+	Streak   store.Streak
+	Repos    []store.StatsRow
+	Sources  []store.StatsRow
+	Hours    []store.StatsRow
+	Weekdays []store.StatsRow
+}
+
+func loadStatsSummary(db *sql.DB, filter store.EventFilter) (statsSummary, error) {
+	var s statsSummary
+	var err error
+
+	if s.Totals, err = store.EventTotals(db, filter); err != nil {
+		return s, err
+	}
+	for _, q := range []struct {
+		by   store.StatsDimension
+		rows *[]store.StatsRow
+	}{
+		{store.StatsByDay, &s.Days},
+		{store.StatsByRepo, &s.Repos},
+		{store.StatsBySource, &s.Sources},
+		{store.StatsByHour, &s.Hours},
+		{store.StatsByWeekday, &s.Weekdays},
+	} {
+		if *q.rows, err = store.EventStats(db, filter, q.by); err != nil {
+			return s, err
+		}
+	}
+	s.Streak = store.LongestStreak(s.Days)
+	return s, nil
+}
+
+// busiest returns the row with the most events, the first one on a tie.
+func busiest(rows []store.StatsRow) (store.StatsRow, bool) {
+	var best store.StatsRow
+	for _, r := range rows {
+		if r.Events > best.Events {
+			best = r
+		}
+	}
+	return best, best.Events > 0
+}
+
+// statsKey returns how a row key is shown: repositories by alias and hours
+// as the start of the hour.
+func statsKey(by store.StatsDimension, key string, aliases repoAliases) string {
+	switch by {
+	case store.StatsByRepo:
+		return aliases.id(key)
+	case store.StatsByBranch:
+		if key == "" {
+			return "(detached)"
+		}
+	case store.StatsByHour:
+		return key + ":00"
+	}
+	return key
+}
+
+// lineChanges returns "+12 / -3", noting how many commits had a metadata
+// snapshot to count from.
+func lineChanges(r store.StatsRow) string {
+	changes := fmt.Sprintf("+%d / -%d", r.Insertions, r.Deletions)
+	if r.Snapshots < r.Commits {
+		changes += style.Muted(fmt.Sprintf(" (%d of %d commits with metadata)", r.Snapshots, r.Commits))
+	}
+	return changes
+}
+
+func formatStatsSummary(s statsSummary, aliases repoAliases) string {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s %s to %s\n\n", style.Header("Stats"), s.Days[0].Key, s.Days[len(s.Days)-1].Key)
+
+	field := func(name, value string) {
+		fmt.Fprintf(&out, "%s %s\n", style.Muted(fmt.Sprintf("%-15s", name)), value)
+	}
+	field("Events", strconv.Itoa(s.Totals.Events))
+	field("Commits", strconv.Itoa(s.Totals.Commits))
+	field("Lines", lineChanges(s.Totals))
+	field("Active days", strconv.Itoa(len(s.Days)))
+
+	streak := pluralize(s.Streak.Days, "day")
+	if s.Streak.Days > 1 {
+		streak += fmt.Sprintf(" (%s to %s)", s.Streak.Start, s.Streak.End)
+	}
+	field("Longest streak", streak)
+
+	if day, ok := busiest(s.Weekdays); ok {
+		field("Busiest day", fmt.Sprintf("%s (%s)", day.Key, pluralize(day.Events, "event")))
+	}
+	if hour, ok := busiest(s.Hours); ok {
+		field("Busiest hour", fmt.Sprintf("%s (%s)", statsKey(store.StatsByHour, hour.Key, aliases), pluralize(hour.Events, "event")))
+	}
+
+	for _, section := range []struct {
+		title string
+		by    store.StatsDimension
+		rows  []store.StatsRow
+	}{
+		{"Repositories", store.StatsByRepo, s.Repos},
+		{"Sources", store.StatsBySource, s.Sources},
+	} {
+		fmt.Fprintf(&out, "\n%s\n", style.Header(section.title))
+		rows := section.rows
+		if len(rows) > statsTopRows {
+			rows = rows[:statsTopRows]
+		}
+		for _, r := range rows {
+			fmt.Fprintf(&out, "  %-30s %s\n", statsKey(section.by, r.Key, aliases), style.Muted(pluralize(r.Events, "event")))
+		}
+		if more := len(section.rows) - len(rows); more > 0 {
+			fmt.Fprintf(&out, "  %s\n", style.Muted(fmt.Sprintf("and %d more (--group-by %s)", more, section.by)))
+		}
+	}
+	return out.String()
+}
+
+func formatStatsRows(by store.StatsDimension, rows []store.StatsRow, aliases repoAliases) string {
+	keyHeader := strings.ToUpper(string(by))
+	keyWidth := len(keyHeader)
+	for _, r := range rows {
+		keyWidth = max(keyWidth, len(statsKey(by, r.Key, aliases)))
+	}
+
+	line := func(key, events, commits, insertions, deletions string) string {
+		return fmt.Sprintf("%-*s  %7s  %7s  %8s  %8s\n", keyWidth, key, events, commits, insertions, deletions)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(style.Muted(strings.TrimRight(line(keyHeader, "EVENTS", "COMMITS", "+LINES", "-LINES"), "\n")) + "\n")
+
+	var total store.StatsRow
+	for _, r := range rows {
+		out.WriteString(line(statsKey(by, r.Key, aliases), strconv.Itoa(r.Events), strconv.Itoa(r.Commits),
+			strconv.Itoa(r.Insertions), strconv.Itoa(r.Deletions)))
+		total.Events += r.Events
+		total.Commits += r.Commits
+		total.Insertions += r.Insertions
+		total.Deletions += r.Deletions
+	}
+	out.WriteString(style.Header(strings.TrimRight(line("TOTAL", strconv.Itoa(total.Events), strconv.Itoa(total.Commits),
+		strconv.Itoa(total.Insertions), strconv.Itoa(total.Deletions)), "\n")) + "\n")
+	return out.String()
+}
+
+type jsonStatsRow struct {
+	Key                 string `json:"key"`
+	RepoAlias           string `json:"repo_alias,omitempty"`
+	Events              int    `json:"events"`
+	Commits             int    `json:"commits"`
+	Insertions          int    `json:"insertions"`
+	Deletions           int    `json:"deletions"`
+	CommitsWithMetadata int    `json:"commits_with_metadata"`
+}
+
+func statsRowsJSON(by store.StatsDimension, rows []store.StatsRow, aliases repoAliases) []jsonStatsRow {
+	out := make([]jsonStatsRow, 0, len(rows))
+	for _, r := range rows {
+		row := jsonStatsRow{
+			Key:                 r.Key,
+			Events:              r.Events,
+			Commits:             r.Commits,
+			Insertions:          r.Insertions,
+			Deletions:           r.Deletions,
+			CommitsWithMetadata: r.Snapshots,
+		}
+		if by == store.StatsByRepo {
+			row.RepoAlias = aliases[r.Key]
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+func outputStatsRowsJSON(by store.StatsDimension, rows []store.StatsRow, aliases repoAliases, deps Deps) error {
+	return output.JSON(deps.Println, struct {
+		GroupBy string         `json:"group_by"`
+		Rows    []jsonStatsRow `json:"rows"`
+	}{string(by), statsRowsJSON(by, rows, aliases)})
+}
+
+func outputStatsSummaryJSON(s statsSummary, aliases repoAliases, deps Deps) error {
+	type jsonStreak struct {
+		Days  int    `json:"days"`
+		Start string `json:"start,omitempty"`
+		End   string `json:"end,omitempty"`
+	}
+
+	type jsonSummary struct {
+		Events              int            `json:"events"`
+		Commits             int            `json:"commits"`
+		Insertions          int            `json:"insertions"`
+		Deletions           int            `json:"deletions"`
+		CommitsWithMetadata int            `json:"commits_with_metadata"`
+		FirstDay            string         `json:"first_day,omitempty"`
+		LastDay             string         `json:"last_day,omitempty"`
+		ActiveDays          int            `json:"active_days"`
+		LongestStreak       jsonStreak     `json:"longest_streak"`
+		BusiestWeekday      string         `json:"busiest_weekday,omitempty"`
+		BusiestHour         string         `json:"busiest_hour,omitempty"`
+		Repos               []jsonStatsRow `json:"repos"`
+		Sources             []jsonStatsRow `json:"sources"`
+		Weekdays            []jsonStatsRow `json:"weekdays"`
+		Hours               []jsonStatsRow `json:"hours"`
+	}
+
+	out := jsonSummary{
+		Events:              s.Totals.Events,
+		Commits:             s.Totals.Commits,
+		Insertions:          s.Totals.Insertions,
+		Deletions:           s.Totals.Deletions,
+		CommitsWithMetadata: s.Totals.Snapshots,
+		ActiveDays:          len(s.Days),
+		LongestStreak:       jsonStreak(s.Streak),
+		Repos:               statsRowsJSON(store.StatsByRepo, s.Repos, aliases),
+		Sources:             statsRowsJSON(store.StatsBySource, s.Sources, aliases),
+		Weekdays:            statsRowsJSON(store.StatsByWeekday, s.Weekdays, aliases),
+		Hours:               statsRowsJSON(store.StatsByHour, s.Hours, aliases),
+	}
+	if len(s.Days) > 0 {
+		out.FirstDay = s.Days[0].Key
+		out.LastDay = s.Days[len(s.Days)-1].Key
+	}
+	if day, ok := busiest(s.Weekdays); ok {
+		out.BusiestWeekday = day.Key
+	}
+	if hour, ok := busiest(s.Hours); ok {
+		out.BusiestHour = hour.Key
+	}
+
+	return output.JSON(deps.Println, out)
+}
@@ -0,0 +1,105 @@
+package tracking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestStats_SummaryJSON(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+	require.NoError(t, store.SetRepoAlias(s.DB(), "github.com/user/api", "api", time.Now()))
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--json"})
+	require.NoError(t, stats(nil, flags, searchTestDeps(s.DB(), &out)))
+
+	var got struct {
+		Events        int    `json:"events"`
+		Commits       int    `json:"commits"`
+		ActiveDays    int    `json:"active_days"`
+		FirstDay      string `json:"first_day"`
+		BusiestHour   string `json:"busiest_hour"`
+		BusiestDay    string `json:"busiest_weekday"`
+		LongestStreak struct {
+			Days int `json:"days"`
+		} `json:"longest_streak"`
+		Repos []struct {
+			Key       string `json:"key"`
+			RepoAlias string `json:"repo_alias"`
+			Events    int    `json:"events"`
+		} `json:"repos"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &got))
+
+	// Days and hours are local; the events span 09:00 to 12:00 UTC
+	first := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC).Local()
+	last := first.Add(3 * time.Hour)
+	days := 1
+	if last.Day() != first.Day() {
+		days = 2
+	}
+
+	require.Equal(t, 5, got.Events)
+	require.Equal(t, 4, got.Commits, "the push is not a new commit")
+	require.Equal(t, days, got.ActiveDays)
+	require.Equal(t, first.Format("2006-01-02"), got.FirstDay)
+	require.Equal(t, first.Format("15"), got.BusiestHour)
+	require.Equal(t, first.Weekday().String(), got.BusiestDay)
+	require.Equal(t, days, got.LongestStreak.Days)
+	require.Equal(t, "github.com/user/api", got.Repos[0].Key)
+	require.Equal(t, "api", got.Repos[0].RepoAlias)
+	require.Equal(t, 4, got.Repos[0].Events)
+}
+
+func TestStats_GroupBy(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--group-by=source", "--repo=github.com/user/api"})
+	require.NoError(t, stats(nil, flags, searchTestDeps(s.DB(), &out)))
+
+	require.Len(t, out, 1)
+	require.Contains(t, out[0], "POST-COMMIT")
+	require.Contains(t, out[0], "PRE-PUSH")
+	require.Contains(t, out[0], "TOTAL")
+}
+
+func TestStats_InvalidGroupBy(t *testing.T) {
+	var out []string
+	err := stats(nil, dispatchers.NewParsedFlags([]string{"--group-by=month"}), searchTestDeps(newTestStore(t).DB(), &out))
+	require.ErrorContains(t, err, "invalid --group-by 'month'")
+}
+
+func TestStats_Empty(t *testing.T) {
+	useTestConfig(t, "")
+	var out []string
+	require.NoError(t, stats(nil, dispatchers.NewParsedFlags(nil), searchTestDeps(newTestStore(t).DB(), &out)))
+	require.Equal(t, []string{"no events"}, out)
+}
+
+func TestStats_DuringInLocalZone(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	// At UTC+14 the events fall between 23:00 on the 10th and 02:00 on the
+	// 11th, so the last one is not on the 10th there.
+	var out []string
+	deps := searchTestDeps(s.DB(), &out)
+	deps.Now = func() time.Time { return time.Date(2025, 3, 11, 12, 0, 0, 0, time.FixedZone("LINT", 14*60*60)) }
+	flags := dispatchers.NewParsedFlags([]string{"--json", "--during=yesterday"})
+	require.NoError(t, stats(nil, flags, deps))
+
+	var got struct {
+		Events int `json:"events"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &got))
+	require.Equal(t, 4, got.Events)
+}
@@ -0,0 +1,332 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/sessions"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// ReportTimesheet estimates the hours worked per day and per repository or
+// client from work sessions.
+func ReportTimesheet(args []string, flags *dispatchers.ParsedFlags) error {
+	return reportTimesheet(args, flags, DefaultDeps())
+}
+
+func reportTimesheet(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	from, to, err := timesheetPeriod(flags, deps.Now())
+	if err != nil {
+		return err
+	}
+
+	by := flags.String("--by", "repo")
+	if by != "repo" && by != "client" {
+		return fmt.Errorf("invalid --by '%s': use repo or client", by)
+	}
+
+	jsonOutput := flags.Has("--json")
+	csvOutput := flags.Has("--csv")
+	if jsonOutput && csvOutput {
+		return errors.New("use either --json or --csv")
+	}
+
+	gap := sessions.ConfiguredGap()
+	if gapStr := flags.String("--gap", ""); gapStr != "" {
+		if gap, err = sessions.ParseGap(gapStr); err != nil {
+			return err
+		}
+	}
+
+	rounding := sessions.ConfiguredRounding()
+	if value := flags.String("--round", ""); value != "" {
+		if rounding.RoundTo, err = sessions.ParseRounding("--round", value); err != nil {
+			return err
+		}
+	}
+	if value := flags.String("--min-block", ""); value != "" {
+		if rounding.MinBlock, err = sessions.ParseRounding("--min-block", value); err != nil {
+			return err
+		}
+	}
+
+	// The whole last day counts
+	until := to.AddDate(0, 0, 1).Add(-time.Second)
+	filter := store.EventFilter{Since: &from, Until: &until, ExcludeSuperseded: true}
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter.Where, err = whereFilter(flags); err != nil {
+		return err
+	}
+
+	events, err := deps.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	// Rows are named by alias, or by group when billing clients; repos
+	// outside any group stand on their own
+	aliases := loadRepoAliases(db)
+	clients := make(map[string]string)
+	if by == "client" {
+		meta, err := store.ListRepoMeta(db)
+		if err != nil {
+			log.Warn("could not read repository groups: %v", err)
+		}
+		for _, m := range meta {
+			if m.Group != "" {
+				clients[m.RepoID] = m.Group
+			}
+		}
+	}
+	key := func(s sessions.Session) string {
+		if client, ok := clients[s.Repos[0]]; ok {
+			return client
+		}
+		return aliases.id(s.Repos[0])
+	}
+
+	// Days are those of the report period, not UTC days
+	rows := sessions.Timesheet(sessions.GroupByRepo(events, gap), key, rounding, from.Location())
+	sheet := timesheet{From: from, To: to, By: by, Gap: gap, Rounding: rounding, Rows: rows}
+
+	switch {
+	case jsonOutput:
+		return outputTimesheetJSON(sheet, deps)
+	case csvOutput:
+		return outputTimesheetCSV(sheet, deps)
+	}
+
+	if len(rows) == 0 {
+		_, _ = deps.Printf("no sessions between %s and %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil
+	}
+	deps.Pager(formatTimesheet(sheet))
+	return nil
+}
+
+// timesheet is a computed report with the rules it was computed with.
+type timesheet struct {
+	From, To time.Time
+	By       string
+	Gap      time.Duration
+	Rounding sessions.Rounding
+	Rows     []sessions.TimesheetRow
+}
+
+// timesheetPeriod returns the first and last day of the report: this week
+// by default, this month with --month, or --from/--to.
+func timesheetPeriod(flags *dispatchers.ParsedFlags, now time.Time) (time.Time, time.Time, error) {
+	fromStr := flags.String("--from", "")
+	toStr := flags.String("--to", "")
+	duringStr := flags.String("--during", "")
+
+	chosen := 0
+	for _, set := range []bool{flags.Has("--week"), flags.Has("--month"), fromStr != "" || toStr != "", duringStr != ""} {
+		if set {
+			chosen++
+		}
+	}
+	if chosen > 1 {
+		return time.Time{}, time.Time{}, errors.New("use only one of --week, --month, --during or --from/--to")
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	clock := func() time.Time { return now }
+	// Timesheets cover whole days
+	dayOf := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()) }
+
+	switch {
+	case flags.Has("--month"):
+		first := today.AddDate(0, 0, 1-today.Day())
+		return first, first.AddDate(0, 1, -1), nil
+
+	case duringStr != "":
+		during, err := duringFlag(flags, clock)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return dayOf(during.Start), dayOf(during.Last()), nil
+
+	case fromStr != "" || toStr != "":
+		if fromStr == "" {
+			return time.Time{}, time.Time{}, errors.New("--to needs --from")
+		}
+		fromPeriod, err := dateFlag(flags, "--from", clock)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from, to := dayOf(fromPeriod.Start), today
+		if toStr != "" {
+			toPeriod, err := dateFlag(flags, "--to", clock)
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			to = dayOf(toPeriod.Last())
+		}
+		if to.Before(from) {
+			return time.Time{}, time.Time{}, fmt.Errorf("--to %s is before --from %s", toStr, fromStr)
+		}
+		return from, to, nil
+
+	default:
+		// Weeks start on Monday
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 6), nil
+	}
+}
+
+// hours formats billed time as decimal hours, as invoices use.
+func hours(d time.Duration) string {
+	return strconv.FormatFloat(hoursValue(d), 'f', 2, 64)
+}
+
+// hoursValue returns d in hours, to two decimals.
+func hoursValue(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+func formatTimesheet(sheet timesheet) string {
+	keyHeader := strings.ToUpper(sheet.By)
+	keyWidth := len(keyHeader)
+	for _, row := range sheet.Rows {
+		keyWidth = max(keyWidth, len(row.Key))
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s %s to %s\n\n",
+		style.Header("Timesheet"), sheet.From.Format("2006-01-02"), sheet.To.Format("2006-01-02"))
+
+	line := func(date, key, sessionCount, commits, worked, billed string) string {
+		return fmt.Sprintf("%-10s  %-*s  %8s  %7s  %8s  %6s\n", date, keyWidth, key, sessionCount, commits, worked, billed)
+	}
+	out.WriteString(style.Muted(strings.TrimRight(line("DATE", keyHeader, "SESSIONS", "COMMITS", "WORKED", "HOURS"), "\n")) + "\n")
+
+	totals := make(map[string]time.Duration)
+	var keys []string
+	var total time.Duration
+	for _, row := range sheet.Rows {
+		out.WriteString(line(row.Date, row.Key, strconv.Itoa(row.Sessions), strconv.Itoa(row.Commits),
+			format.Duration(row.Worked), hours(row.Billed)))
+		if _, ok := totals[row.Key]; !ok {
+			keys = append(keys, row.Key)
+		}
+		totals[row.Key] += row.Billed
+		total += row.Billed
+	}
+
+	out.WriteString("\n")
+	for _, key := range keys {
+		out.WriteString(line("", key, "", "", "", hours(totals[key])))
+	}
+	out.WriteString(style.Header(strings.TrimRight(line("TOTAL", "", "", "", "", hours(total)), "\n")) + "\n")
+
+	rules := []string{"sessions end after " + format.Duration(sheet.Gap) + " idle"}
+	if sheet.Rounding.MinBlock > 0 {
+		rules = append(rules, "count at least "+format.Duration(sheet.Rounding.MinBlock))
+	}
+	if sheet.Rounding.RoundTo > 0 {
+		rules = append(rules, "rows round up to "+format.Duration(sheet.Rounding.RoundTo))
+	}
+	fmt.Fprintf(&out, "\n%s\n", style.Muted("Hours: "+strings.Join(rules, ", ")))
+	return out.String()
+}
+
+func outputTimesheetCSV(sheet timesheet, deps Deps) error {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	_ = w.Write([]string{"date", sheet.By, "sessions", "commits", "worked_minutes", "billed_minutes", "hours"})
+	for _, row := range sheet.Rows {
+		_ = w.Write([]string{
+			row.Date,
+			row.Key,
+			strconv.Itoa(row.Sessions),
+			strconv.Itoa(row.Commits),
+			strconv.Itoa(int(row.Worked / time.Minute)),
+			strconv.Itoa(int(row.Billed / time.Minute)),
+			hours(row.Billed),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	_, _ = deps.Printf("%s", out.String())
+	return nil
+}
+
+func outputTimesheetJSON(sheet timesheet, deps Deps) error {
+	type jsonRow struct {
+		Date          string  `json:"date"`
+		Name          string  `json:"name"`
+		Sessions      int     `json:"sessions"`
+		Commits       int     `json:"commits"`
+		WorkedMinutes int     `json:"worked_minutes"`
+		BilledMinutes int     `json:"billed_minutes"`
+		Hours         float64 `json:"hours"`
+	}
+
+	type jsonTimesheet struct {
+		From            string    `json:"from"`
+		To              string    `json:"to"`
+		By              string    `json:"by"`
+		GapMinutes      int       `json:"gap_minutes"`
+		RoundMinutes    int       `json:"round_minutes"`
+		MinBlockMinutes int       `json:"min_block_minutes"`
+		Rows            []jsonRow `json:"rows"`
+		TotalHours      float64   `json:"total_hours"`
+	}
+
+	out := jsonTimesheet{
+		From:            sheet.From.Format("2006-01-02"),
+		To:              sheet.To.Format("2006-01-02"),
+		By:              sheet.By,
+		GapMinutes:      int(sheet.Gap / time.Minute),
+		RoundMinutes:    int(sheet.Rounding.RoundTo / time.Minute),
+		MinBlockMinutes: int(sheet.Rounding.MinBlock / time.Minute),
+		Rows:            make([]jsonRow, 0, len(sheet.Rows)),
+	}
+	var total time.Duration
+	for _, row := range sheet.Rows {
+		out.Rows = append(out.Rows, jsonRow{
+			Date:          row.Date,
+			Name:          row.Key,
+			Sessions:      row.Sessions,
+			Commits:       row.Commits,
+			WorkedMinutes: int(row.Worked / time.Minute),
+			BilledMinutes: int(row.Billed / time.Minute),
+			Hours:         hoursValue(row.Billed),
+		})
+		total += row.Billed
+	}
+	out.TotalHours = hoursValue(total)
+
+	return output.JSON(deps.Println, out)
+}
@@ -0,0 +1,174 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestTimesheetPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 13, 16, 0, 0, 0, time.UTC) // a Thursday
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	tests := []struct {
+		flags    []string
+		from, to string
+		err      string
+	}{
+		{flags: nil, from: "2025-03-10", to: "2025-03-16"},
+		{flags: []string{"--week"}, from: "2025-03-10", to: "2025-03-16"},
+		{flags: []string{"--month"}, from: "2025-03-01", to: "2025-03-31"},
+		{flags: []string{"--from=2025-02-20", "--to=2025-03-02"}, from: "2025-02-20", to: "2025-03-02"},
+		{flags: []string{"--from=2025-03-11"}, from: "2025-03-11", to: "2025-03-13"},
+		{flags: []string{"--to=2025-03-02"}, err: "--to needs --from"},
+		{flags: []string{"--week", "--month"}, err: "use only one of"},
+		{flags: []string{"--from=2025-03-05", "--to=2025-03-01"}, err: "is before --from"},
+		{flags: []string{"--from=March"}, err: "invalid --from: unrecognized date 'March'"},
+		{flags: []string{"--from=2025-W10", "--to=yesterday"}, from: "2025-03-03", to: "2025-03-12"},
+		{flags: []string{"--during=last week"}, from: "2025-03-03", to: "2025-03-09"},
+		{flags: []string{"--during=2025-02"}, from: "2025-02-01", to: "2025-02-28"},
+		{flags: []string{"--during=this month", "--week"}, err: "use only one of"},
+		{flags: []string{"--during=2w"}, err: "invalid --during '2w': it needs a span"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.flags, " "), func(t *testing.T) {
+			from, to, err := timesheetPeriod(dispatchers.NewParsedFlags(tt.flags), now)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, day(tt.from), from)
+			require.Equal(t, day(tt.to), to)
+		})
+	}
+}
+
+func timesheetTestDeps(t *testing.T, s *store.Store, out *[]string) Deps {
+	t.Helper()
+	deps := searchTestDeps(s.DB(), out)
+	deps.Now = func() time.Time { return time.Date(2025, 3, 13, 16, 0, 0, 0, time.UTC) }
+	deps.Println = func(a ...any) (int, error) {
+		*out = append(*out, fmt.Sprint(a...))
+		return 0, nil
+	}
+	deps.Printf = func(format string, a ...any) (int, error) {
+		*out = append(*out, fmt.Sprintf(format, a...))
+		return 0, nil
+	}
+	return deps
+}
+
+func TestReportTimesheet_JSONByClient(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+	require.NoError(t, store.SetRepoGroup(s.DB(), []string{"github.com/user/api", "github.com/user/web"}, "acme", time.Now()))
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--json", "--by=client"})
+	require.NoError(t, reportTimesheet(nil, flags, timesheetTestDeps(t, s, &out)))
+
+	var got struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Rows []struct {
+			Date          string  `json:"date"`
+			Name          string  `json:"name"`
+			Sessions      int     `json:"sessions"`
+			WorkedMinutes int     `json:"worked_minutes"`
+			BilledMinutes int     `json:"billed_minutes"`
+			Hours         float64 `json:"hours"`
+		} `json:"rows"`
+		TotalHours float64 `json:"total_hours"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &got))
+	require.Equal(t, "2025-03-10", got.From)
+	require.Equal(t, "2025-03-16", got.To)
+	require.Len(t, got.Rows, 1)
+
+	// api 40m, web and the lone api commit 15m blocks each: 70m, rounded to 75m
+	row := got.Rows[0]
+	require.Equal(t, "acme", row.Name)
+	require.Equal(t, 3, row.Sessions)
+	require.Equal(t, 40, row.WorkedMinutes)
+	require.Equal(t, 75, row.BilledMinutes)
+	require.Equal(t, 1.25, row.Hours)
+	require.Equal(t, 1.25, got.TotalHours)
+}
+
+func TestReportTimesheet_CSV(t *testing.T) {
+	useTestConfig(t, "timesheet_round=30m\n")
+	s := sessionsTestStore(t)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--csv", "--min-block=0"})
+	require.NoError(t, reportTimesheet(nil, flags, timesheetTestDeps(t, s, &out)))
+
+	require.Equal(t, "date,repo,sessions,commits,worked_minutes,billed_minutes,hours\n"+
+		"2025-03-10,github.com/user/api,2,3,40,60,1.00\n"+
+		"2025-03-10,github.com/user/web,1,1,0,0,0.00\n", out[0])
+}
+
+func TestReportTimesheet_Table(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--from=2025-03-10", "--to=2025-03-10"})
+	require.NoError(t, reportTimesheet(nil, flags, timesheetTestDeps(t, s, &out)))
+
+	require.Equal(t, []string{"Timesheet 2025-03-10 to 2025-03-10\n\n" +
+		"DATE        REPO                 SESSIONS  COMMITS    WORKED   HOURS\n" +
+		"2025-03-10  github.com/user/api         2        3       40m    1.00\n" +
+		"2025-03-10  github.com/user/web         1        1        0m    0.25\n\n" +
+		"            github.com/user/api                                 1.00\n" +
+		"            github.com/user/web                                 0.25\n" +
+		"TOTAL                                                           1.25\n\n" +
+		"Hours: sessions end after 30m idle, count at least 15m, rows round up to 15m\n"}, out)
+}
+
+func TestReportTimesheet_InvalidBy(t *testing.T) {
+	var out []string
+	err := reportTimesheet(nil, dispatchers.NewParsedFlags([]string{"--by=team"}), timesheetTestDeps(t, newTestStore(t), &out))
+	require.ErrorContains(t, err, "invalid --by 'team'")
+}
+
+func TestReportTimesheet_NoSessions(t *testing.T) {
+	useTestConfig(t, "")
+
+	var out []string
+	flags := dispatchers.NewParsedFlags([]string{"--from=2025-03-01", "--to=2025-03-05"})
+	require.NoError(t, reportTimesheet(nil, flags, timesheetTestDeps(t, sessionsTestStore(t), &out)))
+
+	require.Equal(t, []string{"no sessions between 2025-03-01 and 2025-03-05\n"}, out)
+}
+
+func TestReportTimesheet_LocalDays(t *testing.T) {
+	useTestConfig(t, "")
+	s := sessionsTestStore(t)
+
+	// At UTC-10 the morning sessions of the 10th in UTC start on the
+	// evening of the 9th, and the last commit is on the 10th
+	var out []string
+	deps := timesheetTestDeps(t, s, &out)
+	deps.Now = func() time.Time { return time.Date(2025, 3, 13, 6, 0, 0, 0, time.FixedZone("HST", -10*60*60)) }
+	flags := dispatchers.NewParsedFlags([]string{"--csv", "--min-block=0", "--round=0", "--from=2025-03-09", "--to=2025-03-10"})
+	require.NoError(t, reportTimesheet(nil, flags, deps))
+
+	require.Equal(t, "date,repo,sessions,commits,worked_minutes,billed_minutes,hours\n"+
+		"2025-03-09,github.com/user/api,1,2,40,40,0.67\n"+
+		"2025-03-09,github.com/user/web,1,1,0,0,0.00\n"+
+		"2025-03-10,github.com/user/api,1,1,0,0,0.00\n", out[0])
+}
@@ -0,0 +1,267 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// unpushedCommit is a recorded commit that no remote-tracking branch contains.
+type unpushedCommit struct {
+	Commit    string
+	Subject   string
+	Timestamp time.Time
+}
+
+// unpushedBranch groups unpushed commits by the branch they were made on.
+type unpushedBranch struct {
+	Name    string
+	Commits []unpushedCommit
+}
+
+// unpushedRepo is a tracked repository with local-only commits.
+type unpushedRepo struct {
+	ID         string
+	Path       string
+	HasRemotes bool
+	Branches   []unpushedBranch
+}
+
+func (r unpushedRepo) commitCount() int {
+	n := 0
+	for _, b := range r.Branches {
+		n += len(b.Commits)
+	}
+	return n
+}
+
+// Unpushed lists recorded commits that have not reached any remote.
+func Unpushed(args []string, flags *dispatchers.ParsedFlags) error {
+	return unpushed(args, flags, DefaultDeps())
+}
+
+func unpushed(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if flags.Has("-i") || flags.Has("--interactive") {
+		return unpushedInteractive(flags, deps)
+	}
+
+	jsonOutput := flags.Has("--json")
+
+	repos, err := collectUnpushed(flags.String("--repo", ""), deps)
+	if err != nil {
+		return err
+	}
+
+	if len(repos) == 0 {
+		if jsonOutput {
+			output.JSONEmpty(deps.Println)
+		} else {
+			_, _ = deps.Println("no unpushed commits")
+		}
+		return nil
+	}
+
+	if jsonOutput {
+		return outputUnpushedJSON(repos, deps)
+	}
+
+	home, _ := os.UserHomeDir()
+	total := 0
+	for i, r := range repos {
+		if i > 0 {
+			_, _ = deps.Println()
+		}
+		header := style.Header(shortenHome(r.Path, home)) + " " + style.Muted(r.ID)
+		if !r.HasRemotes {
+			header += " " + style.Warning("(no remotes)")
+		}
+		_, _ = deps.Println(header)
+		for _, b := range r.Branches {
+			_, _ = deps.Printf("  %s %s\n", b.Name, style.Muted("("+pluralize(len(b.Commits), "commit")+")"))
+			for _, c := range b.Commits {
+				_, _ = deps.Printf("    %s %s %s\n",
+					style.Header(fmt.Sprintf("%.7s", c.Commit)),
+					style.Muted(format.DateTimeShort(c.Timestamp)),
+					c.Subject,
+				)
+			}
+		}
+		total += r.commitCount()
+	}
+
+	noun := "repositories"
+	if len(repos) == 1 {
+		noun = "repository"
+	}
+	_, _ = deps.Println()
+	_, _ = deps.Printf("%s in %d %s\n", pluralize(total, "unpushed commit"), len(repos), noun)
+
+	return nil
+}
+
+// collectUnpushed checks every recorded commit in tracked repositories against
+// the commits of its branch that no remote-tracking branch contains, listed
+// once per clone and branch. Commits replaced by an amend or rebase are
+// skipped; their replacements are checked instead.
+func collectUnpushed(repoFilter string, deps Deps) ([]unpushedRepo, error) {
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = s.Close() }()
+
+	paths, err := s.ListRepoPaths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked repositories: %w", err)
+	}
+	tracked := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		tracked[p] = true
+	}
+
+	filter := store.EventFilter{ExcludeSuperseded: true}
+	if repoFilter != "" {
+		filter.RepoID = &repoFilter
+	}
+	events, err := deps.ListEvents(s.DB(), filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	// Group commits by clone, oldest first so branches read chronologically
+	byPath := make(map[string][]store.RepoEvent)
+	for _, e := range events {
+		if e.Source != store.SourcePostCommit && e.Source != store.SourcePostRewrite {
+			continue
+		}
+		if !tracked[e.RepoPath] {
+			continue
+		}
+		byPath[e.RepoPath] = append(byPath[e.RepoPath], e)
+	}
+
+	var repos []unpushedRepo
+	for _, path := range paths {
+		repoEvents := byPath[path]
+		if len(repoEvents) == 0 {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			log.Debug("unpushed: skipping missing repo %s: %v", path, err)
+			continue
+		}
+		sort.SliceStable(repoEvents, func(i, j int) bool {
+			return repoEvents[i].Timestamp.Before(repoEvents[j].Timestamp)
+		})
+
+		r := unpushedRepo{ID: repoEvents[0].RepoID, Path: path}
+		remotes, _ := deps.ListRemotes(path)
+		r.HasRemotes = len(remotes) > 0
+
+		// A commit counts as unpushed when any recorded branch still holds
+		// it locally, so commits merged into another branch are found too
+		local := make(map[string]bool)
+		checked := make(map[string]bool)
+		for _, e := range repoEvents {
+			if checked[e.Branch] {
+				continue
+			}
+			checked[e.Branch] = true
+
+			commits, err := deps.UnpushedCommits(path, e.Branch)
+			if err != nil {
+				// Usually a branch deleted since the commit was recorded
+				log.Debug("unpushed: cannot check branch %s in %s: %v", e.Branch, path, err)
+				continue
+			}
+			for _, c := range commits {
+				local[c] = true
+			}
+		}
+
+		seen := make(map[string]bool)
+		branchIndex := make(map[string]int)
+		for _, e := range repoEvents {
+			if seen[e.Commit] || !local[e.Commit] {
+				continue
+			}
+			seen[e.Commit] = true
+
+			idx, ok := branchIndex[e.Branch]
+			if !ok {
+				idx = len(r.Branches)
+				branchIndex[e.Branch] = idx
+				r.Branches = append(r.Branches, unpushedBranch{Name: e.Branch})
+			}
+			r.Branches[idx].Commits = append(r.Branches[idx].Commits, unpushedCommit{
+				Commit:    e.Commit,
+				Subject:   deps.CommitSubject(path, e.Commit),
+				Timestamp: e.Timestamp,
+			})
+		}
+
+		if len(r.Branches) > 0 {
+			repos = append(repos, r)
+		}
+	}
+
+	return repos, nil
+}
+
+func outputUnpushedJSON(repos []unpushedRepo, deps Deps) error {
+	type commitJSON struct {
+		Commit    string `json:"commit"`
+		Subject   string `json:"subject,omitempty"`
+		Timestamp string `json:"timestamp"`
+	}
+	type branchJSON struct {
+		Branch  string       `json:"branch"`
+		Commits []commitJSON `json:"commits"`
+	}
+	type repoJSON struct {
+		RepoID     string       `json:"repo_id"`
+		Path       string       `json:"path"`
+		HasRemotes bool         `json:"has_remotes"`
+		Branches   []branchJSON `json:"branches"`
+	}
+
+	out := make([]repoJSON, 0, len(repos))
+	for _, r := range repos {
+		rj := repoJSON{RepoID: r.ID, Path: r.Path, HasRemotes: r.HasRemotes}
+		for _, b := range r.Branches {
+			bj := branchJSON{Branch: b.Name, Commits: make([]commitJSON, 0, len(b.Commits))}
+			for _, c := range b.Commits {
+				bj.Commits = append(bj.Commits, commitJSON{
+					Commit:    c.Commit,
+					Subject:   c.Subject,
+					Timestamp: c.Timestamp.Format(time.RFC3339),
+				})
+			}
+			rj.Branches = append(rj.Branches, bj)
+		}
+		out = append(out, rj)
+	}
+
+	return output.JSON(deps.Println, out)
+}
+
+// shortenHome displays paths under the home directory as ~/rel.
+func shortenHome(path, home string) string {
+	if home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return "~/" + rel
+	}
+	return path
+}
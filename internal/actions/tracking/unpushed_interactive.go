@@ -0,0 +1,338 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/ui/components"
+	"github.com/footprint-tools/cli/internal/ui/splitpanel"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"golang.org/x/term"
+)
+
+// unpushedInteractive shows unpushed commits per repository. Pressing Enter
+// opens a shell in the selected repository; when that shell exits the report
+// is refreshed, so pushed work drops off the list.
+func unpushedInteractive(flags *dispatchers.ParsedFlags, deps Deps) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("interactive mode requires a terminal")
+	}
+
+	repoFilter := flags.String("--repo", "")
+	cursor := 0
+
+	for {
+		repos, err := collectUnpushed(repoFilter, deps)
+		if err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			_, _ = deps.Println("no unpushed commits")
+			return nil
+		}
+
+		m := newUnpushedModel(repos)
+		m.cursor = min(cursor, len(repos)-1)
+
+		p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+		final, err := p.Run()
+		if err != nil {
+			return err
+		}
+
+		fm := final.(unpushedModel)
+		if fm.openPath == "" {
+			return nil
+		}
+		cursor = fm.cursor
+
+		if err := openShellIn(fm.openPath, deps); err != nil {
+			return err
+		}
+	}
+}
+
+// openShellIn starts the user's shell inside a repository and waits for it to exit.
+func openShellIn(path string, deps Deps) error {
+	shell := deps.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	home, _ := os.UserHomeDir()
+	_, _ = deps.Printf("Opening a shell in %s (exit to return to fp unpushed)\n", shortenHome(path, home))
+
+	cmd := exec.Command(shell)
+	cmd.Dir = path
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// A non-zero exit from the shell just means the last command failed
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to start %s: %w", shell, err)
+	}
+	return nil
+}
+
+// unpushedModel is the Bubble Tea model for the interactive unpushed view.
+type unpushedModel struct {
+	repos        []unpushedRepo
+	cursor       int
+	width        int
+	height       int
+	colors       style.ColorConfig
+	focusSidebar bool
+	commitScroll int
+	openPath     string // Repo to open a shell in after quitting
+}
+
+func newUnpushedModel(repos []unpushedRepo) unpushedModel {
+	return unpushedModel{
+		repos:        repos,
+		colors:       style.GetColors(),
+		focusSidebar: true, // Start with focus on the repo list
+	}
+}
+
+func (m unpushedModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m unpushedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return m, tea.Quit
+
+		case tea.KeyTab:
+			m.focusSidebar = !m.focusSidebar
+
+		case tea.KeyUp:
+			m.moveUp()
+
+		case tea.KeyDown:
+			m.moveDown()
+
+		case tea.KeyHome:
+			m.selectRepo(0)
+
+		case tea.KeyEnd:
+			m.selectRepo(len(m.repos) - 1)
+
+		case tea.KeyEnter:
+			m.openPath = m.repos[m.cursor].Path
+			return m, tea.Quit
+
+		case tea.KeyRunes:
+			switch string(msg.Runes) {
+			case "q":
+				return m, tea.Quit
+			case "j":
+				m.moveDown()
+			case "k":
+				m.moveUp()
+			case "g":
+				m.selectRepo(0)
+			case "G":
+				m.selectRepo(len(m.repos) - 1)
+			case "h":
+				m.focusSidebar = true
+			case "l":
+				m.focusSidebar = false
+			case "o":
+				m.openPath = m.repos[m.cursor].Path
+				return m, tea.Quit
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *unpushedModel) moveUp() {
+	if m.focusSidebar {
+		m.selectRepo(m.cursor - 1)
+	} else {
+		m.commitScroll = max(m.commitScroll-1, 0)
+	}
+}
+
+func (m *unpushedModel) moveDown() {
+	if m.focusSidebar {
+		m.selectRepo(m.cursor + 1)
+	} else {
+		m.commitScroll = min(m.commitScroll+1, max(len(m.commitLines())-1, 0))
+	}
+}
+
+func (m *unpushedModel) selectRepo(i int) {
+	i = max(0, min(i, len(m.repos)-1))
+	if i != m.cursor {
+		m.cursor = i
+		m.commitScroll = 0
+	}
+}
+
+func (m unpushedModel) totalCommits() int {
+	n := 0
+	for _, r := range m.repos {
+		n += r.commitCount()
+	}
+	return n
+}
+
+func (m unpushedModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	headerHeight := 3
+	footerHeight := 2
+	mainHeight := max(m.height-headerHeight-footerHeight, 1)
+
+	cfg := splitpanel.Config{
+		SidebarWidthPercent: 0.30,
+		SidebarMinWidth:     20,
+		SidebarMaxWidth:     36,
+	}
+	layout := splitpanel.NewLayout(m.width, cfg, m.colors)
+	if m.focusSidebar {
+		layout.SetFocusedPanel(1)
+	} else {
+		layout.SetFocusedPanel(0)
+	}
+
+	sidebar := m.buildReposPanel(mainHeight)
+	commits := m.buildCommitsPanel(mainHeight)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderHeader(),
+		layout.Render(sidebar, commits, mainHeight),
+		m.renderFooter(),
+	)
+}
+
+func (m unpushedModel) renderHeader() string {
+	infoColor := lipgloss.Color(m.colors.Info)
+	mutedColor := lipgloss.Color(m.colors.Muted)
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(infoColor)
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+
+	noun := "repositories"
+	if len(m.repos) == 1 {
+		noun = "repository"
+	}
+	title := titleStyle.Render("fp unpushed")
+	count := mutedStyle.Render(fmt.Sprintf(" | %s in %d %s", pluralize(m.totalCommits(), "commit"), len(m.repos), noun))
+
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(title + count)
+}
+
+func (m unpushedModel) buildReposPanel(height int) splitpanel.Panel {
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Warning))
+	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.UIActive))
+
+	visibleHeight := max(height-2, 1)
+	scroll := 0
+	if m.cursor >= visibleHeight {
+		scroll = m.cursor - visibleHeight + 1
+	}
+
+	var lines []string
+	for i := scroll; i < len(m.repos) && len(lines) < visibleHeight; i++ {
+		r := m.repos[i]
+		name := filepath.Base(r.Path)
+		if i == m.cursor {
+			name = activeStyle.Render("> " + name)
+		} else {
+			name = "  " + name
+		}
+		count := mutedStyle.Render(fmt.Sprintf(" %d", r.commitCount()))
+		if !r.HasRemotes {
+			count += warnStyle.Render(" !")
+		}
+		lines = append(lines, name+count)
+	}
+
+	return splitpanel.Panel{
+		Lines:      lines,
+		ScrollPos:  scroll,
+		TotalItems: len(m.repos),
+	}
+}
+
+// commitLines renders the selected repository's unpushed commits by branch.
+func (m unpushedModel) commitLines() []string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Header))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Warning))
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Info))
+
+	r := m.repos[m.cursor]
+	home, _ := os.UserHomeDir()
+
+	var lines []string
+	lines = append(lines, headerStyle.Render(shortenHome(r.Path, home)))
+	lines = append(lines, mutedStyle.Render(r.ID))
+	if !r.HasRemotes {
+		lines = append(lines, warnStyle.Render("No remotes configured"))
+	}
+	for _, b := range r.Branches {
+		lines = append(lines, "")
+		lines = append(lines, headerStyle.Render(b.Name)+mutedStyle.Render(" ("+pluralize(len(b.Commits), "commit")+")"))
+		for _, c := range b.Commits {
+			lines = append(lines, fmt.Sprintf("  %s %s %s",
+				infoStyle.Render(fmt.Sprintf("%.7s", c.Commit)),
+				mutedStyle.Render(format.DateTimeShort(c.Timestamp)),
+				c.Subject,
+			))
+		}
+	}
+
+	return lines
+}
+
+func (m unpushedModel) buildCommitsPanel(height int) splitpanel.Panel {
+	lines := m.commitLines()
+
+	visibleLines := max(height-2, 1)
+	start := min(m.commitScroll, len(lines))
+	end := min(start+visibleLines, len(lines))
+
+	return splitpanel.Panel{
+		Lines:      lines[start:end],
+		ScrollPos:  start,
+		TotalItems: len(lines),
+	}
+}
+
+func (m unpushedModel) renderFooter() string {
+	help := components.NewThemedHelp()
+
+	bindings := []key.Binding{
+		key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("j/k", "move")),
+		key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch panel")),
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "open shell in repo")),
+		key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+	}
+
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(help.ShortHelpView(bindings))
+}
@@ -0,0 +1,189 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// unpushedTestDeps reports the commits in local, keyed by branch, as not on
+// any remote.
+func unpushedTestDeps(t *testing.T, s *store.Store, local map[string][]string, out *[]string) Deps {
+	t.Helper()
+
+	deps := DefaultDeps()
+	deps.DBPath = func() string { return ":memory:" }
+	deps.OpenStore = func(_ string) (*store.Store, error) { return s, nil }
+	deps.ListRemotes = func(string) ([]string, error) { return []string{"origin"}, nil }
+	deps.UnpushedCommits = func(_, branch string) ([]string, error) {
+		return local[branch], nil
+	}
+	deps.CommitSubject = func(_, commit string) string { return "subject " + commit }
+	deps.Println = func(a ...any) (int, error) {
+		if len(a) > 0 {
+			*out = append(*out, a[0].(string))
+		} else {
+			*out = append(*out, "")
+		}
+		return 0, nil
+	}
+	deps.Printf = func(f string, a ...any) (int, error) {
+		*out = append(*out, strings.TrimSpace(fmt.Sprintf(f, a...)))
+		return 0, nil
+	}
+	return deps
+}
+
+func insertUnpushedEvent(t *testing.T, s *store.Store, path, commit, branch string, source store.Source, ts time.Time) {
+	t.Helper()
+	e := testEvent("local:"+filepath.Base(path), commit, ts)
+	e.RepoPath = path
+	e.Branch = branch
+	e.Source = source
+	insertTestEvents(t, s.DB(), e)
+}
+
+func TestUnpushed_GroupsByRepoAndBranch(t *testing.T) {
+	s := newTestStore(t)
+	repoA := t.TempDir()
+	repoB := t.TempDir()
+	require.NoError(t, s.AddRepo(repoA))
+	require.NoError(t, s.AddRepo(repoB))
+
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	insertUnpushedEvent(t, s, repoA, "aaa1111", "main", store.SourcePostCommit, ts)
+	insertUnpushedEvent(t, s, repoA, "aaa2222", "feature", store.SourcePostCommit, ts.Add(time.Minute))
+	insertUnpushedEvent(t, s, repoA, "aaa3333", "feature", store.SourcePostCommit, ts.Add(2*time.Minute))
+	insertUnpushedEvent(t, s, repoA, "aaa3333", "feature", store.SourcePrePush, ts.Add(3*time.Minute))
+	insertUnpushedEvent(t, s, repoB, "bbb1111", "main", store.SourcePostCommit, ts)
+
+	// Checkouts are not commits the user made
+	insertUnpushedEvent(t, s, repoB, "bbb2222", "main", store.SourcePostCheckout, ts)
+
+	var out []string
+	deps := unpushedTestDeps(t, s, nil, &out)
+	var checked []string
+	deps.UnpushedCommits = func(path, branch string) ([]string, error) {
+		checked = append(checked, filepath.Base(path)+" "+branch)
+		return map[string][]string{"feature": {"aaa3333", "aaa2222"}}[branch], nil
+	}
+
+	repos, err := collectUnpushed("", deps)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Base(repoA) + " main",
+		filepath.Base(repoA) + " feature",
+		filepath.Base(repoB) + " main",
+	}, checked, "git runs once per clone and branch")
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	require.Equal(t, repoA, repos[0].Path)
+	require.True(t, repos[0].HasRemotes)
+	require.Len(t, repos[0].Branches, 1)
+	require.Equal(t, "feature", repos[0].Branches[0].Name)
+	require.Len(t, repos[0].Branches[0].Commits, 2)
+	require.Equal(t, "aaa2222", repos[0].Branches[0].Commits[0].Commit)
+	require.Equal(t, "subject aaa2222", repos[0].Branches[0].Commits[0].Subject)
+}
+
+func TestUnpushed_SkipsUntrackedAndMissingRepos(t *testing.T) {
+	s := newTestStore(t)
+	missing := filepath.Join(t.TempDir(), "gone")
+	untracked := t.TempDir()
+	require.NoError(t, s.AddRepo(missing))
+
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	insertUnpushedEvent(t, s, missing, "ccc1111", "main", store.SourcePostCommit, ts)
+	insertUnpushedEvent(t, s, untracked, "ddd1111", "main", store.SourcePostCommit, ts)
+
+	var out []string
+	deps := unpushedTestDeps(t, s, map[string][]string{"main": {"ccc1111", "ddd1111"}}, &out)
+
+	err := unpushed(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+	require.Equal(t, []string{"no unpushed commits"}, out)
+}
+
+func TestUnpushed_RewrittenCommitUsesReplacement(t *testing.T) {
+	s := newTestStore(t)
+	repo := t.TempDir()
+	require.NoError(t, s.AddRepo(repo))
+
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	insertUnpushedEvent(t, s, repo, "old1111", "main", store.SourcePostCommit, ts)
+	insertUnpushedEvent(t, s, repo, "new2222", "main", store.SourcePostRewrite, ts.Add(time.Minute))
+	_, err := store.RecordRewrites(s.DB(), store.RepoEvent{
+		RepoID: "local:" + filepath.Base(repo), Commit: "new2222", Source: store.SourcePostRewrite, Timestamp: ts,
+	}, "amend", []store.CommitRewrite{{OldCommit: "old1111", NewCommit: "new2222"}})
+	require.NoError(t, err)
+
+	var out []string
+	deps := unpushedTestDeps(t, s, map[string][]string{"main": {"new2222", "old1111"}}, &out)
+
+	repos, err := collectUnpushed("", deps)
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	require.Len(t, repos[0].Branches[0].Commits, 1)
+	require.Equal(t, "new2222", repos[0].Branches[0].Commits[0].Commit)
+}
+
+func TestUnpushed_JSON(t *testing.T) {
+	s := newTestStore(t)
+	repo := t.TempDir()
+	require.NoError(t, s.AddRepo(repo))
+	insertUnpushedEvent(t, s, repo, "eee1111", "main", store.SourcePostCommit, time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+
+	var out []string
+	deps := unpushedTestDeps(t, s, map[string][]string{"main": {"eee1111"}}, &out)
+	deps.ListRemotes = func(string) ([]string, error) { return nil, nil }
+
+	err := unpushed(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	var result []struct {
+		RepoID     string `json:"repo_id"`
+		Path       string `json:"path"`
+		HasRemotes bool   `json:"has_remotes"`
+		Branches   []struct {
+			Branch  string `json:"branch"`
+			Commits []struct {
+				Commit string `json:"commit"`
+			} `json:"commits"`
+		} `json:"branches"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out[0]), &result))
+	require.Len(t, result, 1)
+	require.Equal(t, repo, result[0].Path)
+	require.False(t, result[0].HasRemotes)
+	require.Equal(t, "main", result[0].Branches[0].Branch)
+	require.Equal(t, "eee1111", result[0].Branches[0].Commits[0].Commit)
+}
+
+func TestUnpushed_RepoFilter(t *testing.T) {
+	s := newTestStore(t)
+	repoA := t.TempDir()
+	repoB := t.TempDir()
+	require.NoError(t, s.AddRepo(repoA))
+	require.NoError(t, s.AddRepo(repoB))
+
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	insertUnpushedEvent(t, s, repoA, "fff1111", "main", store.SourcePostCommit, ts)
+	insertUnpushedEvent(t, s, repoB, "fff2222", "main", store.SourcePostCommit, ts)
+
+	var out []string
+	deps := unpushedTestDeps(t, s, map[string][]string{"main": {"fff1111", "fff2222"}}, &out)
+
+	repos, err := collectUnpushed("local:"+filepath.Base(repoB), deps)
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	require.Equal(t, repoB, repos[0].Path)
+}
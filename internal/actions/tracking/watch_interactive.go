@@ -16,7 +16,7 @@ func WatchInteractive(args []string, flags *dispatchers.ParsedFlags) error {
 	return watchInteractive(args, flags, DefaultDeps())
 }
 
-func watchInteractive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+func watchInteractive(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	// Check for terminal
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("interactive watch requires an interactive terminal")
@@ -42,6 +42,13 @@ func watchInteractive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 
 	// Create model
 	m := newWatchModel(db, lastID)
+	m.aliases = loadRepoAliases(db)
+	if groupStr := flags.String("--group", ""); groupStr != "" {
+		m.filter.Group, err = groupFilter(db, groupStr)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Run program
 	p := tea.NewProgram(
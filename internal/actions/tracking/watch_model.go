@@ -2,7 +2,6 @@ package tracking
 
 import (
 	"database/sql"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui/components"
 	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/where"
 )
 
 const (
@@ -40,6 +40,10 @@ type watchModel struct {
 	// Database
 	db     *sql.DB
 	lastID int64
+	filter store.EventFilter
+
+	// Repository aliases shown instead of folder names
+	aliases repoAliases
 
 	// Event buffer (circular, newest first for display)
 	events []store.RepoEvent
@@ -68,6 +72,9 @@ type watchModel struct {
 	filterSource store.Source // -1 means no filter
 	filterRepo   string       // "" means no filter
 
+	// typingFilter is set after / while the filter takes every key
+	typingFilter bool
+
 	// Focus: 0=events, 1=sidebar, 2=drawer
 	focusedPanel    int
 	sidebarViewport components.ThemedViewport
@@ -165,6 +172,10 @@ func (m watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m watchModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.typingFilter {
+		return m.handleFilterKeys(msg)
+	}
+
 	// Global keys
 	switch msg.Type {
 	case tea.KeyCtrlC:
@@ -189,6 +200,35 @@ func (m watchModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m.handleEventsKeys(msg)
 }
 
+// handleFilterKeys edits the filter typed after /. Enter keeps it, Esc
+// drops it.
+func (m watchModel) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.typingFilter = false
+		m.filterQuery = ""
+		return m, nil
+	case tea.KeyEnter:
+		m.typingFilter = false
+		return m, nil
+	case tea.KeyBackspace:
+		if runes := []rune(m.filterQuery); len(runes) > 0 {
+			m.filterQuery = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.filterQuery += " "
+	case tea.KeyRunes:
+		m.filterQuery += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.cursor = 0
+	m.eventScroll = 0
+	return m, nil
+}
+
 func (m watchModel) handleDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc:
@@ -371,7 +411,11 @@ func (m watchModel) handleRunes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "1", "2", "3", "4", "5", "6", "7":
 		return m.toggleSourceFilter(key)
 	case "/":
-		// Start filter mode
+		// Start a new filter: a --where expression or words to search for
+		m.filterQuery = ""
+		m.typingFilter = true
+		m.cursor = 0
+		m.eventScroll = 0
 		return m, nil
 	default:
 		// Any other character goes to text filter
@@ -462,7 +506,7 @@ func (m *watchModel) moveCursor(delta int) {
 
 func (m watchModel) pollEvents() tea.Cmd {
 	return func() tea.Msg {
-		events, err := store.ListEventsSince(m.db, m.lastID)
+		events, err := store.ListEventsSinceFiltered(m.db, m.lastID, m.filter)
 		if err != nil {
 			return nil
 		}
@@ -490,8 +534,7 @@ func (m *watchModel) addEvents(events []store.RepoEvent) {
 		// Update stats
 		m.totalEvents++
 		m.byType["commit"]++ // For now all events are commits
-		repoName := filepath.Base(e.RepoPath)
-		m.byRepo[repoName]++
+		m.byRepo[m.aliases.label(e)]++
 
 		// Fetch and cache commit metadata
 		if _, exists := m.commitMeta[e.Commit]; !exists {
@@ -543,7 +586,10 @@ func (m watchModel) filteredEvents() []store.RepoEvent {
 		return m.events
 	}
 
+	// A query that parses as a --where expression filters by it instead.
+	// Tags are not loaded here, so tag predicates only match with !=
 	query := strings.ToLower(m.filterQuery)
+	expr := queryExpr(m.filterQuery)
 	var filtered []store.RepoEvent
 
 	for _, e := range m.events {
@@ -552,8 +598,12 @@ func (m watchModel) filteredEvents() []store.RepoEvent {
 			continue
 		}
 
-		// Filter by text query
-		if query != "" && !m.matchesQuery(e, query) {
+		// Filter by expression or text query
+		if expr != nil {
+			if !where.Match(expr, whereEvent(e, m.aliases, nil)) {
+				continue
+			}
+		} else if query != "" && !m.matchesQuery(e, query) {
 			continue
 		}
 
@@ -595,7 +645,7 @@ func (m watchModel) getCommitMessage(commit string) string {
 
 // matchesQuery checks if an event matches the search query.
 func (m watchModel) matchesQuery(e store.RepoEvent, query string) bool {
-	repoName := strings.ToLower(filepath.Base(e.RepoPath))
+	repoName := strings.ToLower(m.aliases.label(e))
 	return strings.Contains(repoName, query) ||
 		strings.Contains(strings.ToLower(e.Branch), query) ||
 		strings.Contains(strings.ToLower(e.Commit), query) ||
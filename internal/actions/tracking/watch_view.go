@@ -2,7 +2,6 @@ package tracking
 
 import (
 	"fmt"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -93,8 +92,16 @@ func (m watchModel) renderHeader() string {
 	if m.filterSource != -1 {
 		filterStr = mutedStyle.Render(" | Source: ") + warnStyle.Render(sourceName(m.filterSource))
 	}
-	if m.filterQuery != "" {
-		filterStr += mutedStyle.Render(" | Search: ") + mutedStyle.Render(m.filterQuery)
+	if m.filterQuery != "" || m.typingFilter {
+		label := " | Search: "
+		if queryExpr(m.filterQuery) != nil {
+			label = " | Where: "
+		}
+		query := m.filterQuery
+		if m.typingFilter {
+			query += "_"
+		}
+		filterStr += mutedStyle.Render(label) + mutedStyle.Render(query)
 	}
 
 	// Position indicator
@@ -327,7 +334,7 @@ func (m watchModel) formatEventLine(event store.RepoEvent, width int, selected b
 	timeStr := format.Time(event.Timestamp)
 
 	// Repo name (basename)
-	repoName := filepath.Base(event.RepoPath)
+	repoName := m.aliases.label(event)
 	if len(repoName) > colRepo {
 		repoName = repoName[:colRepo-3] + "..."
 	}
@@ -581,6 +588,9 @@ func (m *watchModel) buildDrawerPanel(layout *splitpanel.Layout, height int) spl
 		sourceColor := m.sourceColor(event.Source)
 		sourceStyle := lipgloss.NewStyle().Foreground(sourceColor).Bold(true)
 		lines = append(lines, sourceStyle.Render(sourceName(event.Source))+" "+labelStyle.Render("on")+" "+valueStyle.Render(event.Branch))
+		if summary := checkoutSummary(event); summary != "" {
+			lines = append(lines, labelStyle.Render(summary))
+		}
 		lines = append(lines, "")
 
 		// === CLICKABLE DETAILS ===
@@ -591,12 +601,16 @@ func (m *watchModel) buildDrawerPanel(layout *splitpanel.Layout, height int) spl
 		addClickable("Commit:  ", event.Commit)
 
 		// Repo name (clickable)
-		repoName := filepath.Base(event.RepoPath)
-		addClickable("Repo:    ", repoName)
+		addClickable("Repo:    ", m.aliases.label(event))
 
 		// Branch (clickable)
 		addClickable("Branch:  ", event.Branch)
 
+		// Previous HEAD (post-checkout only)
+		if event.PreviousCommit != "" {
+			addClickable("From:    ", event.PreviousCommit)
+		}
+
 		lines = append(lines, "")
 
 		// Author (clickable)
@@ -692,6 +706,11 @@ func (m watchModel) renderFooter() string {
 	tabBinding := key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "focus"))
 
 	switch {
+	case m.typingFilter:
+		bindings = []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "apply")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "clear")),
+		}
 	case m.focusedPanel == 2 && m.drawerOpen:
 		// Drawer focused
 		bindings = []key.Binding{
@@ -720,6 +739,7 @@ func (m watchModel) renderFooter() string {
 			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "nav")),
 			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "detail")),
 			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "source")),
+			key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
 			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
 		}
 	}
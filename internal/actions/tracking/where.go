@@ -0,0 +1,47 @@
+package tracking
+
+import (
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/where"
+)
+
+// whereFilter parses the --where flag, returning nil when it is not set.
+func whereFilter(flags *dispatchers.ParsedFlags) (where.Expr, error) {
+	input := flags.String("--where", "")
+	if input == "" {
+		return nil, nil
+	}
+	e, err := where.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --where: %w", err)
+	}
+	return e, nil
+}
+
+// queryExpr parses a / filter of the interactive views as a --where
+// expression. Anything that does not parse, such as a plain word, is nil
+// and falls back to substring search.
+func queryExpr(query string) where.Expr {
+	e, err := where.Parse(query)
+	if err != nil {
+		return nil
+	}
+	return e
+}
+
+// whereEvent gives an expression the fields of a loaded event.
+func whereEvent(e store.RepoEvent, aliases repoAliases, tags []string) where.Event {
+	return where.Event{
+		RepoID:    e.RepoID,
+		RepoAlias: aliases[e.RepoID],
+		Branch:    e.Branch,
+		Commit:    e.Commit,
+		Source:    e.Source,
+		Status:    e.Status,
+		Tags:      tags,
+		Timestamp: e.Timestamp,
+	}
+}
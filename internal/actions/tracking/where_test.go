@@ -0,0 +1,130 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func whereTestModel() activityModel {
+	// Local hours, stored in UTC as recorded events are
+	at := func(hour int) time.Time { return time.Date(2025, 3, 10, hour, 0, 0, 0, time.Local).UTC() }
+	return activityModel{
+		events: []store.RepoEvent{
+			{ID: 1, RepoID: "github.com/acme/api", RepoPath: "/src/api", Commit: "aaa1", Branch: "main", Source: store.SourcePostCommit, Timestamp: at(9)},
+			{ID: 2, RepoID: "github.com/acme/web", RepoPath: "/src/web", Commit: "bbb1", Branch: "feature/login", Source: store.SourceBackfill, Timestamp: at(20)},
+			{ID: 3, RepoID: "github.com/other/cli", RepoPath: "/src/cli", Commit: "ccc1", Branch: "main", Source: store.SourceManual, Timestamp: at(21)},
+		},
+		aliases:      repoAliases{"github.com/other/cli": "tool"},
+		annotations:  map[int64]store.Annotations{3: {Tags: []string{"billable"}}},
+		commitMeta:   map[string]git.CommitMetadata{"bbb1": {Subject: "Add main menu"}},
+		filterSource: -1,
+	}
+}
+
+func filteredIDs(m activityModel) []int64 {
+	var ids []int64
+	for _, e := range m.filteredEvents() {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestActivityModel_WhereQuery(t *testing.T) {
+	m := whereTestModel()
+
+	m.filterQuery = `repo ~ "acme/*" and source in (post-commit, backfill)`
+	require.Equal(t, []int64{1, 2}, filteredIDs(m))
+
+	m.filterQuery = "hour >= 20 and not tag = billable"
+	require.Equal(t, []int64{2}, filteredIDs(m))
+
+	m.filterQuery = "repo = tool"
+	require.Equal(t, []int64{3}, filteredIDs(m))
+
+	// Plain words are still a substring search, subjects included
+	m.filterQuery = "main"
+	require.Equal(t, []int64{1, 2, 3}, filteredIDs(m))
+	require.Nil(t, m.searchCmd())
+}
+
+func TestActivityModel_TypingFilter(t *testing.T) {
+	var model tea.Model = whereTestModel()
+	press := func(msg tea.KeyMsg) {
+		model, _ = model.Update(msg)
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// q and c are typed into the filter instead of quitting and clearing
+	press(runes("/"))
+	press(runes("source"))
+	press(tea.KeyMsg{Type: tea.KeySpace})
+	press(runes("= backfill or branch = quick"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m := model.(activityModel)
+	require.False(t, m.typingFilter)
+	require.Equal(t, "source = backfill or branch = quick", m.filterQuery)
+	require.Equal(t, []int64{2}, filteredIDs(m))
+
+	press(runes("/"))
+	press(runes("x"))
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(activityModel)
+	require.False(t, m.typingFilter)
+	require.Empty(t, m.filterQuery)
+}
+
+func TestActivityModel_SearchWaitsForIndex(t *testing.T) {
+	m := whereTestModel()
+	indexed := false
+	m.index = func() { indexed = true }
+	m.indexing = true
+	found := activitySearchMsg{events: []store.RepoEvent{whereTestModel().events[1]}}
+	m.search = func(string) (activitySearchMsg, error) { return found, nil }
+	m.filterQuery = "menu"
+
+	// Loaded fields are matched until the index is filled
+	require.Nil(t, m.searchCmd())
+	require.Equal(t, []int64{2}, filteredIDs(m))
+
+	msg := m.Init()()
+	require.True(t, indexed)
+	require.IsType(t, activityIndexedMsg{}, msg)
+
+	model, cmd := m.Update(msg)
+	require.False(t, model.(activityModel).indexing)
+	require.NotNil(t, cmd)
+	found.query = "menu"
+	require.Equal(t, found, cmd())
+}
+
+func TestActivityModel_SearchFindsUnloadedEvents(t *testing.T) {
+	m := whereTestModel()
+	older := store.RepoEvent{ID: 9, RepoID: "github.com/acme/api", RepoPath: "/src/api", Commit: "ddd1",
+		Branch: "main", Source: store.SourcePostCommit, Timestamp: m.events[0].Timestamp.Add(-time.Hour)}
+	m.filterQuery = "timeout"
+
+	model, _ := m.Update(activitySearchMsg{
+		query:       "timeout",
+		events:      []store.RepoEvent{older, m.events[2]},
+		commitMeta:  map[string]git.CommitMetadata{"ddd1": {Subject: "Raise auth timeout"}},
+		annotations: map[int64]store.Annotations{9: {Tags: []string{"auth"}}},
+	})
+	m = model.(activityModel)
+
+	// The event that was not loaded is listed among the loaded match
+	require.Equal(t, []int64{3, 9}, filteredIDs(m))
+	require.Equal(t, "Raise auth timeout", m.commitMeta["ddd1"].Subject)
+	require.Equal(t, []string{"auth"}, m.annotations[9].Tags)
+
+	// Results for a query typed past are dropped
+	m.filterQuery = "timeouts"
+	model, _ = m.Update(activitySearchMsg{query: "timeout", events: []store.RepoEvent{older}})
+	require.Empty(t, filteredIDs(model.(activityModel)))
+}
@@ -1,12 +1,16 @@
 package update
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
 
 	"github.com/footprint-tools/cli/internal/app"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/store/migrations"
 )
 
 type Deps struct {
@@ -16,6 +20,10 @@ type Deps struct {
 	CurrentVersion string
 	ExecutablePath func() (string, error)
 	RunCommand     func(name string, args ...string) error
+	// SchemaVersionOf reports the newest database schema a downloaded binary
+	// supports; DowngradeSchema reverts the database to it. Either may be nil.
+	SchemaVersionOf func(binary string) (int, error)
+	DowngradeSchema func(version int) (previous int, err error)
 }
 
 type HTTPClient interface {
@@ -35,5 +43,42 @@ func DefaultDeps() Deps {
 			cmd.Stderr = os.Stderr
 			return cmd.Run()
 		},
+		SchemaVersionOf: schemaVersionOf,
+		DowngradeSchema: downgradeSchema,
 	}
 }
+
+// schemaVersionOf asks a binary which schema version it supports.
+// Releases older than 'fp db migrations' fail here.
+func schemaVersionOf(binary string) (int, error) {
+	out, err := exec.Command(binary, "db", "migrations", "--json").Output()
+	if err != nil {
+		return 0, err
+	}
+	var info struct {
+		Supported *int `json:"supported"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil || info.Supported == nil {
+		return 0, fmt.Errorf("unexpected output from %s db migrations", binary)
+	}
+	return *info.Supported, nil
+}
+
+// downgradeSchema reverts the database to version if it is newer and
+// returns the version it had before.
+func downgradeSchema(version int) (int, error) {
+	s, err := store.NewUnmigrated(store.DBPath())
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = s.Close() }()
+
+	current, err := migrations.CurrentVersion(s.DB())
+	if err != nil {
+		return 0, err
+	}
+	if current <= version {
+		return current, nil
+	}
+	return current, s.Downgrade(version)
+}
@@ -178,6 +178,12 @@ func downloadAndInstall(deps Deps, url string) error {
 	}
 	defer func() { _ = os.Remove(binary) }()
 
+	// The new binary may be older than this one; bring the schema down to
+	// what it supports before it replaces us and can't revert newer migrations
+	if err := prepareSchema(deps, binary); err != nil {
+		return err
+	}
+
 	// Replace the current executable
 	// First, try to remove the old one (may fail if no write permission)
 	if err := os.Remove(execPath); err != nil && !os.IsNotExist(err) {
@@ -197,6 +203,34 @@ func downloadAndInstall(deps Deps, url string) error {
 	return nil
 }
 
+// prepareSchema downgrades the database when the binary being installed
+// supports an older schema than the one in use.
+func prepareSchema(deps Deps, binary string) error {
+	if deps.SchemaVersionOf == nil || deps.DowngradeSchema == nil {
+		return nil
+	}
+
+	if err := os.Chmod(binary, 0755); err != nil {
+		return fmt.Errorf("could not set permissions: %w", err)
+	}
+
+	version, err := deps.SchemaVersionOf(binary)
+	if err != nil {
+		_, _ = fmt.Fprintf(deps.Stderr, "Could not read the schema version of the new binary (%v).\n", err)
+		_, _ = fmt.Fprintf(deps.Stderr, "If it refuses the database, reinstall this version and run 'fp db downgrade <version>'.\n")
+		return nil
+	}
+
+	previous, err := deps.DowngradeSchema(version)
+	if err != nil {
+		return fmt.Errorf("could not downgrade database to schema version %d: %w", version, err)
+	}
+	if previous > version {
+		_, _ = fmt.Fprintf(deps.Stdout, "Downgraded database schema from version %d to %d\n", previous, version)
+	}
+	return nil
+}
+
 func extractBinary(archivePath string) (string, error) {
 	f, err := os.Open(archivePath)
 	if err != nil {
@@ -1025,3 +1025,55 @@ func TestNewDependencies_ExecutablePath(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, path)
 }
+
+func TestPrepareSchema(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "fp")
+	require.NoError(t, os.WriteFile(binary, []byte("binary"), 0600))
+
+	t.Run("downgrades to the new binary's version", func(t *testing.T) {
+		var stdout bytes.Buffer
+		var downgradedTo int
+		deps := Deps{
+			Stdout:          &stdout,
+			Stderr:          &stdout,
+			SchemaVersionOf: func(string) (int, error) { return 8, nil },
+			DowngradeSchema: func(version int) (int, error) {
+				downgradedTo = version
+				return 11, nil
+			},
+		}
+
+		require.NoError(t, prepareSchema(deps, binary))
+		require.Equal(t, 8, downgradedTo)
+		require.Contains(t, stdout.String(), "from version 11 to 8")
+	})
+
+	t.Run("old binary without schema info", func(t *testing.T) {
+		var stdout bytes.Buffer
+		downgraded := false
+		deps := Deps{
+			Stdout:          &stdout,
+			Stderr:          &stdout,
+			SchemaVersionOf: func(string) (int, error) { return 0, errors.New("unknown command") },
+			DowngradeSchema: func(int) (int, error) {
+				downgraded = true
+				return 0, nil
+			},
+		}
+
+		require.NoError(t, prepareSchema(deps, binary))
+		require.False(t, downgraded)
+		require.Contains(t, stdout.String(), "fp db downgrade")
+	})
+
+	t.Run("downgrade failure stops the install", func(t *testing.T) {
+		deps := Deps{
+			Stdout:          io.Discard,
+			Stderr:          io.Discard,
+			SchemaVersionOf: func(string) (int, error) { return 5, nil },
+			DowngradeSchema: func(int) (int, error) { return 11, errors.New("no down file") },
+		}
+
+		require.ErrorContains(t, prepareSchema(deps, binary), "schema version 5")
+	})
+}
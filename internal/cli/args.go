@@ -32,6 +32,105 @@ var (
 		},
 	}
 
+	SearchQueryArg = []dispatchers.ArgSpec{
+		{
+			Name:        "query",
+			Description: "Words to find in commit messages, branches and repository ids",
+			Required:    true,
+		},
+	}
+
+	RepoAliasArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "repo-id",
+			Description: "Repository id as shown by fp activity (omit to list aliases)",
+		},
+		{
+			Name:        "name",
+			Description: "Alias to show instead of the id",
+		},
+	}
+
+	RepoRelinkArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "old-id",
+			Description: "Repository id the history is recorded under",
+			Required:    true,
+		},
+		{
+			Name:        "new-id",
+			Description: "Repository id the clone derives now",
+			Required:    true,
+		},
+	}
+
+	RepoGroupArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "group",
+			Description: "Group name, e.g. a product",
+			Required:    true,
+		},
+		{
+			Name:        "repo-id",
+			Description: "Repository ids as shown by fp activity",
+			Required:    true,
+		},
+	}
+
+	NoteArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "event",
+			Description: "Event id or commit hash (at least 4 characters)",
+			Required:    true,
+		},
+		{
+			Name:        "text",
+			Description: "Note text (omit to show the current note)",
+		},
+	}
+
+	TagArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "event",
+			Description: "Event id or commit hash (omit to list all tags)",
+		},
+		{
+			Name:        "label",
+			Description: "Tags to add, e.g. incident, billable, review",
+		},
+	}
+
+	ImportPathArg = []dispatchers.ArgSpec{
+		{
+			Name:        "export-repo-or-csv",
+			Description: "Export directory or CSV file from another device",
+			Required:    true,
+		},
+	}
+
+	BackupFileArg = []dispatchers.ArgSpec{
+		{
+			Name:        "file",
+			Description: "Path of the backup file",
+			Required:    true,
+		},
+	}
+
+	ExportDirArg = []dispatchers.ArgSpec{
+		{
+			Name:        "dir",
+			Description: "Export directory (default: the export folder)",
+		},
+	}
+
+	SchemaVersionArg = []dispatchers.ArgSpec{
+		{
+			Name:        "version",
+			Description: "Schema version to downgrade to (see 'fp db migrations')",
+			Required:    true,
+		},
+	}
+
 	ThemeNameArg = []dispatchers.ArgSpec{
 		{
 			Name:        "name",
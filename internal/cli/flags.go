@@ -66,10 +66,15 @@ var (
 			Description: "Show commit message and author from git",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--show-rewrites"},
+			Description: "Include amended/rebased commits and show old -> new mappings",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"-s", "--status"},
 			ValueHint:   "<status>",
-			Description: "Filter by status: pending, exported, orphaned, skipped",
+			Description: "Filter by status: pending, exported, orphaned, skipped, imported",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
@@ -81,13 +86,19 @@ var (
 		{
 			Names:       []string{"--since"},
 			ValueHint:   "<date>",
-			Description: "Show events after date (YYYY-MM-DD)",
+			Description: "Show events from date on (e.g. 2025-03-10, yesterday, 3d, last monday)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
 			Names:       []string{"--until"},
 			ValueHint:   "<date>",
-			Description: "Show events before date (YYYY-MM-DD)",
+			Description: "Show events up to date, inclusive (e.g. 2025-03-10, yesterday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "Show events in a period (e.g. \"last week\", \"this month\", 2026-W14)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
@@ -96,6 +107,24 @@ var (
 			Description: "Filter by repository id",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--where"},
+			ValueHint:   "<expr>",
+			Description: "Filter by expression, e.g. 'repo ~ \"acme/*\" and hour >= 18'",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--tag"},
+			ValueHint:   "<label>",
+			Description: "Show only events with this tag",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"-n", "--limit"},
 			ValueHint:   "<n>",
@@ -104,6 +133,347 @@ var (
 		},
 	}
 
+	NoteFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--clear"},
+			Description: "Remove the note",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	TagFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--remove"},
+			Description: "Remove the labels instead of adding them",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	SearchFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "Only match events from date on (e.g. 2025-03-10, yesterday, 3d, last monday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "Only match events up to date, inclusive (e.g. 2025-03-10, yesterday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "Only match events in a period (e.g. \"last week\", \"this month\", 2026-W14)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-n", "--limit"},
+			ValueHint:   "<n>",
+			Description: "Limit number of results (shorthand: -<n>, e.g., -20)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	StatsFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "Only count events from date on (e.g. 2025-03-10, yesterday, 3d, last monday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "Only count events up to date, inclusive (e.g. 2025-03-10, yesterday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "Only count events in a period (e.g. \"last week\", \"this month\", 2026-W14)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--where"},
+			ValueHint:   "<expr>",
+			Description: "Filter by expression, e.g. 'repo ~ \"acme/*\" and hour >= 18'",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--group-by"},
+			ValueHint:   "<dimension>",
+			Description: "Break down by day, week, repo, branch, source, hour or weekday",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	HeatmapFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--year"},
+			ValueHint:   "<year>",
+			Description: "Show a calendar year instead of the last 53 weeks",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--where"},
+			ValueHint:   "<expr>",
+			Description: "Filter by expression, e.g. 'repo ~ \"acme/*\" and hour >= 18'",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	StandupFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--plain"},
+			Description: "Plain text instead of Markdown",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "Start from date (e.g. 2025-03-10, last friday) instead of the previous working day",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--where"},
+			ValueHint:   "<expr>",
+			Description: "Filter by expression, e.g. 'repo ~ \"acme/*\" and hour >= 18'",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	SessionsFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "Only use events from date on (e.g. 2025-03-10, yesterday, 3d, last monday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "Only use events up to date, inclusive (e.g. 2025-03-10, yesterday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "Only use events in a period (e.g. \"last week\", \"this month\", 2026-W14)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--where"},
+			ValueHint:   "<expr>",
+			Description: "Filter by expression, e.g. 'repo ~ \"acme/*\" and hour >= 18'",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--gap"},
+			ValueHint:   "<duration>",
+			Description: "Idle time that ends a session (default: session_gap, 30m)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--global"},
+			Description: "Group events from all repositories together instead of per repository",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ReportTimesheetFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--week"},
+			Description: "This week, Monday to Sunday (default)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--month"},
+			Description: "This calendar month",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--from"},
+			ValueHint:   "<date>",
+			Description: "First day of the report (e.g. 2025-03-10, last monday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--to"},
+			ValueHint:   "<date>",
+			Description: "Last day of the report (e.g. 2025-03-10, yesterday; default: today)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "Report the days in a period (e.g. \"last week\", \"this month\", 2026-W14)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--by"},
+			ValueHint:   "<repo|client>",
+			Description: "One row per repository, or per client (repository group)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--round"},
+			ValueHint:   "<duration>",
+			Description: "Round each row up to a multiple of this (default: timesheet_round, 15m)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--min-block"},
+			ValueHint:   "<duration>",
+			Description: "Least time a session counts for (default: timesheet_min_block, 15m)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--gap"},
+			ValueHint:   "<duration>",
+			Description: "Idle time that ends a session (default: session_gap, 30m)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--where"},
+			ValueHint:   "<expr>",
+			Description: "Filter by expression, e.g. 'repo ~ \"acme/*\" and hour >= 18'",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--csv"},
+			Description: "Output as CSV",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	UnpushedFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"-i", "--interactive"},
+			Description: "Browse unpushed commits and open a shell in a repository",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Filter by repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	RecordFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"--verbose"},
@@ -115,6 +485,19 @@ var (
 			Description: "Acknowledge manual execution (suppresses note)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--replay"},
+			Description: "Write spooled events to the database instead of recording",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	SpoolStatusFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 	}
 
 	SetupFlags = []dispatchers.FlagDescriptor{
@@ -178,6 +561,40 @@ var (
 		},
 	}
 
+	ReposAliasFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--clear"},
+			Description: "Remove the alias",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON (when listing)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ReposRelinkFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--all-statuses"},
+			Description: "Also move exported and skipped events (default: pending only)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--rewrite-export"},
+			Description: "Rewrite repo_id in the export CSVs (needs --all-statuses)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ReposGroupListFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	ReposScanFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"--root"},
@@ -230,7 +647,7 @@ var (
 		{
 			Names:       []string{"-s", "--status"},
 			ValueHint:   "<status>",
-			Description: "Filter by status: pending, exported, orphaned, skipped",
+			Description: "Filter by status: pending, exported, orphaned, skipped, imported",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
@@ -245,6 +662,12 @@ var (
 			Description: "Filter by repository id",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"-g", "--group"},
+			ValueHint:   "<group>",
+			Description: "Filter by repository group",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 	}
 
 	ExportFlags = []dispatchers.FlagDescriptor{
@@ -258,6 +681,24 @@ var (
 			Description: "Show what would be exported without doing it",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "With --dry-run, only show events from date on (e.g. yesterday, 3d)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "With --dry-run, only show events up to date, inclusive",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "With --dry-run, only show events in a period (e.g. \"last week\", \"this month\", 2026-W14)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"--open"},
 			Description: "Open the export directory in file manager",
@@ -270,17 +711,102 @@ var (
 		},
 	}
 
+	PruneFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--older-than"},
+			ValueHint:   "<age>",
+			Description: "Delete events older than this (e.g. 90d, 12w, 6mo, 1y or a date; default: retention_days)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-s", "--status"},
+			ValueHint:   "<status>",
+			Description: "Only delete these statuses, comma-separated (default: exported)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show how many events would be deleted without deleting them",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DBBackupFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--force"},
+			Description: "Overwrite the file if it already exists",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DBCheckFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DBMigrationsFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DBRebuildFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--from-export"},
+			Description: "Rebuild from the CSV export",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--root"},
+			ValueHint:   "<path>",
+			Description: "Find clones of exported repositories under path",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--depth"},
+			ValueHint:   "<n>",
+			Description: "Maximum depth to scan under --root (default: 25)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show what would be restored without doing it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	BackfillFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"--since"},
 			ValueHint:   "<date>",
-			Description: "Import commits after date (YYYY-MM-DD)",
+			Description: "Import commits from date on (e.g. 2025-03-10, yesterday, 3d, last monday)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
 			Names:       []string{"--until"},
 			ValueHint:   "<date>",
-			Description: "Import commits before date (YYYY-MM-DD)",
+			Description: "Import commits up to date, inclusive (e.g. 2025-03-10, yesterday)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--during"},
+			ValueHint:   "<period>",
+			Description: "Import commits in a period (e.g. \"last week\", \"this month\", 2026-W14)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
@@ -307,6 +833,30 @@ var (
 		},
 	}
 
+	ImportFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--device"},
+			ValueHint:   "<name>",
+			Description: "Device for rows without a device column (default: unknown)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--include-local"},
+			Description: "Also import rows recorded on this machine",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show what would be imported without doing it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	LogsFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"-i", "--interactive"},
@@ -4,6 +4,7 @@ import (
 	"github.com/footprint-tools/cli/internal/actions"
 	completionsactions "github.com/footprint-tools/cli/internal/actions/completions"
 	configactions "github.com/footprint-tools/cli/internal/actions/config"
+	dbactions "github.com/footprint-tools/cli/internal/actions/db"
 	logsactions "github.com/footprint-tools/cli/internal/actions/logs"
 	setupactions "github.com/footprint-tools/cli/internal/actions/setup"
 	themeactions "github.com/footprint-tools/cli/internal/actions/theme"
@@ -50,7 +51,9 @@ completion script directly (for use with eval or redirection).`,
 	addThemeCommands(root)
 	addTrackingCommands(root)
 	addActivityCommands(root)
+	addReportCommands(root)
 	addSetupCommands(root)
+	addDBCommands(root)
 	addLogsCommand(root)
 	addUpdateCommand(root)
 	addHelpCommand(root)
@@ -202,14 +205,17 @@ func addTrackingCommands(root *dispatchers.DispatchNode) {
 	repos := dispatchers.Group(dispatchers.GroupSpec{
 		Name:    "repos",
 		Parent:  root,
-		Summary: "List and scan repositories",
-		Description: `List tracked repositories and scan for new ones.
+		Summary: "List, scan and name repositories",
+		Description: `List tracked repositories, scan for new ones, and give them aliases
+and groups.
 
 Examples:
   fp repos list         # List repos with activity
   fp repos scan         # Scan and show hook status
   fp repos check        # Verify hooks in current repo
   fp repos -i           # Interactive hook manager
+  fp repos alias github.com/acme/platform-services-api api
+  fp repos group add payments github.com/acme/pay-api github.com/acme/ledger
 
 To install/remove hooks, use 'fp setup' and 'fp teardown'.`,
 		Usage: "fp repos <command>",
@@ -252,6 +258,107 @@ Examples:
 		Category:    dispatchers.CategoryInspectActivity,
 	})
 
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "alias",
+		Parent:  repos,
+		Summary: "Show a repository under a shorter name",
+		Description: `Sets the name shown instead of a repository id in fp activity, search,
+watch and the interactive views, and used as repo_name in the export.
+The id itself never changes, so filters like --repo keep using it.
+
+Without a name it shows the current alias; without arguments it lists
+every alias and group.
+
+Examples:
+  fp repos alias github.com/acme/platform-services-api api
+  fp repos alias local:/home/me/src/x scratch
+  fp repos alias github.com/acme/platform-services-api --clear`,
+		Usage:    "fp repos alias [<repo-id> [name]] [--clear] [--json]",
+		Args:     RepoAliasArgs,
+		Flags:    ReposAliasFlags,
+		Action:   trackingactions.ReposAlias,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "relink",
+		Parent:  repos,
+		Summary: "Move history to a repository's new id",
+		Description: `Repository ids come from the remote URL, so renaming an organization or
+moving a repository to another host gives it a new id and splits its
+history in two. fp record warns when a tracked clone starts deriving a
+different id; relink moves the events recorded under the old id to the
+new one, together with tracked clones, alias and group.
+
+Only pending events move by default, since exported rows still carry the
+old id. With --all-statuses every event moves; add --rewrite-export to
+also replace the old id in the export CSVs and commit the change.
+
+Examples:
+  fp repos relink github.com/old-org/api github.com/new-org/api
+  fp repos relink github.com/old-org/api gitlab.com/new-org/api --all-statuses --rewrite-export`,
+		Usage:    "fp repos relink <old-id> <new-id> [--all-statuses] [--rewrite-export]",
+		Args:     RepoRelinkArgs,
+		Flags:    ReposRelinkFlags,
+		Action:   trackingactions.ReposRelink,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	group := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "group",
+		Parent:  repos,
+		Summary: "Group repositories, e.g. by product",
+		Description: `Groups collect repositories that belong together, such as the services
+of one product. A repository is in at most one group; adding it to
+another group moves it.
+
+Filter by group with --group in fp activity, fp search and fp watch.
+
+Examples:
+  fp repos group add payments github.com/acme/pay-api github.com/acme/ledger
+  fp repos group remove payments github.com/acme/ledger
+  fp repos group list
+  fp activity --group payments`,
+		Usage: "fp repos group [command]",
+	})
+
+	// Without a subcommand the groups are listed
+	group.Flags = ReposGroupListFlags
+	group.Action = trackingactions.ReposGroupList
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "add",
+		Parent:      group,
+		Summary:     "Add repositories to a group",
+		Description: `Adds repositories to a group, creating it if needed. Repositories already in another group are moved.`,
+		Usage:       "fp repos group add <group> <repo-id>...",
+		Args:        RepoGroupArgs,
+		Action:      trackingactions.ReposGroupAdd,
+		Category:    dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "remove",
+		Parent:      group,
+		Summary:     "Remove repositories from a group",
+		Description: `Takes repositories out of a group. Their events and aliases are kept.`,
+		Usage:       "fp repos group remove <group> <repo-id>...",
+		Args:        RepoGroupArgs,
+		Action:      trackingactions.ReposGroupRemove,
+		Category:    dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "list",
+		Parent:      group,
+		Summary:     "List groups and aliases",
+		Description: `Shows each group with its repositories and their aliases.`,
+		Usage:       "fp repos group list [--json]",
+		Flags:       ReposGroupListFlags,
+		Action:      trackingactions.ReposGroupList,
+		Category:    dispatchers.CategoryInspectActivity,
+	})
+
 	// Interactive mode at group level (no Action = shows help by default)
 	repos.Flags = ReposFlags
 	repos.InteractiveAction = trackingactions.ReposInteractive
@@ -262,12 +369,44 @@ Examples:
 		Summary: "Save a git event (internal)",
 		Description: `Saves a git event to the database.
 
-This runs automatically via git hooks. You don't need to use it directly.`,
-		Usage:    "fp record",
+This runs automatically via git hooks. You don't need to use it directly.
+
+Hook arguments are forwarded so fp can store extra context, such as the
+previous HEAD and branch for post-checkout. Hook managers can pass the
+hook name first: fp record post-checkout "$@"
+
+If the database can't be written (locked, disk full, permissions), the
+event is saved to a spool file and replayed by the next successful
+record. Use --replay to replay it now, and 'fp spool status' to see it.`,
+		Usage:    "fp record [<hook>] [<hook-args>...] [--replay]",
 		Flags:    RecordFlags,
 		Action:   trackingactions.Record,
 		Category: dispatchers.CategoryPlumbing,
 	})
+
+	spool := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "spool",
+		Parent:  root,
+		Summary: "Inspect events waiting to be recorded",
+		Description: `Events that could not be written to the database wait in a spool
+file until a later record replays them.
+
+Examples:
+  fp spool status         # List spooled events and why they failed
+  fp record --replay      # Write them to the database now`,
+		Usage: "fp spool <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "status",
+		Parent:      spool,
+		Summary:     "List events waiting in the spool",
+		Description: `Shows each spooled event with the error that kept it out of the database.`,
+		Usage:       "fp spool status [--json]",
+		Flags:       SpoolStatusFlags,
+		Action:      trackingactions.SpoolStatus,
+		Category:    dispatchers.CategoryPlumbing,
+	})
 }
 
 func addActivityCommands(root *dispatchers.DispatchNode) {
@@ -278,6 +417,16 @@ func addActivityCommands(root *dispatchers.DispatchNode) {
 		Description: `Shows recent git events across all tracked repositories.
 
 Each entry shows: time, event type, repository, commit/branch info.
+Commits replaced by an amend or rebase are hidden; use --show-rewrites
+to include them along with the old -> new commit mappings.
+
+--since and --until take dates such as 2025-03-10, yesterday, 3d, 2w,
+last monday or 2026-W14; --until includes the whole day it names.
+--during "last week" covers a whole period.
+
+--where filters by an expression over repo, branch, commit, source,
+status, tag, hour, weekday and date (see 'fp help workflow'). Typing
+an expression after / in the interactive viewer filters the same way.
 
 Examples:
   fp activity           # Recent events
@@ -285,13 +434,216 @@ Examples:
   fp activity -50       # Show 50 events (shorthand for -n 50)
   fp activity -e        # Include commit messages
   fp activity --json    # Output as JSON
-  fp activity --repo github.com/user/project  # One repo only`,
+  fp activity --show-rewrites  # Include amended/rebased commits
+  fp activity --repo github.com/user/project  # One repo only
+  fp activity --since yesterday  # Since the start of yesterday
+  fp activity --during "last week"  # Monday to Sunday of last week
+  fp activity --tag billable  # Events tagged with fp tag
+  fp activity --group payments  # Repos grouped with fp repos group
+  fp activity --where 'repo in (api, web) and source != backfill'`,
 		Usage:    "fp activity [options]",
 		Action:   trackingactions.Activity,
 		Flags:    ActivityFlags,
 		Category: dispatchers.CategoryInspectActivity,
 	})
 
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "stats",
+		Parent:  root,
+		Summary: "Summarize recorded activity",
+		Description: `Summarizes recorded events: totals, lines added and removed, active
+days, the longest streak of consecutive days, the busiest weekday and hour,
+and the most active repositories and sources.
+
+With --group-by, shows a table of events, commits and line changes per
+day, ISO week, repository, branch, source, hour of the day or weekday.
+
+Commits count once however many events recorded them; checkouts and
+pushes are events but not commits. Line changes come from metadata
+snapshots (snapshot_metadata), so they only cover commits that have one.
+Days, hours and weekdays are in local time.
+
+Examples:
+  fp stats                          # Overview of all activity
+  fp stats --during "this year"     # This year only
+  fp stats --group-by week          # Events and commits per week
+  fp stats --group-by hour --repo github.com/user/project
+  fp stats --json`,
+		Usage:    "fp stats [options]",
+		Action:   trackingactions.Stats,
+		Flags:    StatsFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "heatmap",
+		Parent:  root,
+		Summary: "Show a calendar of daily activity",
+		Description: `Draws a contribution calendar of recorded events: one column per week,
+one row per weekday, with busier days in darker shades of the theme's
+success color. Month names run along the top; the total, the number of
+active days and the busiest day are shown below with a legend.
+
+Covers the last 53 weeks by default, or a calendar year with --year.
+Unlike a hosting provider's graph it includes private and local-only
+repositories. With --no-color, or when piped, days are drawn in ASCII
+(. - + * #).
+
+Examples:
+  fp heatmap                        # The last year
+  fp heatmap --year 2025
+  fp heatmap --group acme
+  fp heatmap --no-color > activity.txt`,
+		Usage:    "fp heatmap [--year <year>] [--repo <id>] [--group <group>]",
+		Action:   trackingactions.Heatmap,
+		Flags:    HeatmapFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "standup",
+		Parent:  root,
+		Summary: "Summarize work since the previous working day",
+		Description: `Lists the commits made since the start of the previous working day,
+grouped by repository and branch, as Markdown ready to paste into a chat.
+On Monday that reaches back to Friday; set work_days for other weeks
+(e.g. fp config set work_days sun-thu).
+
+Each commit shows its subject, taken from the metadata snapshot or read
+from the repository. Amended and rebased commits appear once, in their
+final form. Branches whose commits were all pushed are marked pushed;
+when only some were, the commits made after the last push are marked
+not pushed. Checkouts are left out.
+
+Examples:
+  fp standup                        # Markdown since the last working day
+  fp standup --plain                # Plain text
+  fp standup --since "last monday"  # A longer stretch
+  fp standup --group acme --json`,
+		Usage:    "fp standup [options]",
+		Action:   trackingactions.Standup,
+		Flags:    StandupFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "sessions",
+		Parent:  root,
+		Summary: "Group activity into work sessions",
+		Description: `Groups recorded events into work sessions. A session ends when no
+event follows within the idle gap (session_gap, 30 minutes by default).
+Each session shows its date, start and end time, duration and the
+commits made; checkouts and pushes extend a session but are not counted
+as commits.
+
+Sessions are per repository, so two repos worked on side by side give
+overlapping sessions. Use --global to treat all repositories as one
+stream of work. A session with a single event lasts 0 minutes.
+
+Examples:
+  fp sessions                       # All sessions, newest first
+  fp sessions --since 2025-03-01    # Sessions since a date
+  fp sessions --repo github.com/user/project
+  fp sessions --gap 1h --global     # Longer breaks, across repos
+  fp sessions --json`,
+		Usage:    "fp sessions [options]",
+		Action:   trackingactions.Sessions,
+		Flags:    SessionsFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "search",
+		Parent:  root,
+		Summary: "Find commits by message, branch or repository",
+		Description: `Searches recorded commits by the words in their message, branch name
+and repository id. Best matches come first; hits in the subject line
+count the most. Amended or rebased commits are left out.
+
+Words match as prefixes ("auth" finds "authentication") and must all
+appear. Quote a phrase to match it exactly. Commits recorded since the
+last search are indexed first, reading messages from git or from
+metadata snapshots.
+
+Examples:
+  fp search auth timeout            # Commits mentioning both
+  fp search '"login page"'          # Exact phrase
+  fp search migration --since 2025-03-01
+  fp search fix --repo github.com/user/project --json`,
+		Usage:    "fp search <query> [options]",
+		Args:     SearchQueryArg,
+		Action:   trackingactions.Search,
+		Flags:    SearchFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "note",
+		Parent:  root,
+		Summary: "Attach a note to an event",
+		Description: `Attaches a free-form note to a recorded event. The event is given by
+its id (from fp activity --json) or by a commit hash prefix. Running the
+command again replaces the note; without text it shows the current note.
+
+Notes appear under the event in fp activity and in the interactive
+viewer, where 'n' edits the note of the selected event.
+
+Examples:
+  fp note a1b2c3d "Hotfix for the login outage"
+  fp note a1b2c3d           # Show the note
+  fp note a1b2c3d --clear   # Remove it`,
+		Usage:    "fp note <event> [text] [--clear] [--json]",
+		Args:     NoteArgs,
+		Action:   trackingactions.Note,
+		Flags:    NoteFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "tag",
+		Parent:  root,
+		Summary: "Label events for later reporting",
+		Description: `Adds labels such as "incident", "billable" or "review" to a recorded
+event. Tags are single lowercase words; a leading '#' is ignored. Without
+labels it shows the event's tags, and without arguments it lists every
+tag in use.
+
+Filter by tag with fp activity --tag. In the interactive viewer, 't'
+edits the tags of the selected event.
+
+Examples:
+  fp tag a1b2c3d billable review   # Add two tags
+  fp tag a1b2c3d review --remove   # Remove one
+  fp tag                           # List tags and how often they are used`,
+		Usage:    "fp tag [<event> <label>...] [--remove] [--json]",
+		Args:     TagArgs,
+		Action:   trackingactions.Tag,
+		Flags:    TagFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "unpushed",
+		Parent:  root,
+		Summary: "Find commits that never reached a remote",
+		Description: `Lists commits recorded in tracked repositories that are not contained
+in any remote-tracking branch, grouped by repository and branch.
+
+Each commit is checked with 'git branch -r --contains', so the result
+reflects each clone's last fetch. Amended or rebased commits are checked
+through their replacements.
+
+Examples:
+  fp unpushed           # All tracked repositories
+  fp unpushed -i        # Browse and open a shell in a repo
+  fp unpushed --json    # Output as JSON
+  fp unpushed --repo github.com/user/project  # One repo only`,
+		Usage:    "fp unpushed [options]",
+		Action:   trackingactions.Unpushed,
+		Flags:    UnpushedFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "watch",
 		Parent:  root,
@@ -341,14 +693,67 @@ Duplicates are skipped automatically.
 Examples:
   fp backfill                     # Import all past commits
   fp backfill --since 2024-01-01  # From a specific date
+  fp backfill --during 2024-03    # One month of history
   fp backfill --limit 100         # Only last 100 commits
   fp backfill --dry-run           # Preview without importing`,
-		Usage:    "fp backfill [path] [--since=<date>] [--until=<date>] [--limit=<n>]",
+		Usage:    "fp backfill [path] [--since=<date>] [--until=<date>] [--during=<period>] [--limit=<n>]",
 		Args:     OptionalRepoPathArg,
 		Flags:    BackfillFlags,
 		Action:   trackingactions.Backfill,
 		Category: dispatchers.CategoryManageRepos,
 	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "import",
+		Parent:  root,
+		Summary: "Import events exported on other devices",
+		Description: `Imports the CSV export of another machine, so activity and stats
+show your combined history across devices.
+
+Pass an export directory (reads every commits*.csv) or a single CSV file.
+Events are attributed to the device that recorded them and get the
+imported status, so they are never exported again from this machine.
+Events already in the database are left untouched.
+
+Rows recorded on this machine are skipped unless --include-local is given.
+Exports written before the device column existed are attributed to
+--device (default: unknown).
+
+Examples:
+  fp import ~/sync/laptop-exports           # A synced export directory
+  fp import commits-2025.csv --device work  # A CSV without a device column
+  fp import ~/sync/laptop-exports --dry-run # Preview without importing`,
+		Usage:    "fp import <export-repo-or-csv> [--device <name>] [--include-local] [--dry-run] [--json]",
+		Args:     ImportPathArg,
+		Flags:    ImportFlags,
+		Action:   trackingactions.Import,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "prune",
+		Parent:  root,
+		Summary: "Delete old events from the database",
+		Description: `Deletes events recorded before a given age, then compacts the database.
+
+By default only exported events are deleted, so the CSV export keeps
+everything. Set retention_keep_exported_only=false or pass --status to
+choose other statuses.
+
+Without --older-than, the age comes from retention_days. When
+retention_days is set, the same pruning also runs with the automatic
+export.
+
+Examples:
+  fp prune --older-than 365d --dry-run   # Preview
+  fp prune --older-than 1y               # Delete exported events over a year old
+  fp prune --older-than 90d --status exported,orphaned
+  fp prune --json                        # Use retention_days`,
+		Usage:    "fp prune [--older-than <age>] [--status <status>] [--dry-run] [--json]",
+		Flags:    PruneFlags,
+		Action:   trackingactions.Prune,
+		Category: dispatchers.CategoryManageRepos,
+	})
 }
 
 func addSetupCommands(root *dispatchers.DispatchNode) {
@@ -399,6 +804,188 @@ Examples:
 	})
 }
 
+func addReportCommands(root *dispatchers.DispatchNode) {
+	report := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "report",
+		Parent:  root,
+		Summary: "Summarize activity for invoices and reviews",
+		Description: `Reports built from work sessions (see 'fp sessions').
+
+Examples:
+  fp report timesheet              # Hours per day and repository this week
+  fp report timesheet --month --by client --csv > invoice.csv`,
+		Usage: "fp report <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "timesheet",
+		Parent:  report,
+		Summary: "Estimate hours per day and repository or client",
+		Description: `Turns work sessions into hours per day and per repository. With
+--by client, repositories are billed under their group (set with
+'fp repos group add <client> <repo-id>'); repos outside any group get
+their own rows.
+
+Each session counts for at least the minimum block (timesheet_min_block,
+15 minutes), so a session with a single commit is not lost. Each day's
+time per row is then rounded up to timesheet_round (15 minutes). Use 0
+for either to turn it off. Sessions count on the day they start.
+
+The report covers this week unless --month, --during or --from/--to is
+given. Dates can be relative, as in --from "last monday" or --during
+"last month".
+
+Examples:
+  fp report timesheet                           # This week
+  fp report timesheet --month --by client       # This month per client
+  fp report timesheet --from 2025-03-01 --to 2025-03-15 --round 30m
+  fp report timesheet --week --csv > week.csv
+  fp report timesheet --during 2026-W14 --by client
+  fp report timesheet --json`,
+		Usage:    "fp report timesheet [--week|--month|--during <period>|--from <date> [--to <date>]] [options]",
+		Flags:    ReportTimesheetFlags,
+		Action:   trackingactions.ReportTimesheet,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+}
+
+func addDBCommands(root *dispatchers.DispatchNode) {
+	db := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "db",
+		Parent:  root,
+		Summary: "Back up, restore and check the database",
+		Description: `Maintenance commands for the fp database.
+
+Use these instead of copying store.db by hand: a plain copy taken while
+a hook is writing can miss changes still in the write-ahead log.
+
+Examples:
+  fp db backup ~/fp-backup.db    # Consistent copy, safe while hooks run
+  fp db restore ~/fp-backup.db   # Replace the database with a backup
+  fp db check                    # Look for corruption
+  fp db vacuum                   # Reclaim unused space
+  fp db migrations               # Show the schema version
+  fp db rebuild --from-export    # Recover a lost database from the CSV export`,
+		Usage: "fp db <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "backup",
+		Parent:  db,
+		Summary: "Copy the database to a file",
+		Description: `Writes a copy of the database using SQLite's online backup API.
+
+The copy is consistent even if git hooks record events while it runs.`,
+		Usage:    "fp db backup <file> [--force]",
+		Args:     BackupFileArg,
+		Flags:    DBBackupFlags,
+		Action:   dbactions.Backup,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "restore",
+		Parent:  db,
+		Summary: "Replace the database with a backup",
+		Description: `Restores a backup written by 'fp db backup'.
+
+The backup's schema version is checked first: backups from an older fp
+are migrated after restoring, and backups from a newer fp are refused.
+The current database is saved next to it as store.db.before-restore.`,
+		Usage:    "fp db restore <file>",
+		Args:     BackupFileArg,
+		Action:   dbactions.Restore,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "check",
+		Parent:  db,
+		Summary: "Check the database for corruption",
+		Description: `Runs SQLite's integrity and foreign key checks.
+
+Exits with an error when problems are found.`,
+		Usage:    "fp db check [--json]",
+		Flags:    DBCheckFlags,
+		Action:   dbactions.Check,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "vacuum",
+		Parent:      db,
+		Summary:     "Compact the database file",
+		Description: `Checkpoints the write-ahead log and rebuilds the database file to reclaim unused space.`,
+		Usage:       "fp db vacuum",
+		Action:      dbactions.Vacuum,
+		Category:    dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "migrations",
+		Parent:  db,
+		Summary: "List applied and pending schema migrations",
+		Description: `Shows the database schema version and every migration this fp knows,
+with the time each one was applied.
+
+Versions applied by a newer fp are listed as unknown. fp refuses to use
+a database newer than itself; update fp, or downgrade the schema with
+the newer fp.`,
+		Usage:    "fp db migrations [--json]",
+		Flags:    DBMigrationsFlags,
+		Action:   dbactions.Migrations,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "downgrade",
+		Parent:  db,
+		Summary: "Revert the schema so an older fp can use it",
+		Description: `Reverts migrations newer than <version>, newest first.
+
+Only migrations that ship a down file can be reverted; 'fp db migrations
+--json' shows which are reversible. The database is saved first as
+store.db.before-downgrade. 'fp update <older-version>' does this
+automatically when the release supports an older schema.
+
+Examples:
+  fp db migrations      # Find the version the older fp needs
+  fp db downgrade 10    # Revert everything after version 10`,
+		Usage:    "fp db downgrade <version>",
+		Args:     SchemaVersionArg,
+		Action:   dbactions.Downgrade,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "rebuild",
+		Parent:  db,
+		Summary: "Rebuild the database from the CSV export",
+		Description: `Recovers events from commits.csv and the yearly commits-YYYY.csv
+files when store.db is lost. Reads the export folder (pulling its remote
+first when one is set) or the directory given.
+
+Events recorded on this machine come back as exported, so they are not
+exported again; events from other devices come back as imported.
+Repositories are registered again when a clone with fp hooks is found:
+local repositories by their path, others among the clones under --root.
+Events already in the database are left untouched.
+
+After writing, every event read is checked against the database, and
+the fields the export cannot restore are listed.
+
+Examples:
+  fp db rebuild --from-export --dry-run     # Preview
+  fp db rebuild --from-export --root ~/dev  # Also find clones under ~/dev
+  fp db rebuild --from-export ~/my-activity # A clone of the export repo`,
+		Usage:    "fp db rebuild --from-export [dir] [--root <path>] [--depth <n>] [--dry-run] [--json]",
+		Args:     ExportDirArg,
+		Flags:    DBRebuildFlags,
+		Action:   trackingactions.RebuildFromExport,
+		Category: dispatchers.CategoryManageRepos,
+	})
+}
+
 func addLogsCommand(root *dispatchers.DispatchNode) {
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "logs",
@@ -426,6 +1013,9 @@ func addUpdateCommand(root *dispatchers.DispatchNode) {
 		Summary: "Update to latest version",
 		Description: `Downloads and installs a newer version of fp.
 
+When installing an older release, the database schema is first
+downgraded to the version that release supports.
+
 Examples:
   fp update          # Install latest release
   fp update v0.1.0   # Install specific version`,
@@ -22,10 +22,23 @@ func TestBuildTree_HasExpectedTopLevelCommands(t *testing.T) {
 		"theme",
 		"repos",
 		"record",
+		"spool",
 		"activity",
+		"stats",
+		"heatmap",
+		"standup",
+		"sessions",
+		"report",
+		"search",
+		"note",
+		"tag",
+		"unpushed",
 		"watch",
 		"export",
 		"backfill",
+		"import",
+		"prune",
+		"db",
 		"setup",
 		"teardown",
 		"logs",
@@ -70,11 +83,40 @@ func TestBuildTree_ReposHasSubcommands(t *testing.T) {
 	repos, found := root.Children["repos"]
 	require.True(t, found, "repos group not found")
 
-	expectedSubcommands := []string{"list", "scan", "check"}
+	expectedSubcommands := []string{"list", "scan", "check", "alias", "relink", "group"}
 	for _, sub := range expectedSubcommands {
 		_, found := repos.Children[sub]
 		require.True(t, found, "expected repos subcommand '%s' not found", sub)
 	}
+
+	group := repos.Children["group"]
+	for _, sub := range []string{"add", "remove", "list"} {
+		_, found := group.Children[sub]
+		require.True(t, found, "expected repos group subcommand '%s' not found", sub)
+	}
+}
+
+func TestBuildTree_DBHasSubcommands(t *testing.T) {
+	root := BuildTree()
+
+	db, found := root.Children["db"]
+	require.True(t, found, "db group not found")
+
+	expectedSubcommands := []string{"backup", "restore", "check", "vacuum", "migrations", "downgrade", "rebuild"}
+	for _, sub := range expectedSubcommands {
+		_, found := db.Children[sub]
+		require.True(t, found, "expected db subcommand '%s' not found", sub)
+	}
+}
+
+func TestBuildTree_ReportHasSubcommands(t *testing.T) {
+	root := BuildTree()
+
+	report, found := root.Children["report"]
+	require.True(t, found, "report group not found")
+
+	_, found = report.Children["timesheet"]
+	require.True(t, found, "expected report subcommand 'timesheet' not found")
 }
 
 func TestBuildTree_CommandsHaveActions(t *testing.T) {
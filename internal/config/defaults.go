@@ -6,21 +6,30 @@ import (
 
 // Default configuration values (in code, not persisted)
 var Defaults = map[string]func() string{
-	"export_interval_sec": func() string { return "3600" },
-	"export_path":         paths.ExportRepoDir,
-	"export_last":         func() string { return "0" },
-	"export_remote":       func() string { return "" },
-	"theme":               func() string { return "default" }, // auto-detects -dark/-light
-	"display_date":        func() string { return "Jan 02" },
-	"display_time":        func() string { return "24h" },
-	"color_success":       func() string { return "" }, // uses theme default
-	"color_warning":       func() string { return "" }, // uses theme default
-	"color_error":         func() string { return "" }, // uses theme default
-	"color_info":          func() string { return "" }, // uses theme default
-	"color_muted":         func() string { return "" }, // uses theme default
-	"color_header":        func() string { return "" }, // uses theme default
-	"enable_log":          func() string { return "true" },
-	"pager":               func() string { return "less -FRSX" },
+	"export_interval_sec":          func() string { return "3600" },
+	"export_path":                  paths.ExportRepoDir,
+	"export_last":                  func() string { return "0" },
+	"export_remote":                func() string { return "" },
+	"snapshot_metadata":            func() string { return "false" },
+	"export_annotations":           func() string { return "false" },
+	"repo_identity":                func() string { return "remote" },
+	"session_gap":                  func() string { return "30m" },
+	"timesheet_round":              func() string { return "15m" },
+	"timesheet_min_block":          func() string { return "15m" },
+	"work_days":                    func() string { return "mon-fri" },
+	"retention_days":               func() string { return "0" },
+	"retention_keep_exported_only": func() string { return "true" },
+	"theme":                        func() string { return "default" }, // auto-detects -dark/-light
+	"display_date":                 func() string { return "Jan 02" },
+	"display_time":                 func() string { return "24h" },
+	"color_success":                func() string { return "" }, // uses theme default
+	"color_warning":                func() string { return "" }, // uses theme default
+	"color_error":                  func() string { return "" }, // uses theme default
+	"color_info":                   func() string { return "" }, // uses theme default
+	"color_muted":                  func() string { return "" }, // uses theme default
+	"color_header":                 func() string { return "" }, // uses theme default
+	"enable_log":                   func() string { return "true" },
+	"pager":                        func() string { return "less -FRSX" },
 }
 
 // Get returns the value for a config key.
@@ -1,5 +1,7 @@
 package domain
 
+import "strings"
+
 // ConfigKey defines a configuration key with its metadata.
 type ConfigKey struct {
 	Name        string
@@ -10,6 +12,12 @@ type ConfigKey struct {
 	HideIfEmpty bool   // Only show in config list if explicitly set
 }
 
+// IsArray reports whether the key holds a list of values, written one per
+// line as key[]=value.
+func (k ConfigKey) IsArray() bool {
+	return strings.HasSuffix(k.Name, "[]")
+}
+
 // ConfigKeys defines all available configuration keys.
 // This is the single source of truth for configuration.
 // Order determines display order in `fp config list`.
@@ -65,6 +73,77 @@ var ConfigKeys = []ConfigKey{
 		Description: "Remote URL for syncing exports",
 		Section:     "Export",
 	},
+	{
+		Name:        "snapshot_metadata",
+		Default:     "false",
+		Description: "Store commit metadata in the database when recording, so exports survive deleted clones (true/false)",
+		Section:     "Export",
+	},
+	{
+		Name:        "export_annotations",
+		Default:     "false",
+		Description: "Add tags and note columns to the CSV export (true/false)",
+		Section:     "Export",
+	},
+	{
+		Name:        "export_sinks[]",
+		Default:     "",
+		Description: "Export destination: '<sink>[?] [path]' with sink csv-git, dir, jsonl or sqlite, ? for best effort; one per line (default csv-git)",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	// Tracking
+	{
+		Name:        "repo_identity",
+		Default:     "remote",
+		Description: "How repository ids are derived: remote (origin URL), root-commit (survives remote changes) or path",
+		Section:     "Tracking",
+	},
+	{
+		Name:        "remote_rewrite[]",
+		Default:     "",
+		Description: "Rewrite a remote URL or id prefix before ids are derived: '<prefix> <replacement>', one rule per line",
+		Section:     "Tracking",
+		HideIfEmpty: true,
+	},
+	// Reports
+	{
+		Name:        "session_gap",
+		Default:     "30m",
+		Description: "Idle time that ends a work session in fp sessions (e.g. 30m, 1h)",
+		Section:     "Reports",
+	},
+	{
+		Name:        "timesheet_round",
+		Default:     "15m",
+		Description: "Round each timesheet row up to a multiple of this (e.g. 15m, 30m; 0 for exact minutes)",
+		Section:     "Reports",
+	},
+	{
+		Name:        "timesheet_min_block",
+		Default:     "15m",
+		Description: "Least time a work session counts for in timesheets (0 for none)",
+		Section:     "Reports",
+	},
+	{
+		Name:        "work_days",
+		Default:     "mon-fri",
+		Description: "Working days fp standup looks back to (e.g. mon-fri, sun-thu, mon,wed,fri)",
+		Section:     "Reports",
+	},
+	// Retention
+	{
+		Name:        "retention_days",
+		Default:     "0",
+		Description: "Delete events older than this many days during automatic export (0 keeps everything)",
+		Section:     "Retention",
+	},
+	{
+		Name:        "retention_keep_exported_only",
+		Default:     "true",
+		Description: "Only prune events that are already exported, so none are lost from the CSVs (true/false)",
+		Section:     "Retention",
+	},
 	// Hidden (internal)
 	{
 		Name:        "export_last",
@@ -167,7 +246,7 @@ func VisibleConfigKeys() []ConfigKey {
 
 // ConfigSections returns the ordered list of section names.
 func ConfigSections() []string {
-	return []string{"Display", "Logging", "Export", "Color Overrides"}
+	return []string{"Display", "Logging", "Export", "Tracking", "Reports", "Retention", "Color Overrides"}
 }
 
 // ConfigKeysBySection returns visible config keys grouped by section.
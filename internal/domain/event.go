@@ -15,6 +15,7 @@ const (
 	StatusExported EventStatus = 1 // stable
 	StatusOrphaned EventStatus = 2 // stable - repo no longer tracked
 	StatusSkipped  EventStatus = 3 // stable
+	StatusImported EventStatus = 4 // stable - copied from another device's export
 )
 
 // String returns the string representation of the status.
@@ -28,6 +29,8 @@ func (s EventStatus) String() string {
 		return "ORPHANED"
 	case StatusSkipped:
 		return "SKIPPED"
+	case StatusImported:
+		return "IMPORTED"
 	default:
 		return "UNKNOWN"
 	}
@@ -44,6 +47,8 @@ func ParseEventStatus(s string) (EventStatus, bool) {
 		return StatusOrphaned, true
 	case "SKIPPED":
 		return StatusSkipped, true
+	case "IMPORTED":
+		return StatusImported, true
 	default:
 		return 0, false
 	}
@@ -118,6 +123,17 @@ type RepoEvent struct {
 	Timestamp time.Time
 	Status    EventStatus
 	Source    EventSource
+
+	// Checkout context (post-checkout only)
+	PreviousCommit string
+	PreviousBranch string
+	BranchSwitch   bool
+
+	// Commit that replaced this one via amend or rebase (empty if current)
+	SupersededBy string
+
+	// Hostname of the device an imported event was recorded on (empty if local)
+	Device string
 }
 
 // EventFilter specifies criteria for querying events.
@@ -27,6 +27,9 @@ type GitProvider interface {
 	// CurrentBranch returns the current branch name.
 	CurrentBranch() (string, error)
 
+	// PreviousBranch returns the branch checked out before the current one.
+	PreviousBranch() (string, error)
+
 	// CommitMessage returns the most recent commit message.
 	CommitMessage() (string, error)
 
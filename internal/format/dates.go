@@ -0,0 +1,183 @@
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a span of time named on the command line, from Start up to but
+// not including End. A point in time, such as 3d, has End equal to Start.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Last returns the last second of the period, for filters that include
+// their end: --until 2025-03-10 keeps the whole day.
+func (p Period) Last() time.Time {
+	if !p.End.After(p.Start) {
+		return p.Start
+	}
+	return p.End.Add(-time.Second)
+}
+
+// DateHint lists the accepted date forms for error messages.
+const DateHint = "use YYYY-MM-DD, YYYY-MM, YYYY-Www, today, yesterday, monday, last monday, " +
+	"this week, last month, this year, or an age such as 12h, 3d, 2w, 6mo or 1y"
+
+var (
+	agePattern     = regexp.MustCompile(`^(\d+)(h|d|w|mo|y)$`)
+	minutesPattern = regexp.MustCompile(`^\d+m$`)
+	isoWeekPattern = regexp.MustCompile(`^(\d{4})-[wW](\d{1,2})$`)
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParsePeriod parses a date as given to --since, --until or --during,
+// relative to now and in its time zone:
+//
+//	2025-03-10             that day
+//	2025-03, 2026-W14      a month, an ISO week
+//	today, yesterday
+//	monday                 the most recent Monday, today included
+//	last monday            the Monday before today
+//	this week, last month  also day, year; weeks start on Monday
+//	12h, 3d, 2w, 6mo, 1y   the moment that long ago
+//	now
+//
+// A bare m is refused rather than read as minutes or months, since fp prune
+// once took 6m to mean six months.
+func ParsePeriod(s string, now time.Time) (Period, error) {
+	value := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	day := func(t time.Time) Period { return Period{Start: t, End: t.AddDate(0, 0, 1)} }
+
+	switch value {
+	case "":
+		return Period{}, fmt.Errorf("empty date: %s", DateHint)
+	case "now":
+		return Period{Start: now, End: now}, nil
+	case "today":
+		return day(today), nil
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1)), nil
+	}
+
+	if m := agePattern.FindStringSubmatch(value); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n > 100000 {
+			return Period{}, fmt.Errorf("date '%s' is too far back", s)
+		}
+		var t time.Time
+		switch m[2] {
+		case "h":
+			t = now.Add(-time.Duration(n) * time.Hour)
+		case "d":
+			t = now.AddDate(0, 0, -n)
+		case "w":
+			t = now.AddDate(0, 0, -7*n)
+		case "mo":
+			t = now.AddDate(0, -n, 0)
+		case "y":
+			t = now.AddDate(-n, 0, 0)
+		}
+		return Period{Start: t, End: t}, nil
+	}
+	if minutesPattern.MatchString(value) {
+		return Period{}, fmt.Errorf("ambiguous date '%s': use mo for months, e.g. %so", s, value)
+	}
+
+	if m := isoWeekPattern.FindStringSubmatch(value); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		// January 4th is always in week 1
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+7*(week-1))
+		if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+			return Period{}, fmt.Errorf("invalid ISO week '%s': %d has no week %d", s, year, week)
+		}
+		return Period{Start: monday, End: monday.AddDate(0, 0, 7)}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return day(t), nil
+	}
+	if t, err := time.ParseInLocation("2006-01", value, loc); err == nil {
+		return Period{Start: t, End: t.AddDate(0, 1, 0)}, nil
+	}
+
+	if weekday, ok := weekdayNames[value]; ok {
+		back := (int(today.Weekday()) - int(weekday) + 7) % 7
+		return day(today.AddDate(0, 0, -back)), nil
+	}
+
+	if which, unit, ok := strings.Cut(value, " "); ok && (which == "this" || which == "last") {
+		if weekday, ok := weekdayNames[unit]; ok && which == "last" {
+			back := (int(today.Weekday())-int(weekday)+6)%7 + 1
+			return day(today.AddDate(0, 0, -back)), nil
+		}
+
+		var p Period
+		switch unit {
+		case "day":
+			p = day(today)
+		case "week":
+			monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+			p = Period{Start: monday, End: monday.AddDate(0, 0, 7)}
+		case "month":
+			first := today.AddDate(0, 0, 1-today.Day())
+			p = Period{Start: first, End: first.AddDate(0, 1, 0)}
+		case "year":
+			first := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, loc)
+			p = Period{Start: first, End: first.AddDate(1, 0, 0)}
+		default:
+			return Period{}, fmt.Errorf("unrecognized date '%s': %s", s, DateHint)
+		}
+		if which == "last" {
+			p.End = p.Start
+			switch unit {
+			case "day":
+				p.Start = p.Start.AddDate(0, 0, -1)
+			case "week":
+				p.Start = p.Start.AddDate(0, 0, -7)
+			case "month":
+				p.Start = p.Start.AddDate(0, -1, 0)
+			case "year":
+				p.Start = p.Start.AddDate(-1, 0, 0)
+			}
+		}
+		return p, nil
+	}
+
+	if looksLikeDate(value) {
+		return Period{}, fmt.Errorf("invalid date '%s': expected a real YYYY-MM-DD date", s)
+	}
+	return Period{}, fmt.Errorf("unrecognized date '%s': %s", s, DateHint)
+}
+
+// looksLikeDate reports whether a value has the shape of YYYY-MM-DD, so a
+// day that does not exist gets a clearer error.
+func looksLikeDate(value string) bool {
+	parts := strings.Split(value, "-")
+	if len(parts) != 3 || len(parts[0]) != 4 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
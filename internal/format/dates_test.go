@@ -0,0 +1,87 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	local := time.FixedZone("local", -3*60*60)
+	// A Wednesday
+	now := time.Date(2026, 4, 8, 15, 30, 0, 0, local)
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, local) }
+
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"2026-03-10", day(3, 10), day(3, 11)},
+		{"2026-03", day(3, 1), day(4, 1)},
+		{"2026-W14", day(3, 30), day(4, 6)},
+		{"2026-w1", time.Date(2025, 12, 29, 0, 0, 0, 0, local), day(1, 5)},
+		{"today", day(4, 8), day(4, 9)},
+		{"Yesterday", day(4, 7), day(4, 8)},
+		{"wednesday", day(4, 8), day(4, 9)},
+		{"mon", day(4, 6), day(4, 7)},
+		{"last wednesday", day(4, 1), day(4, 2)},
+		{"last  monday", day(4, 6), day(4, 7)},
+		{"last sunday", day(4, 5), day(4, 6)},
+		{"this week", day(4, 6), day(4, 13)},
+		{"last week", day(3, 30), day(4, 6)},
+		{"this month", day(4, 1), day(5, 1)},
+		{"last month", day(3, 1), day(4, 1)},
+		{"last day", day(4, 7), day(4, 8)},
+		{"this year", day(1, 1), time.Date(2027, 1, 1, 0, 0, 0, 0, local)},
+		{"last year", time.Date(2025, 1, 1, 0, 0, 0, 0, local), day(1, 1)},
+		{"3d", now.AddDate(0, 0, -3), now.AddDate(0, 0, -3)},
+		{"2w", now.AddDate(0, 0, -14), now.AddDate(0, 0, -14)},
+		{"12h", now.Add(-12 * time.Hour), now.Add(-12 * time.Hour)},
+		{"6mo", now.AddDate(0, -6, 0), now.AddDate(0, -6, 0)},
+		{"1y", now.AddDate(-1, 0, 0), now.AddDate(-1, 0, 0)},
+		{"now", now, now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := ParsePeriod(tt.input, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.start, p.Start)
+			require.Equal(t, tt.end, p.End)
+		})
+	}
+}
+
+func TestParsePeriod_Errors(t *testing.T) {
+	now := time.Date(2026, 4, 8, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"", "empty date"},
+		{"last-week", "unrecognized date 'last-week': use YYYY-MM-DD"},
+		{"next monday", "unrecognized date 'next monday'"},
+		{"this fortnight", "unrecognized date 'this fortnight'"},
+		{"3 days ago", "unrecognized date"},
+		{"2026-02-30", "invalid date '2026-02-30': expected a real YYYY-MM-DD date"},
+		{"2026-W54", "invalid ISO week '2026-W54': 2026 has no week 54"},
+		{"2026-W0", "2026 has no week 0"},
+		{"999999d", "too far back"},
+		{"6m", "ambiguous date '6m': use mo for months, e.g. 6mo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParsePeriod(tt.input, now)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestPeriod_Last(t *testing.T) {
+	start := time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2026, 4, 8, 23, 59, 59, 0, time.UTC), Period{Start: start, End: start.AddDate(0, 0, 1)}.Last())
+	require.Equal(t, start, Period{Start: start, End: start}.Last())
+}
@@ -1,6 +1,7 @@
 package format
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -53,6 +54,16 @@ func Full(t time.Time) string {
 	return Date(t) + " " + TimeFull(t)
 }
 
+// Duration formats a span in hours and minutes, rounded down to the minute.
+// Example output: "2h 05m", "45m" or "0m"
+func Duration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", max(0, minutes))
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
 // getDateFormat returns the Go time format string for dates.
 func getDateFormat() string {
 	displayDate, _ := config.Get("display_date")
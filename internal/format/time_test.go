@@ -342,3 +342,11 @@ func TestDate_DDMMYYYYFormat(t *testing.T) {
 	result := Date(testTime)
 	require.Equal(t, "23/01/2024", result)
 }
+
+func TestDuration(t *testing.T) {
+	require.Equal(t, "0m", Duration(40*time.Second))
+	require.Equal(t, "45m", Duration(45*time.Minute+30*time.Second))
+	require.Equal(t, "2h 05m", Duration(2*time.Hour+5*time.Minute))
+	require.Equal(t, "26h 00m", Duration(26*time.Hour))
+	require.Equal(t, "0m", Duration(-time.Minute))
+}
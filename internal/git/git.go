@@ -3,6 +3,7 @@ package git
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -72,6 +73,24 @@ func OriginURL(repoRoot string) (string, error) {
 	return runGit("-C", repoRoot, "remote", "get-url", "origin")
 }
 
+// RootCommit returns the oldest root commit reachable from HEAD. Repositories
+// with merged unrelated histories have several roots; git lists the newest
+// first. Returns an empty string when the repository has no commits yet.
+func RootCommit(repoRoot string) (string, error) {
+	if _, err := runGit("-C", repoRoot, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return "", nil
+	}
+	out, err := runGit("-C", repoRoot, "rev-list", "--max-parents=0", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	roots := splitLines(out)
+	if len(roots) == 0 {
+		return "", nil
+	}
+	return roots[len(roots)-1], nil
+}
+
 // ListRemotes returns all remote names for a repository.
 func ListRemotes(repoRoot string) ([]string, error) {
 	out, err := runGit("-C", repoRoot, "remote")
@@ -104,6 +123,12 @@ func CurrentBranch() (string, error) {
 	return runGit("rev-parse", "--abbrev-ref", "HEAD")
 }
 
+// PreviousBranch returns the branch that was checked out before the current one.
+// Relies on the HEAD reflog, so it is only meaningful right after a branch switch.
+func PreviousBranch() (string, error) {
+	return runGit("rev-parse", "--abbrev-ref", "@{-1}")
+}
+
 // GetCurrentBranch returns the name of the current branch for a specific repo.
 func GetCurrentBranch(repoPath string) (string, error) {
 	return runGit("-C", repoPath, "branch", "--show-current")
@@ -157,6 +182,7 @@ type CommitMetadata struct {
 	AuthorEmail    string
 	CommitterName  string
 	CommitterEmail string
+	CommittedAt    string // Committer date in RFC3339 format
 	Subject        string // Commit message (first line)
 	Body           string // Commit message body (after first line)
 	FilesChanged   int
@@ -166,10 +192,16 @@ type CommitMetadata struct {
 
 // GetCommitMetadata retrieves enriched metadata for a specific commit from a repository.
 // repoPath is the path to the repository, commit is the full commit hash.
-// Returns empty values (not errors) if the data cannot be retrieved.
+// Returns empty values (not errors) if the data cannot be retrieved, or when
+// repoPath is empty (an imported event), rather than reading whatever
+// repository the current directory is in.
 func GetCommitMetadata(repoPath, commit string) CommitMetadata {
 	meta := CommitMetadata{}
 
+	if repoPath == "" {
+		return meta
+	}
+
 	// Validate commit reference format
 	if !isValidCommitRef(commit) {
 		log.Warn("git: invalid commit reference format: %s", commit)
@@ -182,20 +214,21 @@ func GetCommitMetadata(repoPath, commit string) CommitMetadata {
 		meta.ParentCommits = strings.Join(parentList, " ")
 	}
 
-	// Get author, committer info, author date, subject, and body using git show with format
-	// Format: author_name%x00author_email%x00author_date_iso%x00committer_name%x00committer_email%x00subject%x00body
-	format := "%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%s%x00%b"
+	// Get author, committer info, dates, subject, and body using git show with format
+	// Format: author_name%x00author_email%x00author_date_iso%x00committer_name%x00committer_email%x00committer_date_iso%x00subject%x00body
+	format := "%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%s%x00%b"
 	if info, err := runGitInRepo(repoPath, "show", "-s", "--format="+format, commit); err == nil {
 		parts := strings.Split(info, "\x00")
-		if len(parts) >= 6 {
+		if len(parts) >= 7 {
 			meta.AuthorName = parts[0]
 			meta.AuthorEmail = parts[1]
 			meta.AuthoredAt = parts[2]
 			meta.CommitterName = parts[3]
 			meta.CommitterEmail = parts[4]
-			meta.Subject = parts[5]
-			if len(parts) >= 7 {
-				meta.Body = strings.TrimSpace(parts[6])
+			meta.CommittedAt = parts[5]
+			meta.Subject = parts[6]
+			if len(parts) >= 8 {
+				meta.Body = strings.TrimSpace(parts[7])
 			}
 		}
 	}
@@ -272,11 +305,14 @@ func ListCommits(repoPath string, opts ListCommitsOptions) ([]HistoryCommit, err
 
 	args := []string{"-C", repoPath, "log", "--format=" + format, "--reverse"}
 
-	if opts.Since != "" && dateArgPattern.MatchString(opts.Since) {
-		args = append(args, "--since="+opts.Since)
-	}
-	if opts.Until != "" && dateArgPattern.MatchString(opts.Until) {
-		args = append(args, "--until="+opts.Until)
+	for _, date := range []struct{ flag, value string }{{"--since", opts.Since}, {"--until", opts.Until}} {
+		if date.value == "" {
+			continue
+		}
+		if !dateArgPattern.MatchString(date.value) {
+			return nil, fmt.Errorf("invalid %s date %q", date.flag, date.value)
+		}
+		args = append(args, date.flag+"="+date.value)
 	}
 	if opts.Limit > 0 {
 		args = append(args, "-n", strconv.Itoa(opts.Limit))
@@ -349,3 +385,56 @@ func GetBranchForCommit(repoPath, commit string) string {
 	// Return the first branch
 	return branches[0]
 }
+
+// IsZeroSHA reports whether a hash is git's null object ID (all zeros),
+// which hooks use for refs that are being created or deleted.
+func IsZeroSHA(sha string) bool {
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
+
+// CountPushedCommits returns how many commits a pre-push ref update sends.
+// When the remote ref is new (remoteSHA is the null SHA), counts the commits
+// not yet reachable from any ref of the remote. Deletions send nothing.
+func CountPushedCommits(repoPath, remote, localSHA, remoteSHA string) (int, error) {
+	if IsZeroSHA(localSHA) {
+		return 0, nil
+	}
+	if !isValidCommitRef(localSHA) {
+		return 0, fmt.Errorf("invalid commit reference: %s", localSHA)
+	}
+
+	args := []string{"rev-list", "--count", localSHA}
+	if IsZeroSHA(remoteSHA) {
+		args = append(args, "--not", "--remotes="+remote)
+	} else {
+		if !isValidCommitRef(remoteSHA) {
+			return 0, fmt.Errorf("invalid commit reference: %s", remoteSHA)
+		}
+		args = append(args, "^"+remoteSHA)
+	}
+
+	out, err := runGitInRepo(repoPath, args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// UnpushedCommits returns the commits on a local branch that no
+// remote-tracking branch contains (as of the last fetch). An empty branch,
+// such as a commit made on a detached HEAD, checks every local branch.
+func UnpushedCommits(repoPath, branch string) ([]string, error) {
+	ref := "--branches"
+	if branch != "" {
+		if strings.HasPrefix(branch, "-") || strings.ContainsAny(branch, " ~^:?*[\\") {
+			return nil, fmt.Errorf("invalid branch name: %s", branch)
+		}
+		ref = "refs/heads/" + branch
+	}
+
+	out, err := runGitInRepo(repoPath, "rev-list", ref, "--not", "--remotes")
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out), nil
+}
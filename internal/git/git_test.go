@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -124,6 +125,21 @@ func TestOriginURL_NoOrigin(t *testing.T) {
 	require.Error(t, err, "should error when origin doesn't exist")
 }
 
+func TestRootCommit(t *testing.T) {
+	repo := newTestRepo(t)
+
+	got, err := RootCommit(repo)
+	require.NoError(t, err)
+	require.Empty(t, got, "a repository without commits has no root")
+
+	first := commitFile(t, repo, "a.txt", "a")
+	commitFile(t, repo, "b.txt", "b")
+
+	got, err = RootCommit(repo)
+	require.NoError(t, err)
+	require.Equal(t, first, got)
+}
+
 func TestListRemotes(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -387,6 +403,16 @@ func TestParseDiffStats(t *testing.T) {
 	}
 }
 
+func TestGetCommitMetadata_NoRepoPath(t *testing.T) {
+	repo := newTestRepo(t)
+	commitHash := commitFile(t, repo, "test.txt", "hello world\n")
+
+	// An imported event has no clone; the repository around the current
+	// directory must not answer for it
+	t.Chdir(repo)
+	require.Equal(t, CommitMetadata{}, GetCommitMetadata("", commitHash))
+}
+
 func TestGetCommitMetadata(t *testing.T) {
 	repo := newTestRepo(t)
 
@@ -400,6 +426,8 @@ func TestGetCommitMetadata(t *testing.T) {
 	require.Equal(t, "test@example.com", meta.AuthorEmail)
 	require.Equal(t, "Add test.txt", meta.Subject)
 	require.NotEmpty(t, meta.AuthoredAt)
+	require.Equal(t, "Test User", meta.CommitterName)
+	require.NotEmpty(t, meta.CommittedAt)
 
 	// Diff stats may or may not be populated depending on git behavior
 	// Just verify they are non-negative
@@ -441,6 +469,21 @@ func TestListCommits(t *testing.T) {
 		require.Contains(t, []string{commit1, commit2, commit3}, hashes[0])
 		require.Contains(t, []string{commit1, commit2, commit3}, hashes[1])
 	})
+
+	t.Run("date range", func(t *testing.T) {
+		commits, err := ListCommits(repo, ListCommitsOptions{Since: time.Now().Add(-time.Hour).Format(time.RFC3339)})
+		require.NoError(t, err)
+		require.Len(t, commits, 3)
+
+		commits, err = ListCommits(repo, ListCommitsOptions{Until: "2000-01-01T00:00:00Z"})
+		require.NoError(t, err)
+		require.Empty(t, commits)
+	})
+
+	t.Run("invalid date is an error", func(t *testing.T) {
+		_, err := ListCommits(repo, ListCommitsOptions{Since: "yesterday; rm -rf"})
+		require.ErrorContains(t, err, `invalid --since date "yesterday; rm -rf"`)
+	})
 }
 
 func TestGetBranchForCommit(t *testing.T) {
@@ -457,6 +500,72 @@ func TestGetBranchForCommit(t *testing.T) {
 	require.Contains(t, []string{"master", "main"}, branch)
 }
 
+func TestCountPushedCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	zero := "0000000000000000000000000000000000000000"
+
+	first := commitFile(t, repo, "a.txt", "a")
+	commitFile(t, repo, "b.txt", "b")
+	third := commitFile(t, repo, "c.txt", "c")
+
+	// Existing remote ref: commits between remote and local SHA
+	count, err := CountPushedCommits(repo, "origin", third, first)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// New remote ref: nothing is on the remote yet
+	count, err = CountPushedCommits(repo, "origin", third, zero)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	// Deleting a remote ref pushes no commits
+	count, err = CountPushedCommits(repo, "origin", zero, third)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	_, err = CountPushedCommits(repo, "origin", "bad;ref", first)
+	require.Error(t, err)
+}
+
+func TestIsZeroSHA(t *testing.T) {
+	require.True(t, IsZeroSHA("0000000000000000000000000000000000000000"))
+	require.False(t, IsZeroSHA(""))
+	require.False(t, IsZeroSHA("abc0000"))
+}
+
+func TestUnpushedCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	pushed := commitFile(t, repo, "a.txt", "a")
+
+	remote := t.TempDir()
+	cmd := exec.Command("git", "init", "--bare")
+	cmd.Dir = remote
+	require.NoError(t, cmd.Run())
+	setRemote(t, repo, "origin", remote)
+
+	cmd = exec.Command("git", "push", "origin", "HEAD:refs/heads/main")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+
+	local := commitFile(t, repo, "b.txt", "b")
+	branch, err := runGitInRepo(repo, "branch", "--show-current")
+	require.NoError(t, err)
+
+	commits, err := UnpushedCommits(repo, branch)
+	require.NoError(t, err)
+	require.Equal(t, []string{local}, commits)
+	require.NotContains(t, commits, pushed)
+
+	commits, err = UnpushedCommits(repo, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{local}, commits)
+
+	_, err = UnpushedCommits(repo, "gone")
+	require.Error(t, err)
+	_, err = UnpushedCommits(repo, "--all")
+	require.Error(t, err)
+}
+
 func TestGetBranchForCommit_PreferMainMaster(t *testing.T) {
 	repo := newTestRepo(t)
 
@@ -45,6 +45,11 @@ func (p *Provider) CurrentBranch() (string, error) {
 	return CurrentBranch()
 }
 
+// PreviousBranch returns the branch checked out before the current one.
+func (p *Provider) PreviousBranch() (string, error) {
+	return PreviousBranch()
+}
+
 // CommitMessage returns the most recent commit message.
 func (p *Provider) CommitMessage() (string, error) {
 	return CommitMessage()
@@ -0,0 +1,157 @@
+// Package heatmap lays out daily event counts as a contribution calendar:
+// one column per week, one row per weekday, shaded by how busy each day was.
+package heatmap
+
+import (
+	"strings"
+	"time"
+)
+
+// Levels is the number of shades for days with activity. Level 0 is a day
+// without any.
+const Levels = 4
+
+// Glyphs are the cells drawn for each level when styling is available;
+// the theme color does the rest.
+var Glyphs = [Levels + 1]string{"·", "░", "▒", "▓", "█"}
+
+// ASCII are the cells drawn for each level under --no-color or when piped.
+var ASCII = [Levels + 1]string{".", "-", "+", "*", "#"}
+
+// outside marks grid cells before the first or after the last day.
+const outside = -1
+
+// Grid is a calendar of daily counts. Weeks start on Monday, so row 0 is
+// Monday and row 6 is Sunday.
+type Grid struct {
+	From, To time.Time // first and last day shown
+
+	// Weeks holds a column of seven counts per week. Days outside From
+	// and To, padding the first and last week, are -1
+	Weeks [][7]int
+
+	Total      int // events in the period
+	ActiveDays int // days with at least one event
+	Max        int // events on the busiest day
+	MaxDay     time.Time
+}
+
+// New builds the grid for the days from and to, both inclusive, from
+// counts keyed by YYYY-MM-DD day.
+func New(counts map[string]int, from, to time.Time) Grid {
+	from = day(from)
+	to = day(to)
+	g := Grid{From: from, To: to}
+
+	// Back up to the Monday of the first week
+	start := from.AddDate(0, 0, -weekday(from))
+	for d := start; !d.After(to) || weekday(d) != 0; d = d.AddDate(0, 0, 1) {
+		if weekday(d) == 0 {
+			g.Weeks = append(g.Weeks, [7]int{})
+		}
+		week := &g.Weeks[len(g.Weeks)-1]
+
+		if d.Before(from) || d.After(to) {
+			week[weekday(d)] = outside
+			continue
+		}
+		n := counts[d.Format("2006-01-02")]
+		week[weekday(d)] = n
+		g.Total += n
+		if n > 0 {
+			g.ActiveDays++
+		}
+		if n > g.Max {
+			g.Max, g.MaxDay = n, d
+		}
+	}
+	return g
+}
+
+// Level returns the shade of a day with count events, scaled to the
+// busiest day so the darkest shade is always used.
+func (g Grid) Level(count int) int {
+	if count <= 0 || g.Max == 0 {
+		return 0
+	}
+	return (count*Levels + g.Max - 1) / g.Max
+}
+
+// Cells draws the grid rows alone, one line per weekday. paint styles the
+// glyph of a level; days outside the period are blank.
+func (g Grid) Cells(glyphs [Levels + 1]string, paint func(level int, s string) string) []string {
+	lines := make([]string, 7)
+	for row := range lines {
+		var b strings.Builder
+		for _, week := range g.Weeks {
+			if week[row] == outside {
+				b.WriteString(" ")
+				continue
+			}
+			level := g.Level(week[row])
+			b.WriteString(paint(level, glyphs[level]))
+		}
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// Render draws the grid with month names above the weeks they start in and
+// Mon, Wed and Fri labels on the left.
+func (g Grid) Render(glyphs [Levels + 1]string, paint func(level int, s string) string) []string {
+	const labelWidth = 4
+
+	start := g.From.AddDate(0, 0, -weekday(g.From))
+	months := []byte(strings.Repeat(" ", len(g.Weeks)+3))
+	next := 0
+	for i := range g.Weeks {
+		// Label the first week and each week holding the 1st, when the
+		// previous label leaves room
+		month := start.AddDate(0, 0, 7*i+6)
+		if i == 0 {
+			month = g.From
+		} else if month.Day() > 7 || month.AddDate(0, 0, 1-month.Day()).After(g.To) {
+			continue
+		}
+		if i < next {
+			continue
+		}
+		name := month.Format("Jan")
+		copy(months[i:], name)
+		next = i + len(name) + 1
+	}
+
+	lines := []string{strings.Repeat(" ", labelWidth) + strings.TrimRight(string(months), " ")}
+	for row, cells := range g.Cells(glyphs, paint) {
+		label := ""
+		switch row {
+		case 0:
+			label = "Mon"
+		case 2:
+			label = "Wed"
+		case 4:
+			label = "Fri"
+		}
+		lines = append(lines, label+strings.Repeat(" ", labelWidth-len(label))+cells)
+	}
+	return lines
+}
+
+// Legend returns the "Less ... More" scale of shades.
+func Legend(glyphs [Levels + 1]string, paint func(level int, s string) string) string {
+	cells := make([]string, len(glyphs))
+	for level, glyph := range glyphs {
+		cells[level] = paint(level, glyph)
+	}
+	return "Less " + strings.Join(cells, " ") + " More"
+}
+
+// day truncates t to midnight in its own location.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekday returns 0 for Monday through 6 for Sunday.
+func weekday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
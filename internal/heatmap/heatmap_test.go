@@ -0,0 +1,67 @@
+package heatmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func plain(_ int, s string) string { return s }
+
+func TestNew(t *testing.T) {
+	// Wednesday 2025-01-01 to Sunday 2025-01-12
+	from := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	g := New(map[string]int{"2025-01-01": 2, "2025-01-06": 8, "2025-01-12": 1, "2025-01-20": 50}, from, to)
+
+	require.Len(t, g.Weeks, 2)
+	require.Equal(t, [7]int{-1, -1, 2, 0, 0, 0, 0}, g.Weeks[0])
+	require.Equal(t, [7]int{8, 0, 0, 0, 0, 0, 1}, g.Weeks[1])
+	require.Equal(t, 11, g.Total)
+	require.Equal(t, 3, g.ActiveDays)
+	require.Equal(t, 8, g.Max)
+	require.Equal(t, "2025-01-06", g.MaxDay.Format("2006-01-02"))
+}
+
+func TestLevel(t *testing.T) {
+	g := Grid{Max: 8}
+	require.Equal(t, 0, g.Level(0))
+	require.Equal(t, 1, g.Level(1))
+	require.Equal(t, 1, g.Level(2))
+	require.Equal(t, 2, g.Level(3))
+	require.Equal(t, 4, g.Level(8))
+
+	require.Equal(t, 0, Grid{}.Level(3))
+}
+
+func TestRender(t *testing.T) {
+	// Monday 2025-01-27 to Sunday 2025-02-16: February starts in week two
+	from := time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 16, 0, 0, 0, 0, time.UTC)
+	g := New(map[string]int{"2025-01-27": 4, "2025-02-05": 1}, from, to)
+
+	lines := g.Render(ASCII, plain)
+	require.Equal(t, []string{
+		"    Jan",
+		"Mon #..",
+		"    ...",
+		"Wed .-.",
+		"    ...",
+		"Fri ...",
+		"    ...",
+		"    ...",
+	}, lines)
+
+	require.Equal(t, "Less . - + * # More", Legend(ASCII, plain))
+}
+
+func TestRender_MonthLabels(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	lines := New(nil, from, to).Render(ASCII, plain)
+
+	// January's first week, then the weeks holding Feb 1 and Mar 1
+	require.Equal(t, "    Jan Feb Mar", lines[0])
+	require.Len(t, lines[1], 4+14)
+}
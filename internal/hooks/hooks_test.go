@@ -97,7 +97,7 @@ func TestScript_GeneratesValidScript(t *testing.T) {
 
 	require.Contains(t, script, "#!/bin/sh")
 	require.Contains(t, script, "FP_SOURCE='post-commit'")
-	require.Contains(t, script, "'/usr/local/bin/fp' record")
+	require.Contains(t, script, "'/usr/local/bin/fp' record \"$@\"")
 	require.Contains(t, script, ">/dev/null")
 }
 
@@ -267,7 +267,7 @@ To integrate Footprint with Husky, add to your Husky hook files:
   fp record post-merge
 
   # In .husky/post-checkout (create if needed):
-  fp record post-checkout`
+  fp record post-checkout "$@"`
 
 const GuidanceLefthook = `Footprint detected Lefthook in this repository.
 
@@ -286,7 +286,7 @@ To integrate Footprint with Lefthook, add to your lefthook.yml:
   post-checkout:
     commands:
       footprint:
-        run: fp record post-checkout`
+        run: fp record post-checkout {0}`
 
 const GuidanceUnmanagedHooks = `Footprint found existing hooks in this repository that are not
 managed by a known tool.
@@ -297,10 +297,10 @@ To install Footprint, you have these options:
      Then run: fp setup
 
   2. Manually add Footprint to your existing hooks by adding:
-     fp record <hook-name>
+     fp record <hook-name> "$@"
 
      For example, in .git/hooks/post-commit add:
-     fp record post-commit`
+     fp record post-commit "$@"`
 
 const GuidanceGlobalHooks = `This repository has core.hooksPath set, which means local hooks
 in .git/hooks/ are ignored by Git.
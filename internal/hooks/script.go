@@ -12,8 +12,10 @@ func Script(fpPath string, source string) string {
 	// Run fp record with the source environment variable
 	// Redirect stdout to /dev/null (suppress normal output)
 	// Errors are now logged internally by fp record via the logger
+	// Forward hook arguments ("$@") so fp record can read hook context
+	// (e.g. post-checkout previous/new HEAD and branch flag)
 	// Use proper shell quoting to prevent injection
 	return "#!/bin/sh\n" +
 		"FP_SOURCE=" + shellQuote(source) + " " +
-		shellQuote(fpPath) + " record >/dev/null 2>&1 || true\n"
+		shellQuote(fpPath) + " record \"$@\" >/dev/null 2>&1 || true\n"
 }
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/footprint-tools/cli/internal/domain"
@@ -39,57 +40,39 @@ func containsPathTraversal(s string) bool {
 	return false
 }
 
+// DeriveID derives a repository ID from a remote URL, or from the local
+// path when there is no remote. The remote_rewrite rules from the config
+// are applied first.
 func DeriveID(remoteURL, repoRoot string) (RepoID, error) {
+	return deriveID(remoteURL, repoRoot, configuredRewrites())
+}
+
+func deriveID(remoteURL, repoRoot string, rewrites []Rewrite) (RepoID, error) {
 	remoteURL = strings.TrimSpace(remoteURL)
 	repoRoot = strings.TrimSpace(repoRoot)
 
 	if remoteURL != "" {
-		remoteURL = strings.TrimSuffix(remoteURL, ".git")
-
-		if strings.HasPrefix(remoteURL, "git@") {
-			parts := strings.SplitN(remoteURL, ":", 2)
-			if len(parts) != 2 {
-				return "", errors.New("invalid ssh remote url")
-			}
-			host := strings.TrimPrefix(parts[0], "git@")
-			path := parts[1]
-
-			// Validate against path traversal
-			if containsPathTraversal(host) || containsPathTraversal(path) {
-				return "", errors.New("invalid remote url: contains path traversal sequence")
-			}
-
-			// Normalize remote URLs to lowercase to prevent duplicates
-			return RepoID(strings.ToLower(host + "/" + path)), nil
+		var (
+			id  RepoID
+			err error
+		)
+		rewritten, ok := applyRewrites(remoteURL, rewrites)
+		if ok && !strings.Contains(rewritten, ":") {
+			// A replacement without a scheme or host separator is the id itself
+			host, path, _ := strings.Cut(strings.TrimSuffix(rewritten, ".git"), "/")
+			id, err = hostPathID(host, path)
+		} else {
+			id, err = remoteID(rewritten, repoRoot)
 		}
-
-		if strings.HasPrefix(remoteURL, "https://") || strings.HasPrefix(remoteURL, "http://") {
-			remoteURL = strings.TrimPrefix(remoteURL, "https://")
-			remoteURL = strings.TrimPrefix(remoteURL, "http://")
-
-			// Validate against path traversal
-			if containsPathTraversal(remoteURL) {
-				return "", errors.New("invalid remote url: contains path traversal sequence")
-			}
-
-			// Normalize remote URLs to lowercase to prevent duplicates
-			return RepoID(strings.ToLower(remoteURL)), nil
+		if err != nil {
+			return "", err
 		}
 
-		// Support git:// protocol (read-only git protocol)
-		if path, ok := strings.CutPrefix(remoteURL, "git://"); ok {
-			if containsPathTraversal(path) {
-				return "", errors.New("invalid remote url: contains path traversal sequence")
-			}
-			return RepoID(strings.ToLower(path)), nil
+		// Rules may also name a derived id, e.g. an internal mirror host
+		if rewritten, ok := applyRewrites(string(id), rewrites); ok {
+			id = RepoID(rewritten)
 		}
-
-		// Support file:// protocol (local repositories)
-		if path, ok := strings.CutPrefix(remoteURL, "file://"); ok {
-			return RepoID("local:" + path), nil
-		}
-
-		return "", errors.New("unsupported remote url format: only git@, https://, http://, git://, and file:// are supported")
+		return id, nil
 	}
 
 	if repoRoot != "" {
@@ -100,6 +83,56 @@ func DeriveID(remoteURL, repoRoot string) (RepoID, error) {
 	return "", errors.New("cannot derive repo id")
 }
 
+// Strategy selects how repository ids are derived (the repo_identity setting).
+type Strategy string
+
+const (
+	// StrategyRemote derives the id from the origin URL, or the path when
+	// there is no origin. This is the default.
+	StrategyRemote Strategy = "remote"
+	// StrategyRootCommit derives the id from the oldest root commit, so it
+	// survives remote changes and is shared by forks and mirrors.
+	StrategyRootCommit Strategy = "root-commit"
+	// StrategyPath derives the id from the clone's location on disk.
+	StrategyPath Strategy = "path"
+)
+
+// rootCommitPrefix marks ids derived from a root commit.
+const rootCommitPrefix = "root:"
+
+// ParseStrategy parses a repo_identity value. Empty means StrategyRemote.
+func ParseStrategy(value string) (Strategy, error) {
+	switch s := Strategy(strings.ToLower(strings.TrimSpace(value))); s {
+	case "":
+		return StrategyRemote, nil
+	case StrategyRemote, StrategyRootCommit, StrategyPath:
+		return s, nil
+	default:
+		return StrategyRemote, fmt.Errorf("invalid repo_identity '%s': use root-commit, remote or path", value)
+	}
+}
+
+// Identify derives a repository id under a strategy. rootCommit is only
+// used by StrategyRootCommit; when it is empty (a repository without
+// commits) the id falls back to the remote URL.
+func Identify(strategy Strategy, remoteURL, repoRoot, rootCommit string) (RepoID, error) {
+	switch strategy {
+	case StrategyRootCommit:
+		commit := strings.ToLower(strings.TrimSpace(rootCommit))
+		if commit == "" {
+			return DeriveID(remoteURL, repoRoot)
+		}
+		if strings.Trim(commit, "0123456789abcdef") != "" {
+			return "", fmt.Errorf("invalid root commit %q", rootCommit)
+		}
+		return RepoID(rootCommitPrefix + commit), nil
+	case StrategyPath:
+		return DeriveID("", repoRoot)
+	default:
+		return DeriveID(remoteURL, repoRoot)
+	}
+}
+
 // ToFilesystemSafe converts a RepoID to a filesystem-safe directory name.
 // Transforms:
 //   - "github.com/user/repo" -> "github.com__user__repo"
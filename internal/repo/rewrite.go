@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// rewriteConfigKey is the .fprc array key holding the rewrite rules.
+const rewriteConfigKey = "remote_rewrite"
+
+// Rewrite replaces a prefix of remote URLs or repo ids, configured in .fprc
+// as remote_rewrite[]=<prefix> <replacement>. Like git's insteadOf, it maps
+// shorthands (gh:) and mirrors onto one canonical id.
+type Rewrite struct {
+	From string
+	To   string
+}
+
+// ParseRewrite parses a remote_rewrite value: a prefix and its replacement
+// separated by whitespace.
+func ParseRewrite(value string) (Rewrite, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return Rewrite{}, fmt.Errorf("invalid remote_rewrite '%s': use '<prefix> <replacement>'", value)
+	}
+	return Rewrite{From: fields[0], To: fields[1]}, nil
+}
+
+// applyRewrites replaces the longest matching prefix of s. Prefixes are
+// compared without regard to case, since ids are lowercased.
+func applyRewrites(s string, rewrites []Rewrite) (string, bool) {
+	best := -1
+	for i, r := range rewrites {
+		if len(s) < len(r.From) || !strings.EqualFold(s[:len(r.From)], r.From) {
+			continue
+		}
+		if best < 0 || len(r.From) > len(rewrites[best].From) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return s, false
+	}
+	return rewrites[best].To + s[len(rewrites[best].From):], true
+}
+
+// configuredRewrites reads the remote_rewrite rules. Invalid rules are
+// logged and skipped so a typo never stops recording.
+func configuredRewrites() []Rewrite {
+	lines, err := config.ReadLines()
+	if err != nil {
+		log.Debug("repo: could not read remote_rewrite rules: %v", err)
+		return nil
+	}
+
+	var rewrites []Rewrite
+	for _, value := range config.ParseArray(lines, rewriteConfigKey) {
+		r, err := ParseRewrite(value)
+		if err != nil {
+			log.Warn("%v", err)
+			continue
+		}
+		rewrites = append(rewrites, r)
+	}
+	return rewrites
+}
@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// remoteSchemes are the URL schemes git accepts for remotes, other than file.
+var remoteSchemes = map[string]bool{
+	"ssh":     true,
+	"git+ssh": true,
+	"ssh+git": true,
+	"git":     true,
+	"http":    true,
+	"https":   true,
+	"ftp":     true,
+	"ftps":    true,
+}
+
+// remoteID parses the remote URL forms git accepts into a host/path id:
+//
+//	ssh://[user@]host[:port]/path      git://host[:port]/path
+//	http[s]://[user[:token]@]host[:port]/path
+//	[user@]host:path                   (scp-like)
+//	/path, ./path, file:///path        (local, "local:" ids)
+//	<transport>::<address>             (remote helpers)
+//
+// User names, credentials, ports and a trailing .git are dropped, and ids
+// are lowercased so every spelling of a remote gives the same id.
+func remoteID(remoteURL, repoRoot string) (RepoID, error) {
+	url := remoteURL
+
+	// Remote helpers: the address after "::" is the actual URL
+	if transport, address, ok := strings.Cut(url, "::"); ok && transport != "" && !strings.ContainsAny(transport, "/:@") {
+		url = address
+	}
+
+	url = strings.TrimRight(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+
+	if scheme, rest, ok := strings.Cut(url, "://"); ok {
+		scheme = strings.ToLower(scheme)
+		if scheme == "file" {
+			return localID(strings.TrimPrefix(rest, "localhost"), repoRoot), nil
+		}
+		if !remoteSchemes[scheme] {
+			return "", fmt.Errorf("unsupported remote url scheme %q", scheme)
+		}
+		host, path, _ := strings.Cut(rest, "/")
+		return hostPathID(host, path)
+	}
+
+	if isLocalPath(url) {
+		return localID(url, repoRoot), nil
+	}
+
+	// scp-like syntax: a colon before the first slash separates host and path
+	colon := strings.Index(url, ":")
+	slash := strings.Index(url, "/")
+	if colon > 0 && (slash < 0 || colon < slash) {
+		return hostPathID(url[:colon], url[colon+1:])
+	}
+
+	if strings.Contains(url, "@") {
+		return "", errors.New("invalid ssh remote url: expected user@host:path")
+	}
+
+	// Anything else is a path relative to the clone
+	return localID(url, repoRoot), nil
+}
+
+// hostPathID builds the id of a network remote from its authority and path.
+func hostPathID(host, path string) (RepoID, error) {
+	// User name and credentials (user@, user:token@)
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+
+	// Port, including bracketed IPv6 hosts ([::1]:2222)
+	if bracketed, ok := strings.CutPrefix(host, "["); ok {
+		host, _, _ = strings.Cut(bracketed, "]")
+	} else {
+		host, _, _ = strings.Cut(host, ":")
+	}
+
+	// Home-relative paths (ssh://host/~user/repo, host:~/repo)
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "~/"), "~")
+	path = strings.Trim(path, "/")
+
+	if host == "" || path == "" {
+		return "", errors.New("invalid remote url: missing host or path")
+	}
+	if containsPathTraversal(host) || containsPathTraversal(path) {
+		return "", errors.New("invalid remote url: contains path traversal sequence")
+	}
+
+	return RepoID(strings.ToLower(host + "/" + path)), nil
+}
+
+// isLocalPath reports whether a remote is a filesystem path.
+func isLocalPath(url string) bool {
+	return strings.HasPrefix(url, "/") || strings.HasPrefix(url, "./") ||
+		strings.HasPrefix(url, "../") || url == "." || url == ".."
+}
+
+// localID returns the id of a remote on the local filesystem. Relative
+// paths are resolved against the clone, as git does.
+func localID(path, repoRoot string) RepoID {
+	if !filepath.IsAbs(path) && repoRoot != "" {
+		path = filepath.Join(repoRoot, path)
+	}
+	return RepoID("local:" + path)
+}
+
+// FormerRemoteID returns the id fp derived from a remote URL before every
+// URL form was parsed, when it accepted the URL at all. Those ids kept user
+// names, ports and trailing slashes, so a clone may have recorded history
+// under an id that differs from the one its remote gives now.
+func FormerRemoteID(remoteURL string) (RepoID, bool) {
+	url := strings.TrimSuffix(strings.TrimSpace(remoteURL), ".git")
+
+	if rest, ok := strings.CutPrefix(url, "git@"); ok {
+		host, path, ok := strings.Cut(rest, ":")
+		if !ok || containsPathTraversal(host) || containsPathTraversal(path) {
+			return "", false
+		}
+		return RepoID(strings.ToLower(host + "/" + path)), true
+	}
+
+	for _, scheme := range []string{"https://", "http://", "git://"} {
+		if rest, ok := strings.CutPrefix(url, scheme); ok {
+			if containsPathTraversal(rest) {
+				return "", false
+			}
+			return RepoID(strings.ToLower(rest)), true
+		}
+	}
+
+	if path, ok := strings.CutPrefix(url, "file://"); ok {
+		return RepoID("local:" + path), true
+	}
+	return "", false
+}
@@ -0,0 +1,162 @@
+// Package sessions groups recorded events into work sessions: runs of
+// activity where no two events are further apart than an idle gap.
+package sessions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// DefaultGap is the idle gap used when session_gap is not set.
+const DefaultGap = 30 * time.Minute
+
+// gapConfigKey is the .fprc key holding the idle gap.
+const gapConfigKey = "session_gap"
+
+// Session is a run of events with no idle gap between them. A session
+// with a single event starts and ends at that event.
+type Session struct {
+	Start time.Time
+	End   time.Time
+
+	// Repos are the repository ids worked on, in order of first event
+	Repos []string
+
+	// Events are the session's events, oldest first
+	Events []store.RepoEvent
+
+	// Commits counts the distinct commits made, leaving out checkouts
+	// and pushes
+	Commits int
+}
+
+// Duration returns the time between the first and last event.
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Group clusters events from all repositories into sessions, so switching
+// between repos stays one session. Sessions are returned oldest first.
+func Group(events []store.RepoEvent, gap time.Duration) []Session {
+	sorted := make([]store.RepoEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var (
+		out     []Session
+		current []store.RepoEvent
+	)
+	for _, e := range sorted {
+		if len(current) > 0 && e.Timestamp.Sub(current[len(current)-1].Timestamp) > gap {
+			out = append(out, newSession(current))
+			current = nil
+		}
+		current = append(current, e)
+	}
+	if len(current) > 0 {
+		out = append(out, newSession(current))
+	}
+	return out
+}
+
+// GroupByRepo clusters each repository's events on their own, so two repos
+// worked on side by side give overlapping sessions. Sessions are returned
+// oldest first.
+func GroupByRepo(events []store.RepoEvent, gap time.Duration) []Session {
+	byRepo := make(map[string][]store.RepoEvent)
+	for _, e := range events {
+		byRepo[e.RepoID] = append(byRepo[e.RepoID], e)
+	}
+
+	var out []Session
+	for _, repoEvents := range byRepo {
+		out = append(out, Group(repoEvents, gap)...)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Start.Equal(out[j].Start) {
+			return out[i].Repos[0] < out[j].Repos[0]
+		}
+		return out[i].Start.Before(out[j].Start)
+	})
+	return out
+}
+
+// newSession builds a session from chronologically sorted events.
+func newSession(events []store.RepoEvent) Session {
+	s := Session{
+		Start:  events[0].Timestamp,
+		End:    events[len(events)-1].Timestamp,
+		Events: events,
+	}
+
+	seenRepos := make(map[string]bool)
+	commits := make(map[string]bool)
+	for _, e := range events {
+		if !seenRepos[e.RepoID] {
+			seenRepos[e.RepoID] = true
+			s.Repos = append(s.Repos, e.RepoID)
+		}
+		if isCommitEvent(e.Source) {
+			commits[e.RepoID+"\x00"+e.Commit] = true
+		}
+	}
+	s.Commits = len(commits)
+	return s
+}
+
+// isCommitEvent reports whether events from a source record a new commit.
+// Checkouts and pushes point at commits made earlier.
+func isCommitEvent(source store.Source) bool {
+	return source != store.SourcePostCheckout && source != store.SourcePrePush
+}
+
+// ParseGap parses an idle gap: a duration such as 45m or 1h30m, or a plain
+// number of minutes.
+func ParseGap(value string) (time.Duration, error) {
+	gap, err := parseMinutes(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session gap '%s': use a duration like 30m or 1h", value)
+	}
+	if gap <= 0 {
+		return 0, fmt.Errorf("invalid session gap '%s': must be greater than 0", value)
+	}
+	return gap, nil
+}
+
+// parseMinutes parses a duration such as 15m or 1h30m, or a plain number
+// of minutes.
+func parseMinutes(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, nil
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// ConfiguredGap returns the session_gap setting. An invalid value is logged
+// and the default is used.
+func ConfiguredGap() time.Duration {
+	value, _ := config.Get(gapConfigKey)
+	if value == "" {
+		return DefaultGap
+	}
+	gap, err := ParseGap(value)
+	if err != nil {
+		log.Warn("%v", err)
+		return DefaultGap
+	}
+	return gap
+}
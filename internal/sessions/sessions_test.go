@@ -0,0 +1,116 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+var base = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func event(repoID, commit string, minutes int, source store.Source) store.RepoEvent {
+	return store.RepoEvent{
+		RepoID:    repoID,
+		Commit:    commit,
+		Timestamp: base.Add(time.Duration(minutes) * time.Minute),
+		Source:    source,
+	}
+}
+
+func TestGroup(t *testing.T) {
+	// Newest first, as ListEvents returns them
+	events := []store.RepoEvent{
+		event("web", "e5", 200, store.SourcePostCommit),
+		event("api", "e4", 50, store.SourcePrePush),
+		event("web", "e3", 30, store.SourcePostCommit),
+		event("api", "e2", 10, store.SourcePostCommit),
+		event("api", "e1", 0, store.SourcePostCheckout),
+	}
+
+	got := Group(events, 30*time.Minute)
+	require.Len(t, got, 2)
+
+	require.Equal(t, base, got[0].Start)
+	require.Equal(t, 50*time.Minute, got[0].Duration())
+	require.Equal(t, []string{"api", "web"}, got[0].Repos)
+	require.Len(t, got[0].Events, 4)
+	require.Equal(t, 2, got[0].Commits) // the checkout and push are not commits
+
+	require.Equal(t, time.Duration(0), got[1].Duration())
+	require.Equal(t, 1, got[1].Commits)
+}
+
+func TestGroup_GapIsInclusive(t *testing.T) {
+	events := []store.RepoEvent{
+		event("api", "a", 0, store.SourcePostCommit),
+		event("api", "b", 30, store.SourcePostCommit),
+		event("api", "c", 61, store.SourcePostCommit),
+	}
+
+	got := Group(events, 30*time.Minute)
+	require.Len(t, got, 2)
+	require.Equal(t, 2, got[0].Commits)
+}
+
+func TestGroupByRepo(t *testing.T) {
+	events := []store.RepoEvent{
+		event("api", "a1", 0, store.SourcePostCommit),
+		event("web", "w1", 5, store.SourcePostCommit),
+		event("api", "a2", 20, store.SourcePostCommit),
+		event("api", "a2", 25, store.SourceManual), // same commit recorded twice
+		event("web", "w2", 90, store.SourcePostCommit),
+	}
+
+	got := GroupByRepo(events, 30*time.Minute)
+	require.Len(t, got, 3)
+
+	require.Equal(t, []string{"api"}, got[0].Repos)
+	require.Equal(t, 25*time.Minute, got[0].Duration())
+	require.Equal(t, 2, got[0].Commits)
+	require.Equal(t, []string{"web"}, got[1].Repos)
+	require.Equal(t, []string{"web"}, got[2].Repos)
+	require.Equal(t, base.Add(90*time.Minute), got[2].Start)
+}
+
+func TestGroup_Empty(t *testing.T) {
+	require.Empty(t, Group(nil, DefaultGap))
+	require.Empty(t, GroupByRepo(nil, DefaultGap))
+}
+
+func TestParseGap(t *testing.T) {
+	gap, err := ParseGap("45m")
+	require.NoError(t, err)
+	require.Equal(t, 45*time.Minute, gap)
+
+	gap, err = ParseGap("1h30m")
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Minute, gap)
+
+	gap, err = ParseGap("20")
+	require.NoError(t, err)
+	require.Equal(t, 20*time.Minute, gap)
+
+	_, err = ParseGap("soon")
+	require.ErrorContains(t, err, "invalid session gap 'soon'")
+
+	_, err = ParseGap("0")
+	require.ErrorContains(t, err, "must be greater than 0")
+}
+
+func TestConfiguredGap(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fprc := filepath.Join(home, ".fprc")
+
+	require.NoError(t, os.WriteFile(fprc, []byte("session_gap=1h\n"), 0600))
+	require.Equal(t, time.Hour, ConfiguredGap())
+
+	// Invalid values fall back to the default
+	require.NoError(t, os.WriteFile(fprc, []byte("session_gap=later\n"), 0600))
+	require.Equal(t, DefaultGap, ConfiguredGap())
+}
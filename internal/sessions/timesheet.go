@@ -0,0 +1,114 @@
+package sessions
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// Default timesheet rules, used when timesheet_round and
+// timesheet_min_block are not set.
+const (
+	DefaultRoundTo  = 15 * time.Minute
+	DefaultMinBlock = 15 * time.Minute
+)
+
+// Rounding turns measured session time into billed time.
+type Rounding struct {
+	// RoundTo rounds each day's time per row up to a multiple; 0 keeps
+	// exact minutes
+	RoundTo time.Duration
+
+	// MinBlock is the least time a session is billed for, so a session
+	// with a single commit still counts
+	MinBlock time.Duration
+}
+
+// TimesheetRow is the time spent on one row (a repository or client) on
+// one day. Sessions count on the day they start, in the report's zone.
+type TimesheetRow struct {
+	Date     string // YYYY-MM-DD
+	Key      string
+	Sessions int
+	Commits  int
+	Worked   time.Duration // measured from first to last event
+	Billed   time.Duration // after the minimum block and rounding
+}
+
+// Timesheet totals sessions per day in loc and per key, applying the
+// minimum block to each session and rounding to each row. Rows are ordered
+// by date and key.
+func Timesheet(list []Session, key func(Session) string, rounding Rounding, loc *time.Location) []TimesheetRow {
+	type rowKey struct{ date, key string }
+	rows := make(map[rowKey]*TimesheetRow)
+
+	for _, s := range list {
+		k := rowKey{date: s.Start.In(loc).Format("2006-01-02"), key: key(s)}
+		row, ok := rows[k]
+		if !ok {
+			row = &TimesheetRow{Date: k.date, Key: k.key}
+			rows[k] = row
+		}
+		row.Sessions++
+		row.Commits += s.Commits
+		row.Worked += s.Duration()
+		row.Billed += max(s.Duration(), rounding.MinBlock)
+	}
+
+	out := make([]TimesheetRow, 0, len(rows))
+	for _, row := range rows {
+		row.Billed = roundUp(row.Billed, rounding.RoundTo)
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Date != out[j].Date {
+			return out[i].Date < out[j].Date
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// roundUp rounds d up to a multiple of step, after dropping seconds.
+func roundUp(d, step time.Duration) time.Duration {
+	d = d.Truncate(time.Minute)
+	if step <= 0 || d%step == 0 {
+		return d
+	}
+	return d - d%step + step
+}
+
+// ParseRounding parses a timesheet rounding or minimum block: a duration
+// such as 15m, a plain number of minutes, or 0 for none.
+func ParseRounding(name, value string) (time.Duration, error) {
+	d, err := parseMinutes(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s '%s': use a duration like 15m, or 0 for none", name, value)
+	}
+	return d, nil
+}
+
+// ConfiguredRounding returns the timesheet_round and timesheet_min_block
+// settings. Invalid values are logged and the defaults are used.
+func ConfiguredRounding() Rounding {
+	return Rounding{
+		RoundTo:  configuredDuration("timesheet_round", DefaultRoundTo),
+		MinBlock: configuredDuration("timesheet_min_block", DefaultMinBlock),
+	}
+}
+
+func configuredDuration(key string, fallback time.Duration) time.Duration {
+	value, _ := config.Get(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := ParseRounding(key, value)
+	if err != nil {
+		log.Warn("%v", err)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestTimesheet(t *testing.T) {
+	events := []store.RepoEvent{
+		// api: 50 minutes and a lone commit on the 10th, 20 minutes on the 11th
+		event("api", "a1", 0, store.SourcePostCommit),
+		event("api", "a2", 25, store.SourcePostCommit),
+		event("api", "a3", 50, store.SourcePostCommit),
+		event("api", "a6", 300, store.SourcePostCommit),
+		event("api", "a4", 24*60, store.SourcePostCommit),
+		event("api", "a5", 24*60+20, store.SourcePostCommit),
+		// web: 7 minutes on the 10th
+		event("web", "w1", 60, store.SourcePostCommit),
+		event("web", "w2", 67, store.SourcePostCommit),
+	}
+	list := GroupByRepo(events, 30*time.Minute)
+	key := func(s Session) string { return s.Repos[0] }
+
+	rows := Timesheet(list, key, Rounding{RoundTo: 15 * time.Minute, MinBlock: 15 * time.Minute}, time.UTC)
+	require.Len(t, rows, 3)
+
+	require.Equal(t, "2025-03-10", rows[0].Date)
+	require.Equal(t, "api", rows[0].Key)
+	require.Equal(t, 2, rows[0].Sessions)
+	require.Equal(t, 4, rows[0].Commits)
+	require.Equal(t, 50*time.Minute, rows[0].Worked)
+	require.Equal(t, 75*time.Minute, rows[0].Billed) // 50m + a 15m block, rounded up
+
+	require.Equal(t, "web", rows[1].Key)
+	require.Equal(t, 15*time.Minute, rows[1].Billed)
+
+	require.Equal(t, "2025-03-11", rows[2].Date)
+	require.Equal(t, 30*time.Minute, rows[2].Billed)
+
+	// No rounding and no minimum: measured time only
+	rows = Timesheet(list, key, Rounding{}, time.UTC)
+	require.Equal(t, 50*time.Minute, rows[0].Billed)
+	require.Equal(t, 7*time.Minute, rows[1].Billed)
+}
+
+func TestTimesheet_KeyMergesRepos(t *testing.T) {
+	events := []store.RepoEvent{
+		event("api", "a1", 0, store.SourcePostCommit),
+		event("api", "a2", 20, store.SourcePostCommit),
+		event("web", "w1", 5, store.SourcePostCommit),
+		event("web", "w2", 25, store.SourcePostCommit),
+	}
+	list := GroupByRepo(events, 30*time.Minute)
+
+	rows := Timesheet(list, func(Session) string { return "acme" }, Rounding{RoundTo: 30 * time.Minute}, time.UTC)
+	require.Len(t, rows, 1)
+	require.Equal(t, 2, rows[0].Sessions)
+	require.Equal(t, 40*time.Minute, rows[0].Worked)
+	require.Equal(t, time.Hour, rows[0].Billed)
+}
+
+func TestRoundUp(t *testing.T) {
+	require.Equal(t, 15*time.Minute, roundUp(time.Minute, 15*time.Minute))
+	require.Equal(t, 30*time.Minute, roundUp(30*time.Minute+40*time.Second, 15*time.Minute))
+	require.Equal(t, 45*time.Minute, roundUp(31*time.Minute, 15*time.Minute))
+	require.Equal(t, 7*time.Minute, roundUp(7*time.Minute+10*time.Second, 0))
+	require.Equal(t, time.Duration(0), roundUp(0, 15*time.Minute))
+}
+
+func TestParseRounding(t *testing.T) {
+	d, err := ParseRounding("--round", "30m")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, d)
+
+	d, err = ParseRounding("--round", "0")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), d)
+
+	_, err = ParseRounding("--min-block", "-5m")
+	require.ErrorContains(t, err, "invalid --min-block '-5m'")
+}
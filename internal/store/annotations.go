@@ -0,0 +1,250 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// minCommitPrefix is the shortest commit prefix FindEvents accepts.
+const minCommitPrefix = 4
+
+// Annotations are the user-authored tags and note of an event.
+type Annotations struct {
+	Tags []string
+	Note string
+}
+
+// IsEmpty reports whether the event has neither tags nor a note.
+func (a Annotations) IsEmpty() bool {
+	return len(a.Tags) == 0 && a.Note == ""
+}
+
+// annotatingSources orders the events of one commit by how well they stand
+// for the commit itself, so annotating a hash picks the commit over a push
+// or checkout of it.
+var annotatingSources = []Source{
+	SourcePostCommit,
+	SourcePostMerge,
+	SourcePostRewrite,
+	SourceBackfill,
+	SourceManual,
+	SourcePrePush,
+	SourcePostCheckout,
+}
+
+// FindEvent resolves an event reference: an event ID, or a commit hash or
+// prefix of at least minCommitPrefix characters. A numeric reference is
+// tried as an ID first. When a commit has several events the one that
+// recorded the commit is returned. Prefixes matching more than one commit
+// are an error.
+func FindEvent(db *sql.DB, ref string) (RepoEvent, error) {
+	ref = strings.TrimSpace(ref)
+
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		rows, err := db.Query(`SELECT`+repoEventColumns+` FROM repo_events WHERE id = ?`, id)
+		if err != nil {
+			return RepoEvent{}, err
+		}
+		events, err := scanRepoEvents(rows)
+		if err != nil {
+			return RepoEvent{}, err
+		}
+		if len(events) == 1 {
+			return events[0], nil
+		}
+	}
+
+	if len(ref) < minCommitPrefix {
+		return RepoEvent{}, fmt.Errorf("no event %s: use an event ID or at least %d characters of a commit hash", ref, minCommitPrefix)
+	}
+
+	rows, err := db.Query(`SELECT`+repoEventColumns+` FROM repo_events WHERE commit_hash LIKE ? ORDER BY id`,
+		strings.ToLower(ref)+"%")
+	if err != nil {
+		return RepoEvent{}, err
+	}
+	events, err := scanRepoEvents(rows)
+	if err != nil {
+		return RepoEvent{}, err
+	}
+	if len(events) == 0 {
+		return RepoEvent{}, fmt.Errorf("no event for commit %s", ref)
+	}
+
+	best := events[0]
+	for _, e := range events[1:] {
+		if e.Commit != best.Commit {
+			return RepoEvent{}, fmt.Errorf("commit prefix %s is ambiguous: matches %.7s and %.7s", ref, best.Commit, e.Commit)
+		}
+		if sourceRank(e.Source) < sourceRank(best.Source) {
+			best = e
+		}
+	}
+	return best, nil
+}
+
+func sourceRank(source Source) int {
+	for i, s := range annotatingSources {
+		if s == source {
+			return i
+		}
+	}
+	return len(annotatingSources)
+}
+
+func scanRepoEvents(rows *sql.Rows) ([]RepoEvent, error) {
+	defer closeRows(rows)
+
+	var out []RepoEvent
+	for rows.Next() {
+		e, err := scanRepoEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// SetNote replaces the note of an event. An empty note removes it.
+func SetNote(db *sql.DB, eventID int64, note string, now time.Time) error {
+	return setNote(db, eventID, note, now)
+}
+
+func setNote(db execer, eventID int64, note string, now time.Time) error {
+	if strings.TrimSpace(note) == "" {
+		_, err := db.Exec(`DELETE FROM event_notes WHERE event_id = ?`, eventID)
+		return err
+	}
+	_, err := db.Exec(`
+		INSERT INTO event_notes (event_id, note, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(event_id) DO UPDATE SET note = excluded.note, updated_at = excluded.updated_at`,
+		eventID, note, now.UTC().Format(time.RFC3339))
+	return err
+}
+
+// AddTag tags an event. Returns false when the event already had the tag.
+func AddTag(db *sql.DB, eventID int64, tag string, now time.Time) (bool, error) {
+	return addTag(db, eventID, tag, now)
+}
+
+func addTag(db execer, eventID int64, tag string, now time.Time) (bool, error) {
+	result, err := db.Exec(
+		`INSERT OR IGNORE INTO event_tags (event_id, tag, created_at) VALUES (?, ?, ?)`,
+		eventID, tag, now.UTC().Format(time.RFC3339))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// RemoveTag removes a tag from an event. Returns false when the event did
+// not have the tag.
+func RemoveTag(db *sql.DB, eventID int64, tag string) (bool, error) {
+	result, err := db.Exec(`DELETE FROM event_tags WHERE event_id = ? AND tag = ?`, eventID, tag)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// ListAnnotations returns the tags and notes of the given events. Events
+// without annotations are absent from the map.
+func ListAnnotations(db *sql.DB, eventIDs []int64) (map[int64]Annotations, error) {
+	out := make(map[int64]Annotations)
+
+	for start := 0; start < len(eventIDs); start += metadataBatchSize {
+		batch := eventIDs[start:min(start+metadataBatchSize, len(eventIDs))]
+		if err := listAnnotationsBatch(db, batch, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func listAnnotationsBatch(db *sql.DB, eventIDs []int64, out map[int64]Annotations) error {
+	args := make([]any, len(eventIDs))
+	for i, id := range eventIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(eventIDs)), ",")
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT event_id, tag FROM event_tags WHERE event_id IN (%s) ORDER BY event_id, tag
+	`, placeholders), args...)
+	if err != nil {
+		log.Error("store: list tags query failed: %v", err)
+		return err
+	}
+	for rows.Next() {
+		var (
+			id  int64
+			tag string
+		)
+		if err := rows.Scan(&id, &tag); err != nil {
+			closeRows(rows)
+			return err
+		}
+		a := out[id]
+		a.Tags = append(a.Tags, tag)
+		out[id] = a
+	}
+	closeRows(rows)
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = db.Query(fmt.Sprintf(`
+		SELECT event_id, note FROM event_notes WHERE event_id IN (%s)
+	`, placeholders), args...)
+	if err != nil {
+		log.Error("store: list notes query failed: %v", err)
+		return err
+	}
+	defer closeRows(rows)
+	for rows.Next() {
+		var (
+			id   int64
+			note string
+		)
+		if err := rows.Scan(&id, &note); err != nil {
+			return err
+		}
+		a := out[id]
+		a.Note = note
+		out[id] = a
+	}
+	return rows.Err()
+}
+
+// TagCount is a tag and the number of events carrying it.
+type TagCount struct {
+	Tag    string
+	Events int
+}
+
+// ListTags returns every tag in use with its event count, most used first.
+func ListTags(db *sql.DB) ([]TagCount, error) {
+	rows, err := db.Query(`SELECT tag, COUNT(*) FROM event_tags GROUP BY tag ORDER BY COUNT(*) DESC, tag`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []TagCount
+	for rows.Next() {
+		var tc TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Events); err != nil {
+			return nil, err
+		}
+		out = append(out, tc)
+	}
+	return out, rows.Err()
+}
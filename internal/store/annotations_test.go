@@ -0,0 +1,102 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func insertAnnotatedEvent(t *testing.T, db *sql.DB, commit string, source Source) RepoEvent {
+	t.Helper()
+	e := RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/repo",
+		Commit:    commit,
+		Branch:    "main",
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    source,
+	}
+	require.NoError(t, InsertEvent(db, e))
+	found, err := FindEvent(db, commit)
+	require.NoError(t, err)
+	return found
+}
+
+func TestFindEvent(t *testing.T) {
+	db := newTestDB(t)
+	push := insertAnnotatedEvent(t, db, "abcdef123456", SourcePrePush)
+	require.NoError(t, InsertEvent(db, RepoEvent{
+		RepoID: "github.com/user/repo", RepoPath: "/repo", Commit: "abcdef123456", Branch: "main",
+		Timestamp: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC), Status: StatusPending, Source: SourcePostCommit,
+	}))
+	insertAnnotatedEvent(t, db, "abc999000000", SourcePostCommit)
+
+	// A commit prefers the event that recorded it over its push
+	e, err := FindEvent(db, "abcdef")
+	require.NoError(t, err)
+	require.Equal(t, SourcePostCommit, e.Source)
+
+	e, err = FindEvent(db, "1")
+	require.NoError(t, err)
+	require.Equal(t, push.ID, e.ID)
+
+	_, err = FindEvent(db, "abc")
+	require.ErrorContains(t, err, "at least 4 characters")
+	_, err = FindEvent(db, "abc9")
+	require.NoError(t, err)
+	_, err = FindEvent(db, "abcd")
+	require.NoError(t, err)
+	_, err = FindEvent(db, "ffff")
+	require.ErrorContains(t, err, "no event")
+
+	insertAnnotatedEvent(t, db, "abcdff000000", SourcePostCommit)
+	_, err = FindEvent(db, "abcd")
+	require.ErrorContains(t, err, "ambiguous")
+}
+
+func TestAnnotations(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	a := insertAnnotatedEvent(t, db, "aaa111", SourcePostCommit)
+	b := insertAnnotatedEvent(t, db, "bbb222", SourcePostCommit)
+
+	added, err := AddTag(db, a.ID, "incident", now)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = AddTag(db, a.ID, "incident", now)
+	require.NoError(t, err)
+	require.False(t, added)
+	_, err = AddTag(db, a.ID, "billable", now)
+	require.NoError(t, err)
+	_, err = AddTag(db, b.ID, "billable", now)
+	require.NoError(t, err)
+	require.NoError(t, SetNote(db, b.ID, "Paged at 2am", now))
+
+	annotations, err := ListAnnotations(db, []int64{a.ID, b.ID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"billable", "incident"}, annotations[a.ID].Tags)
+	require.Equal(t, "Paged at 2am", annotations[b.ID].Note)
+
+	tags, err := ListTags(db)
+	require.NoError(t, err)
+	require.Equal(t, []TagCount{{Tag: "billable", Events: 2}, {Tag: "incident", Events: 1}}, tags)
+
+	tag := "incident"
+	events, err := ListEvents(db, EventFilter{Tag: &tag})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, a.ID, events[0].ID)
+
+	removed, err := RemoveTag(db, a.ID, "incident")
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.NoError(t, SetNote(db, b.ID, "", now))
+
+	annotations, err = ListAnnotations(db, []int64{a.ID, b.ID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"billable"}, annotations[a.ID].Tags)
+	require.Empty(t, annotations[b.ID].Note)
+}
@@ -11,4 +11,15 @@ type RepoEvent struct {
 	Timestamp time.Time
 	Status    Status
 	Source    Source
+
+	// Checkout context (post-checkout only)
+	PreviousCommit string
+	PreviousBranch string
+	BranchSwitch   bool
+
+	// Commit that replaced this one via amend or rebase (empty if current)
+	SupersededBy string
+
+	// Hostname of the device an imported event was recorded on (empty if local)
+	Device string
 }
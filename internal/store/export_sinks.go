@@ -0,0 +1,186 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// SinkExportedEvents returns which of the given events an export sink has
+// already received while they stayed pending.
+func SinkExportedEvents(db *sql.DB, sink string, ids []int64) (map[int64]bool, error) {
+	done := make(map[int64]bool)
+	for start := 0; start < len(ids); start += metadataBatchSize {
+		batch := ids[start:min(start+metadataBatchSize, len(ids))]
+
+		args := []any{sink}
+		for _, id := range batch {
+			args = append(args, id)
+		}
+		rows, err := db.Query(`SELECT event_id FROM export_sink_events WHERE sink = ? AND event_id IN (`+
+			strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")+`)`, args...)
+		if err != nil {
+			log.Error("store: list export sink events failed: %v (sink=%s)", err, sink)
+			return nil, err
+		}
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				closeRows(rows)
+				return nil, err
+			}
+			done[id] = true
+		}
+		err = rows.Err()
+		closeRows(rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return done, nil
+}
+
+// MarkSinkExported records that an export sink received the given events.
+func MarkSinkExported(db *sql.DB, sink string, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	exportedAt := at.UTC().Format(time.RFC3339)
+	for _, id := range ids {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO export_sink_events (sink, event_id, exported_at) VALUES (?, ?, ?)`,
+			sink, id, exportedAt); err != nil {
+			log.Error("store: mark export sink events failed: %v (sink=%s)", err, sink)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ClearSinkState forgets which sinks received the given events, once they
+// are exported or no longer need to be.
+func ClearSinkState(db *sql.DB, ids []int64) error {
+	for start := 0; start < len(ids); start += metadataBatchSize {
+		batch := ids[start:min(start+metadataBatchSize, len(ids))]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		if _, err := db.Exec(`DELETE FROM export_sink_events WHERE event_id IN (`+
+			strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")+`)`, args...); err != nil {
+			log.Error("store: clear export sink events failed: %v (count=%d)", err, len(ids))
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportRow is one row of an export snapshot. Key identifies the row the
+// way the CSV export deduplicates it, so writing a row again replaces it.
+type ExportRow struct {
+	Key    string
+	Values []any
+}
+
+// exportSnapshotTable holds the rows of an export snapshot database.
+const exportSnapshotTable = "events"
+
+// WriteExportSnapshot upserts rows into the standalone SQLite database at
+// path, creating it if needed, and deletes the rows keyed in drop. The
+// events table has a row_key primary key followed by the given columns;
+// columns missing from an older snapshot are added, and values keep the
+// type they are written with so numbers can be summed.
+func WriteExportSnapshot(path string, columns []string, rows []ExportRow, drop []string) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("open snapshot: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	setDBPermissions(path)
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = `"` + strings.ReplaceAll(c, `"`, `""`) + `"`
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + exportSnapshotTable +
+		` (row_key TEXT PRIMARY KEY, ` + strings.Join(quoted, ", ") + `)`); err != nil {
+		return fmt.Errorf("create snapshot table: %w", err)
+	}
+	if err := addSnapshotColumns(db, columns, quoted); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, key := range drop {
+		if _, err := tx.Exec(`DELETE FROM `+exportSnapshotTable+` WHERE row_key = ?`, key); err != nil {
+			return fmt.Errorf("delete snapshot row: %w", err)
+		}
+	}
+
+	insert, err := tx.Prepare(`INSERT OR REPLACE INTO ` + exportSnapshotTable +
+		` (row_key, ` + strings.Join(quoted, ", ") + `) VALUES (?` + strings.Repeat(", ?", len(columns)) + `)`)
+	if err != nil {
+		return fmt.Errorf("prepare snapshot insert: %w", err)
+	}
+	defer func() { _ = insert.Close() }()
+
+	for _, row := range rows {
+		if len(row.Values) != len(columns) {
+			return fmt.Errorf("snapshot row %s has %d values, expected %d", row.Key, len(row.Values), len(columns))
+		}
+		if _, err := insert.Exec(append([]any{row.Key}, row.Values...)...); err != nil {
+			return fmt.Errorf("write snapshot row %s: %w", row.Key, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// addSnapshotColumns adds the columns an existing snapshot lacks, such as
+// the annotation columns after export_annotations is turned on.
+func addSnapshotColumns(db *sql.DB, columns, quoted []string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('` + exportSnapshotTable + `')`)
+	if err != nil {
+		return fmt.Errorf("read snapshot columns: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			closeRows(rows)
+			return err
+		}
+		existing[name] = true
+	}
+	err = rows.Err()
+	closeRows(rows)
+	if err != nil {
+		return err
+	}
+
+	for i, c := range columns {
+		if existing[c] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + exportSnapshotTable + ` ADD COLUMN ` + quoted[i]); err != nil {
+			return fmt.Errorf("add snapshot column %s: %w", c, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinkState(t *testing.T) {
+	db := newTestDB(t)
+	a := insertAnnotatedEvent(t, db, "aaaa1111", SourcePostCommit)
+	b := insertAnnotatedEvent(t, db, "bbbb2222", SourcePostCommit)
+	at := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	done, err := SinkExportedEvents(db, "jsonl:/out.jsonl", []int64{a.ID, b.ID})
+	require.NoError(t, err)
+	require.Empty(t, done)
+
+	require.NoError(t, MarkSinkExported(db, "jsonl:/out.jsonl", []int64{a.ID}, at))
+	// Marking twice is harmless
+	require.NoError(t, MarkSinkExported(db, "jsonl:/out.jsonl", []int64{a.ID}, at))
+	require.NoError(t, MarkSinkExported(db, "dir:/out", []int64{b.ID}, at))
+
+	done, err = SinkExportedEvents(db, "jsonl:/out.jsonl", []int64{a.ID, b.ID})
+	require.NoError(t, err)
+	require.Equal(t, map[int64]bool{a.ID: true}, done)
+
+	require.NoError(t, ClearSinkState(db, []int64{a.ID, b.ID}))
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM export_sink_events`).Scan(&n))
+	require.Zero(t, n)
+}
+
+func TestWriteExportSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commits.db")
+
+	require.NoError(t, WriteExportSnapshot(path, []string{"commit_hash", "insertions"}, []ExportRow{
+		{Key: "repo:aaa", Values: []any{"aaa", 3}},
+		{Key: "repo:bbb", Values: []any{"bbb", 5}},
+	}, nil))
+
+	// Rows are replaced by key, dropped keys are deleted and new columns added
+	require.NoError(t, WriteExportSnapshot(path, []string{"commit_hash", "insertions", "note"}, []ExportRow{
+		{Key: "repo:aaa", Values: []any{"aaa", 4, "reviewed"}},
+	}, []string{"repo:bbb"}))
+
+	snapshot, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer func() { _ = snapshot.Close() }()
+
+	var total, count int
+	require.NoError(t, snapshot.QueryRow(`SELECT SUM(insertions), COUNT(*) FROM events`).Scan(&total, &count))
+	require.Equal(t, 4, total)
+	require.Equal(t, 1, count)
+
+	var note string
+	require.NoError(t, snapshot.QueryRow(`SELECT note FROM events WHERE row_key = 'repo:aaa'`).Scan(&note))
+	require.Equal(t, "reviewed", note)
+
+	err = WriteExportSnapshot(path, []string{"commit_hash"}, []ExportRow{{Key: "x", Values: []any{"a", "b"}}}, nil)
+	require.ErrorContains(t, err, "has 2 values, expected 1")
+}
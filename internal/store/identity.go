@@ -0,0 +1,81 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// RepoIdentity is how a clone is identified when repo_identity is not
+// remote: the id it is recorded under and the id its remote URL gives.
+type RepoIdentity struct {
+	RepoPath   string
+	RepoID     string
+	LegacyID   string
+	Strategy   string
+	RootCommit string
+	RemoteURL  string
+}
+
+// GetRepoIdentity returns the identity stored for a clone. The second
+// result is false when there is none.
+func GetRepoIdentity(db *sql.DB, repoPath string) (RepoIdentity, bool, error) {
+	var (
+		ident              RepoIdentity
+		rootCommit, remote sql.NullString
+	)
+	err := db.QueryRow(`
+		SELECT repo_path, repo_id, legacy_id, strategy, root_commit, remote_url
+		FROM repo_identities
+		WHERE repo_path = ?
+	`, repoPath).Scan(&ident.RepoPath, &ident.RepoID, &ident.LegacyID, &ident.Strategy, &rootCommit, &remote)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RepoIdentity{}, false, nil
+	}
+	if err != nil {
+		return RepoIdentity{}, false, err
+	}
+	ident.RootCommit = rootCommit.String
+	ident.RemoteURL = remote.String
+	return ident, true, nil
+}
+
+// SaveRepoIdentity stores or replaces the identity of a clone.
+func SaveRepoIdentity(db *sql.DB, ident RepoIdentity, now time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO repo_identities (repo_path, repo_id, legacy_id, strategy, root_commit, remote_url, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(repo_path) DO UPDATE SET
+			repo_id = excluded.repo_id,
+			legacy_id = excluded.legacy_id,
+			strategy = excluded.strategy,
+			root_commit = excluded.root_commit,
+			remote_url = excluded.remote_url,
+			updated_at = excluded.updated_at
+	`, ident.RepoPath, ident.RepoID, ident.LegacyID, ident.Strategy,
+		nullString(ident.RootCommit), nullString(ident.RemoteURL), now.UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteRepoIdentity forgets the identity of a clone, so its events are
+// recorded under the id its remote URL gives again.
+func DeleteRepoIdentity(db *sql.DB, repoPath string) error {
+	_, err := db.Exec(`DELETE FROM repo_identities WHERE repo_path = ?`, repoPath)
+	return err
+}
+
+// MappedRepoID returns the id a clone is recorded under for an id derived
+// from its remote URL, or legacyID when the clone has no stored identity.
+func MappedRepoID(db *sql.DB, repoPath, legacyID string) (string, error) {
+	var repoID string
+	err := db.QueryRow(`
+		SELECT repo_id FROM repo_identities WHERE repo_path = ? AND legacy_id = ?
+	`, repoPath, legacyID).Scan(&repoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return legacyID, nil
+	}
+	if err != nil {
+		return legacyID, err
+	}
+	return repoID, nil
+}
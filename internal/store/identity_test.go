@@ -0,0 +1,48 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoIdentity(t *testing.T) {
+	s, _ := newFileStore(t, "identity.db")
+	db := s.DB()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	repoID, err := MappedRepoID(db, "/src/api", "github.com/acme/api")
+	require.NoError(t, err)
+	require.Equal(t, "github.com/acme/api", repoID)
+
+	require.NoError(t, SaveRepoIdentity(db, RepoIdentity{
+		RepoPath:   "/src/api",
+		RepoID:     "root:4b825dc",
+		LegacyID:   "github.com/acme/api",
+		Strategy:   "root-commit",
+		RootCommit: "4b825dc",
+		RemoteURL:  "git@github.com:acme/api.git",
+	}, now))
+
+	repoID, err = MappedRepoID(db, "/src/api", "github.com/acme/api")
+	require.NoError(t, err)
+	require.Equal(t, "root:4b825dc", repoID)
+
+	// Only the legacy id the identity was stored for is mapped
+	repoID, err = MappedRepoID(db, "/src/api", "gitlab.com/acme/api")
+	require.NoError(t, err)
+	require.Equal(t, "gitlab.com/acme/api", repoID)
+
+	// Registered clones report the remote kept with their identity
+	require.NoError(t, s.AddRepo("/src/api"))
+	repos, err := s.ListRepos()
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	require.Equal(t, "git@github.com:acme/api.git", repos[0].RemoteURL)
+
+	require.NoError(t, DeleteRepoIdentity(db, "/src/api"))
+	_, found, err := GetRepoIdentity(db, "/src/api")
+	require.NoError(t, err)
+	require.False(t, found)
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+// ImportedEvent is an event read from a CSV export, with the commit metadata
+// and annotations its row carried.
+type ImportedEvent struct {
+	Event       RepoEvent
+	Metadata    git.CommitMetadata
+	Annotations Annotations
+}
+
+// ImportEvents inserts events from another device's export with the imported
+// status, so they are never exported again. Events already in the database,
+// including ones recorded here, are left untouched. Row metadata is stored as
+// a snapshot because the commits usually don't exist in a local clone.
+// Returns the number of events inserted.
+func ImportEvents(db *sql.DB, events []ImportedEvent) (int, error) {
+	imported := make([]ImportedEvent, len(events))
+	for i, ie := range events {
+		ie.Event.Status = StatusImported
+		imported[i] = ie
+	}
+	return RestoreEvents(db, imported)
+}
+
+// RestoreEvents inserts events read back from an export, keeping the status
+// and device of each. Like ImportEvents it never touches events already in
+// the database. Returns the number of events inserted.
+func RestoreEvents(db *sql.DB, events []ImportedEvent) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	inserted := 0
+	for _, ie := range events {
+		e := ie.Event
+		result, err := tx.Exec(
+			`INSERT INTO repo_events
+			 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id, device)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(repo_id, commit_hash, source_id) DO NOTHING`,
+			e.RepoID,
+			e.RepoPath,
+			e.Commit,
+			e.Branch,
+			e.Timestamp.UTC().Format(time.RFC3339),
+			int(e.Status),
+			int(e.Source),
+			nullString(e.Device),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("insert %s %.7s: %w", e.RepoID, e.Commit, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			continue
+		}
+		inserted++
+
+		if ie.Metadata.AuthoredAt != "" {
+			if err := saveCommitMetadata(tx, e.RepoID, e.Commit, ie.Metadata); err != nil {
+				return 0, fmt.Errorf("save metadata for %.7s: %w", e.Commit, err)
+			}
+		}
+
+		if !ie.Annotations.IsEmpty() {
+			if err := saveAnnotations(tx, result, e, ie.Annotations); err != nil {
+				return 0, fmt.Errorf("save annotations for %.7s: %w", e.Commit, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// saveAnnotations stores the tags and note of a just-inserted event.
+func saveAnnotations(tx execer, result sql.Result, e RepoEvent, a Annotations) error {
+	eventID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := setNote(tx, eventID, a.Note, e.Timestamp); err != nil {
+		return err
+	}
+	for _, tag := range a.Tags {
+		if _, err := addTag(tx, eventID, tag, e.Timestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountMissingEvents returns how many of the given events have no row in the
+// database with the same repository, commit and source. It verifies that an
+// import or rebuild left every event it read in place.
+func CountMissingEvents(db *sql.DB, events []ImportedEvent) (int, error) {
+	stmt, err := db.Prepare(`SELECT EXISTS (
+		SELECT 1 FROM repo_events WHERE repo_id = ? AND commit_hash = ? AND source_id = ?
+	)`)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	missing := 0
+	for _, ie := range events {
+		var exists bool
+		if err := stmt.QueryRow(ie.Event.RepoID, ie.Event.Commit, int(ie.Event.Source)).Scan(&exists); err != nil {
+			return 0, err
+		}
+		if !exists {
+			missing++
+		}
+	}
+	return missing, nil
+}
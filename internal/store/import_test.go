@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func TestImportEvents(t *testing.T) {
+	s, _ := newFileStore(t, "store.db")
+	insertMaintenanceEvent(t, s, "aaa111")
+
+	at := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	imported := func(commit string) ImportedEvent {
+		return ImportedEvent{
+			Event: RepoEvent{
+				RepoID:    "github.com/user/repo",
+				Commit:    commit,
+				Branch:    "main",
+				Timestamp: at,
+				Source:    SourcePostCommit,
+				Device:    "work-laptop",
+			},
+			Metadata: git.CommitMetadata{
+				AuthoredAt: at.Format(time.RFC3339),
+				AuthorName: "Dev",
+				Subject:    "Fix parser",
+			},
+		}
+	}
+
+	// The local event is a duplicate and stays as recorded
+	n, err := ImportEvents(s.DB(), []ImportedEvent{imported("aaa111"), imported("bbb222")})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	events, err := ListEvents(s.DB(), EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		switch e.Commit {
+		case "aaa111":
+			require.Equal(t, StatusPending, e.Status)
+			require.Empty(t, e.Device)
+		case "bbb222":
+			require.Equal(t, StatusImported, e.Status)
+			require.Equal(t, "work-laptop", e.Device)
+		}
+	}
+
+	snapshots, err := ListCommitMetadata(s.DB(), events)
+	require.NoError(t, err)
+	require.Equal(t, "Fix parser", snapshots["github.com/user/repo:bbb222"].Subject)
+
+	missing, err := CountMissingEvents(s.DB(), []ImportedEvent{imported("bbb222"), imported("zzz999")})
+	require.NoError(t, err)
+	require.Equal(t, 1, missing)
+
+	// Importing the same export again adds nothing
+	n, err = ImportEvents(s.DB(), []ImportedEvent{imported("bbb222")})
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
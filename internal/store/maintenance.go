@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/footprint-tools/cli/internal/store/migrations"
+)
+
+// Backup copies the database to destPath with SQLite's online backup API.
+// Unlike copying store.db by hand, the copy is consistent even while hooks
+// write to the database and the WAL holds uncheckpointed pages.
+func (s *Store) Backup(destPath string) error {
+	dest, err := sql.Open("sqlite3", destPath)
+	if err != nil {
+		return fmt.Errorf("open backup file: %w", err)
+	}
+	defer func() { _ = dest.Close() }()
+
+	if err := copyDatabase(s.db, dest); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	setDBPermissions(destPath)
+	return nil
+}
+
+// RestoreFrom replaces the database contents with the backup at srcPath and
+// migrates it to the current schema. Backups written by a newer fp, or files
+// that are not fp databases, are refused before anything is changed.
+func (s *Store) RestoreFrom(srcPath string) error {
+	version, err := BackupVersion(srcPath)
+	if err != nil {
+		return err
+	}
+	latest, err := migrations.LatestVersion()
+	if err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("backup has schema version %d but this fp supports up to %d: update fp first", version, latest)
+	}
+
+	src, err := sql.Open("sqlite3", "file:"+srcPath+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	if err := copyDatabase(src, s.db); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+
+	if err := migrations.Run(s.db); err != nil {
+		return fmt.Errorf("migrate restored database: %w", err)
+	}
+	return nil
+}
+
+// Downgrade reverts the schema to target so an older fp can open the
+// database. When the store has a path, the database is first backed up next
+// to it as <path>.before-downgrade, because down migrations drop data.
+func (s *Store) Downgrade(target int) error {
+	if s.path != "" {
+		backupPath := s.path + ".before-downgrade"
+		if err := os.Remove(backupPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove old %s: %w", backupPath, err)
+		}
+		if err := s.Backup(backupPath); err != nil {
+			return fmt.Errorf("save database before downgrading: %w", err)
+		}
+	}
+	return migrations.Downgrade(s.db, target)
+}
+
+// BackupVersion returns the schema version of the fp database at path,
+// without modifying it.
+func BackupVersion(path string) (int, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var tables int
+	err = db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('schema_migrations', 'repo_events')`,
+	).Scan(&tables)
+	if err != nil {
+		return 0, fmt.Errorf("read %s: %w", path, err)
+	}
+	if tables != 2 {
+		return 0, fmt.Errorf("%s is not an fp database", path)
+	}
+
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// CheckIntegrity runs SQLite's integrity and foreign key checks.
+// Returns one line per problem found; an empty result means the database is healthy.
+func (s *Store) CheckIntegrity() ([]string, error) {
+	var problems []string
+
+	rows, err := s.db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("integrity check: %w", err)
+	}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			closeRows(rows)
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	closeRows(rows)
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("foreign key check: %w", err)
+	}
+	defer closeRows(rows)
+	for rows.Next() {
+		var (
+			table, parent string
+			rowID         sql.NullInt64
+			fkID          int
+		)
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, err
+		}
+		problems = append(problems, fmt.Sprintf("%s row %d references a missing %s row", table, rowID.Int64, parent))
+	}
+
+	return problems, rows.Err()
+}
+
+// copyDatabase copies every page of src's main database into dest.
+func copyDatabase(src, dest *sql.DB) error {
+	ctx := context.Background()
+
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = srcConn.Close() }()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = destConn.Close() }()
+
+	return destConn.Raw(func(destDriver any) error {
+		return srcConn.Raw(func(srcDriver any) error {
+			destSQLite, ok := destDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection %T", destDriver)
+			}
+			srcSQLite, ok := srcDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection %T", srcDriver)
+			}
+
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+			if _, err := backup.Step(-1); err != nil {
+				_ = backup.Finish()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store/migrations"
+)
+
+func newFileStore(t *testing.T, name string) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	s, err := New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s, path
+}
+
+func insertMaintenanceEvent(t *testing.T, s *Store, commit string) {
+	t.Helper()
+	require.NoError(t, InsertEvent(s.DB(), RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/repo",
+		Commit:    commit,
+		Branch:    "main",
+		Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    SourcePostCommit,
+	}))
+}
+
+func eventCommits(t *testing.T, s *Store) []string {
+	t.Helper()
+	events, err := ListEvents(s.DB(), EventFilter{})
+	require.NoError(t, err)
+	var commits []string
+	for _, e := range events {
+		commits = append(commits, e.Commit)
+	}
+	return commits
+}
+
+func TestStore_BackupAndRestore(t *testing.T) {
+	s, _ := newFileStore(t, "store.db")
+	insertMaintenanceEvent(t, s, "aaa111")
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, s.Backup(backupPath))
+
+	latest, err := migrations.LatestVersion()
+	require.NoError(t, err)
+	version, err := BackupVersion(backupPath)
+	require.NoError(t, err)
+	require.Equal(t, latest, version)
+
+	// Changes after the backup are undone by restoring it
+	insertMaintenanceEvent(t, s, "bbb222")
+	require.NoError(t, s.RestoreFrom(backupPath))
+	require.Equal(t, []string{"aaa111"}, eventCommits(t, s))
+}
+
+func TestStore_RestoreRefusesNewerSchema(t *testing.T) {
+	s, _ := newFileStore(t, "store.db")
+	insertMaintenanceEvent(t, s, "aaa111")
+
+	newer, newerPath := newFileStore(t, "newer.db")
+	_, err := newer.DB().Exec(`INSERT INTO schema_migrations (version, description) VALUES (999, 'future')`)
+	require.NoError(t, err)
+	require.NoError(t, newer.Close())
+
+	err = s.RestoreFrom(newerPath)
+	require.ErrorContains(t, err, "schema version 999")
+	require.Equal(t, []string{"aaa111"}, eventCommits(t, s))
+}
+
+func TestBackupVersion_RejectsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	notDB := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(notDB, []byte("hello"), 0600))
+	_, err := BackupVersion(notDB)
+	require.Error(t, err)
+
+	other, otherPath := newFileStore(t, "other.db")
+	_, err = other.DB().Exec(`DROP TABLE repo_events`)
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	_, err = BackupVersion(otherPath)
+	require.ErrorContains(t, err, "not an fp database")
+}
+
+func TestStore_CheckIntegrity(t *testing.T) {
+	s, _ := newFileStore(t, "store.db")
+	insertMaintenanceEvent(t, s, "aaa111")
+
+	problems, err := s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	// A rewrite pair pointing at a missing event is reported
+	_, err = s.DB().Exec(`PRAGMA foreign_keys = OFF`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`INSERT INTO commit_rewrites (event_id, repo_id, old_commit, new_commit, rewrite_type, created_at)
+		VALUES (999, 'repo', 'a', 'b', 'amend', '2025-01-01T00:00:00Z')`)
+	require.NoError(t, err)
+
+	problems, err = s.CheckIntegrity()
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], "commit_rewrites")
+}
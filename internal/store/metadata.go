@@ -0,0 +1,142 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// metadataBatchSize bounds the number of commits per lookup query so large
+// exports stay under SQLite's host parameter limit.
+const metadataBatchSize = 500
+
+// SaveCommitMetadata stores a snapshot of a commit's git metadata.
+// Commit metadata never changes for a given hash, so an existing snapshot is kept.
+func SaveCommitMetadata(db *sql.DB, repoID, commit string, meta git.CommitMetadata) error {
+	return saveCommitMetadata(db, repoID, commit, meta)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// querier is an execer that can also read rows, such as *sql.Tx.
+type querier interface {
+	execer
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func saveCommitMetadata(db execer, repoID, commit string, meta git.CommitMetadata) error {
+	_, err := db.Exec(
+		`INSERT INTO commit_metadata
+		 (repo_id, commit_hash, author_name, author_email, authored_at,
+		  committer_name, committer_email, committed_at, subject, body,
+		  parent_commits, files_changed, insertions, deletions, captured_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(repo_id, commit_hash) DO NOTHING`,
+		repoID, commit,
+		nullString(meta.AuthorName), nullString(meta.AuthorEmail), nullString(meta.AuthoredAt),
+		nullString(meta.CommitterName), nullString(meta.CommitterEmail), nullString(meta.CommittedAt),
+		nullString(meta.Subject), nullString(meta.Body), nullString(meta.ParentCommits),
+		meta.FilesChanged, meta.Insertions, meta.Deletions,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		log.Error("store: save commit metadata failed: %v (repo=%s, commit=%.7s)", err, repoID, commit)
+	}
+	return err
+}
+
+// HasCommitMetadata reports whether a snapshot exists for a commit.
+func HasCommitMetadata(db *sql.DB, repoID, commit string) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM commit_metadata WHERE repo_id = ? AND commit_hash = ?`,
+		repoID, commit,
+	).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ListCommitMetadata returns the snapshots available for the given events,
+// keyed by repo_id:commit. Events without a snapshot are absent from the map.
+func ListCommitMetadata(db *sql.DB, events []RepoEvent) (map[string]git.CommitMetadata, error) {
+	out := make(map[string]git.CommitMetadata)
+
+	seen := make(map[string]bool)
+	var commits []string
+	for _, e := range events {
+		if e.Commit != "" && !seen[e.Commit] {
+			seen[e.Commit] = true
+			commits = append(commits, e.Commit)
+		}
+	}
+
+	for start := 0; start < len(commits); start += metadataBatchSize {
+		batch := commits[start:min(start+metadataBatchSize, len(commits))]
+		if err := listCommitMetadataBatch(db, batch, out); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func listCommitMetadataBatch(db *sql.DB, commits []string, out map[string]git.CommitMetadata) error {
+	args := make([]any, len(commits))
+	for i, c := range commits {
+		args[i] = c
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(commits)), ",")
+	query := fmt.Sprintf(`
+		SELECT repo_id, commit_hash, author_name, author_email, authored_at,
+		       committer_name, committer_email, committed_at, subject, body,
+		       parent_commits, files_changed, insertions, deletions
+		FROM commit_metadata
+		WHERE commit_hash IN (%s)
+	`, placeholders)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Error("store: list commit metadata query failed: %v", err)
+		return err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var (
+			repoID, commit                          string
+			authorName, authorEmail, authoredAt     sql.NullString
+			committerName, committerEmail, commitAt sql.NullString
+			subject, body, parents                  sql.NullString
+			meta                                    git.CommitMetadata
+		)
+		if err := rows.Scan(
+			&repoID, &commit, &authorName, &authorEmail, &authoredAt,
+			&committerName, &committerEmail, &commitAt, &subject, &body,
+			&parents, &meta.FilesChanged, &meta.Insertions, &meta.Deletions,
+		); err != nil {
+			return err
+		}
+		meta.AuthorName = authorName.String
+		meta.AuthorEmail = authorEmail.String
+		meta.AuthoredAt = authoredAt.String
+		meta.CommitterName = committerName.String
+		meta.CommitterEmail = committerEmail.String
+		meta.CommittedAt = commitAt.String
+		meta.Subject = subject.String
+		meta.Body = body.String
+		meta.ParentCommits = parents.String
+		out[repoID+":"+commit] = meta
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func TestSaveCommitMetadata_RoundTrip(t *testing.T) {
+	db := newTestDB(t)
+
+	meta := git.CommitMetadata{
+		AuthorName:     "Test User",
+		AuthorEmail:    "test@example.com",
+		AuthoredAt:     "2024-01-15T12:00:00Z",
+		CommitterName:  "Other User",
+		CommitterEmail: "other@example.com",
+		CommittedAt:    "2024-01-15T12:05:00Z",
+		Subject:        "Fix a bug",
+		Body:           "Longer explanation",
+		ParentCommits:  "aaa111 bbb222",
+		FilesChanged:   3,
+		Insertions:     10,
+		Deletions:      2,
+	}
+	require.NoError(t, SaveCommitMetadata(db, "github.com/user/repo", "abc123", meta))
+
+	exists, err := HasCommitMetadata(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = HasCommitMetadata(db, "github.com/user/other", "abc123")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	snapshots, err := ListCommitMetadata(db, []RepoEvent{
+		{RepoID: "github.com/user/repo", Commit: "abc123"},
+		{RepoID: "github.com/user/repo", Commit: "missing"},
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, meta, snapshots["github.com/user/repo:abc123"])
+}
+
+func TestSaveCommitMetadata_KeepsFirstSnapshot(t *testing.T) {
+	db := newTestDB(t)
+
+	require.NoError(t, SaveCommitMetadata(db, "repo", "abc123", git.CommitMetadata{Subject: "first"}))
+	require.NoError(t, SaveCommitMetadata(db, "repo", "abc123", git.CommitMetadata{Subject: "second"}))
+
+	snapshots, err := ListCommitMetadata(db, []RepoEvent{{RepoID: "repo", Commit: "abc123"}})
+	require.NoError(t, err)
+	require.Equal(t, "first", snapshots["repo:abc123"].Subject)
+}
+
+func TestListCommitMetadata_Empty(t *testing.T) {
+	db := newTestDB(t)
+
+	snapshots, err := ListCommitMetadata(db, nil)
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
@@ -15,11 +15,34 @@ import (
 //go:embed sql/*.sql
 var sqlFiles embed.FS
 
+const downSuffix = ".down.sql"
+
 // Migration represents a database migration.
+// Down holds the optional NN_description.down.sql that reverts it.
 type Migration struct {
 	Version     int
 	Description string
 	SQL         string
+	Down        string
+}
+
+// AppliedMigration is a row of schema_migrations.
+type AppliedMigration struct {
+	Version     int
+	Description string
+	AppliedAt   string
+}
+
+// NewerSchemaError is returned when the database was migrated by a newer fp
+// than the running binary. Writing to it could lose or corrupt data.
+type NewerSchemaError struct {
+	Current int
+	Latest  int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("database schema version %d is newer than this fp supports (%d): update fp, "+
+		"or run 'fp db downgrade %d' with the newer fp first", e.Current, e.Latest, e.Latest)
 }
 
 const createSchemaTable = `
@@ -37,12 +60,26 @@ func Load() ([]Migration, error) {
 	}
 
 	var migrations []Migration
+	downs := make(map[int]string)
 
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
 			continue
 		}
 
+		if strings.HasSuffix(entry.Name(), downSuffix) {
+			version, _, err := parseFilename(strings.TrimSuffix(entry.Name(), downSuffix) + ".sql")
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", entry.Name(), err)
+			}
+			content, err := sqlFiles.ReadFile(filepath.Join("sql", entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
+			}
+			downs[version] = string(content)
+			continue
+		}
+
 		version, description, err := parseFilename(entry.Name())
 		if err != nil {
 			return nil, fmt.Errorf("parse %s: %w", entry.Name(), err)
@@ -74,6 +111,15 @@ func Load() ([]Migration, error) {
 		seen[m.Version] = m.Description
 	}
 
+	for version := range downs {
+		if _, ok := seen[version]; !ok {
+			return nil, fmt.Errorf("down migration %02d has no matching up migration", version)
+		}
+	}
+	for i := range migrations {
+		migrations[i].Down = downs[migrations[i].Version]
+	}
+
 	return migrations, nil
 }
 
@@ -114,6 +160,10 @@ func Run(db *sql.DB) error {
 		return err
 	}
 
+	if latest := migrations[len(migrations)-1].Version; current > latest {
+		return &NewerSchemaError{Current: current, Latest: latest}
+	}
+
 	for _, m := range migrations {
 		if m.Version <= current {
 			continue
@@ -127,7 +177,36 @@ func Run(db *sql.DB) error {
 	return nil
 }
 
-func apply(db *sql.DB, m Migration) (retErr error) {
+func apply(db *sql.DB, m Migration) error {
+	return inTransaction(db, m.Version, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(m.SQL); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(
+			"INSERT INTO schema_migrations (version, description) VALUES (?, ?)",
+			m.Version, m.Description,
+		)
+		if err != nil {
+			return fmt.Errorf("record migration: %w", err)
+		}
+		return nil
+	})
+}
+
+// revertMigration runs a migration's down file and removes it from schema_migrations.
+func revertMigration(tx *sql.Tx, m Migration) error {
+	if _, err := tx.Exec(m.Down); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+		return fmt.Errorf("unrecord migration: %w", err)
+	}
+	return nil
+}
+
+func inTransaction(db *sql.DB, version int, fn func(*sql.Tx) error) (retErr error) {
 	tx, err := db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin: %w", err)
@@ -140,7 +219,7 @@ func apply(db *sql.DB, m Migration) (retErr error) {
 			if rbErr := tx.Rollback(); rbErr != nil {
 				// Rollback failure is critical - database may be in inconsistent state
 				log.Error("migrations: rollback failed for migration %d: %v (original error: %v)",
-					m.Version, rbErr, retErr)
+					version, rbErr, retErr)
 				// Return compound error so caller knows rollback also failed
 				if retErr != nil {
 					retErr = fmt.Errorf("%w (additionally, rollback failed: %v)", retErr, rbErr)
@@ -151,18 +230,10 @@ func apply(db *sql.DB, m Migration) (retErr error) {
 		}
 	}()
 
-	if _, err := tx.Exec(m.SQL); err != nil {
+	if err := fn(tx); err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(
-		"INSERT INTO schema_migrations (version, description) VALUES (?, ?)",
-		m.Version, m.Description,
-	)
-	if err != nil {
-		return fmt.Errorf("record migration: %w", err)
-	}
-
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit: %w", err)
 	}
@@ -208,3 +279,92 @@ func Pending(db *sql.DB) ([]Migration, error) {
 	}
 	return pending, nil
 }
+
+// LatestVersion returns the highest migration version embedded in this binary.
+func LatestVersion() (int, error) {
+	migrations, err := Load()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Applied returns the rows of schema_migrations in version order, including
+// versions this binary does not know about.
+func Applied(db *sql.DB) ([]AppliedMigration, error) {
+	if _, err := db.Exec(createSchemaTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT version, description, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var m AppliedMigration
+		if err := rows.Scan(&m.Version, &m.Description, &m.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied = append(applied, m)
+	}
+	return applied, rows.Err()
+}
+
+// Downgrade reverts applied migrations newer than target, newest first, using
+// their down files. They are reverted in a single transaction, so nothing is
+// changed unless every migration to revert has a down file and all of them
+// succeed.
+func Downgrade(db *sql.DB, target int) error {
+	if target < 0 {
+		return fmt.Errorf("invalid target version %d", target)
+	}
+
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	applied, err := Applied(db)
+	if err != nil {
+		return err
+	}
+
+	var revert []Migration
+	for i := len(applied) - 1; i >= 0; i-- {
+		a := applied[i]
+		if a.Version <= target {
+			break
+		}
+		m, ok := byVersion[a.Version]
+		if !ok {
+			return fmt.Errorf("migration %02d_%s is unknown to this fp: downgrade with the fp that applied it", a.Version, a.Description)
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %02d_%s has no down file, so the schema cannot go below version %d: "+
+				"restore a backup taken before it was applied", m.Version, m.Description, m.Version)
+		}
+		revert = append(revert, m)
+	}
+
+	if len(revert) == 0 {
+		return nil
+	}
+	return inTransaction(db, revert[0].Version, func(tx *sql.Tx) error {
+		for _, m := range revert {
+			if err := revertMigration(tx, m); err != nil {
+				return fmt.Errorf("revert %02d_%s: %w", m.Version, m.Description, err)
+			}
+		}
+		return nil
+	})
+}
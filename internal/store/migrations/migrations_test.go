@@ -2,6 +2,9 @@ package migrations_test
 
 import (
 	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -138,3 +141,165 @@ func TestRedundantColumnsRemoved(t *testing.T) {
 		}
 	}
 }
+
+func TestLatestVersion(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	latest, err := migrations.LatestVersion()
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+	current, _ := migrations.CurrentVersion(db)
+	if latest != current {
+		t.Errorf("expected latest %d to match applied version %d", latest, current)
+	}
+}
+
+func openMigrated(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = ?", table).Scan(&count)
+	if err != nil {
+		t.Fatalf("check %s: %v", table, err)
+	}
+	return count > 0
+}
+
+func TestLoadDownFiles(t *testing.T) {
+	all, err := migrations.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	for _, m := range all {
+		if m.Version == 1 && m.Down != "" {
+			t.Errorf("initial schema should not have a down file")
+		}
+		if m.Version == 11 && m.Down == "" {
+			t.Errorf("migration 11 should have a down file")
+		}
+	}
+}
+
+func TestRunRefusesNewerDatabase(t *testing.T) {
+	db := openMigrated(t)
+
+	if _, err := db.Exec("INSERT INTO schema_migrations (version, description) VALUES (999, 'future')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	err := migrations.Run(db)
+	var newer *migrations.NewerSchemaError
+	if !errors.As(err, &newer) {
+		t.Fatalf("expected NewerSchemaError, got %v", err)
+	}
+	if newer.Current != 999 {
+		t.Errorf("expected current 999, got %d", newer.Current)
+	}
+}
+
+func TestApplied(t *testing.T) {
+	db := openMigrated(t)
+
+	applied, err := migrations.Applied(db)
+	if err != nil {
+		t.Fatalf("applied: %v", err)
+	}
+	all, _ := migrations.Load()
+	if len(applied) != len(all) {
+		t.Fatalf("expected %d applied, got %d", len(all), len(applied))
+	}
+	if applied[0].Version != 1 || applied[0].AppliedAt == "" {
+		t.Errorf("unexpected first row: %+v", applied[0])
+	}
+}
+
+func TestDowngrade(t *testing.T) {
+	db := openMigrated(t)
+
+	if err := migrations.Downgrade(db, 8); err != nil {
+		t.Fatalf("downgrade: %v", err)
+	}
+
+	if v, _ := migrations.CurrentVersion(db); v != 8 {
+		t.Errorf("expected version 8, got %d", v)
+	}
+	for _, table := range []string{"push_refs", "commit_metadata", "event_search"} {
+		if tableExists(t, db, table) {
+			t.Errorf("table %s should have been dropped", table)
+		}
+	}
+
+	// Upgrading again restores the reverted migrations
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("run after downgrade: %v", err)
+	}
+	if !tableExists(t, db, "push_refs") {
+		t.Errorf("push_refs should be recreated")
+	}
+}
+
+func TestDowngradeIrreversible(t *testing.T) {
+	db := openMigrated(t)
+	before, _ := migrations.CurrentVersion(db)
+
+	// Migration 08 has no down file, so nothing may be reverted
+	err := migrations.Downgrade(db, 7)
+	if err == nil || !strings.Contains(err.Error(), "08_commit_rewrites has no down file") {
+		t.Fatalf("expected error for migration without down file, got %v", err)
+	}
+
+	if v, _ := migrations.CurrentVersion(db); v != before {
+		t.Errorf("version changed: %d -> %d", before, v)
+	}
+	if !tableExists(t, db, "event_search") {
+		t.Errorf("event_search should be untouched")
+	}
+}
+
+func TestDowngradeFailureRevertsNothing(t *testing.T) {
+	db := openMigrated(t)
+	before, _ := migrations.CurrentVersion(db)
+
+	// Reverting 09 fails after every newer migration was reverted
+	if _, err := db.Exec(`CREATE TRIGGER keep_09 BEFORE DELETE ON schema_migrations
+		WHEN old.version = 9 BEGIN SELECT RAISE(ABORT, 'keep 09'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	err := migrations.Downgrade(db, 8)
+	if err == nil || !strings.Contains(err.Error(), "09_push_refs") {
+		t.Fatalf("expected error reverting 09_push_refs, got %v", err)
+	}
+
+	if v, _ := migrations.CurrentVersion(db); v != before {
+		t.Errorf("version changed: %d -> %d", before, v)
+	}
+	for _, table := range []string{"push_refs", "commit_metadata", "event_search"} {
+		if !tableExists(t, db, table) {
+			t.Errorf("table %s should be untouched", table)
+		}
+	}
+}
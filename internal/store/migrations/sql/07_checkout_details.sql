@@ -0,0 +1,7 @@
+-- Checkout context captured from post-checkout arguments (<prev> <new> <flag>)
+-- previous_commit: HEAD before the checkout
+-- previous_branch: branch that was checked out before (branch switches only)
+-- branch_switch: 1 for branch checkouts, 0 for file checkouts and other sources
+ALTER TABLE repo_events ADD COLUMN previous_commit TEXT;
+ALTER TABLE repo_events ADD COLUMN previous_branch TEXT;
+ALTER TABLE repo_events ADD COLUMN branch_switch INTEGER NOT NULL DEFAULT 0;
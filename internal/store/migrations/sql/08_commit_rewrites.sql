@@ -0,0 +1,19 @@
+-- Rewrite mappings read from post-rewrite stdin (<old-sha> <new-sha> per line)
+-- event_id links each pair to the post-rewrite event that reported it
+-- rewrite_type is the post-rewrite argument: amend or rebase
+CREATE TABLE IF NOT EXISTS commit_rewrites (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER REFERENCES repo_events(id) ON DELETE CASCADE,
+    repo_id TEXT NOT NULL,
+    old_commit TEXT NOT NULL,
+    new_commit TEXT NOT NULL,
+    rewrite_type TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    UNIQUE(repo_id, old_commit, new_commit)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_rewrites_event_id ON commit_rewrites(event_id);
+CREATE INDEX IF NOT EXISTS idx_commit_rewrites_old_commit ON commit_rewrites(repo_id, old_commit);
+
+-- Commit that replaced this event's commit (NULL while the commit is current)
+ALTER TABLE repo_events ADD COLUMN superseded_by TEXT;
@@ -0,0 +1,21 @@
+-- Ref updates read from pre-push (<local ref> <local sha> <remote ref> <remote sha> per line)
+-- event_id links each ref update to the pre-push event that reported it
+-- remote_name/remote_url are the pre-push arguments
+-- commit_count is the number of commits the update sends (git rev-list --count)
+CREATE TABLE IF NOT EXISTS push_refs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER REFERENCES repo_events(id) ON DELETE CASCADE,
+    repo_id TEXT NOT NULL,
+    remote_name TEXT NOT NULL,
+    remote_url TEXT,
+    local_ref TEXT NOT NULL,
+    local_sha TEXT NOT NULL,
+    remote_ref TEXT NOT NULL,
+    remote_sha TEXT NOT NULL,
+    commit_count INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL,
+    UNIQUE(event_id, remote_name, local_ref, remote_ref, local_sha)
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_refs_event_id ON push_refs(event_id);
+CREATE INDEX IF NOT EXISTS idx_push_refs_repo_id ON push_refs(repo_id);
@@ -0,0 +1,23 @@
+-- Snapshot of git commit metadata taken at record/backfill time (opt-in via
+-- snapshot_metadata). Export and enriched views read it before asking git,
+-- so rows stay complete after a clone is deleted, moved or rebased away.
+CREATE TABLE IF NOT EXISTS commit_metadata (
+    repo_id TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    author_name TEXT,
+    author_email TEXT,
+    authored_at TEXT,
+    committer_name TEXT,
+    committer_email TEXT,
+    committed_at TEXT,
+    subject TEXT,
+    body TEXT,
+    parent_commits TEXT,
+    files_changed INTEGER NOT NULL DEFAULT 0,
+    insertions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    captured_at TEXT NOT NULL,
+    PRIMARY KEY (repo_id, commit_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_metadata_commit ON commit_metadata(commit_hash);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS event_search_delete;
+DROP TABLE IF EXISTS event_search;
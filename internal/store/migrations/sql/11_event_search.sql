@@ -0,0 +1,19 @@
+-- Full-text index over commit subjects and bodies, branch and repo ID.
+-- docid is the repo_events id. This is FTS4 rather than FTS5: go-sqlite3 only
+-- compiles FTS5 with the sqlite_fts5 build tag, and 'go install', 'make' and
+-- CI build without tags, so an FTS5 table would fail to migrate there. The
+-- matchinfo()-based ranking in store/search.go depends on FTS4 as well.
+-- Rows are filled lazily by 'fp search' and the activity view, so recording
+-- never waits on git for commit messages.
+CREATE VIRTUAL TABLE IF NOT EXISTS event_search USING fts4(
+    subject,
+    body,
+    branch,
+    repo_id,
+    tokenize=unicode61 "remove_diacritics=1"
+);
+
+CREATE TRIGGER IF NOT EXISTS event_search_delete AFTER DELETE ON repo_events
+BEGIN
+    DELETE FROM event_search WHERE docid = old.id;
+END;
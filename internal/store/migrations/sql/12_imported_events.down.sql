@@ -0,0 +1,3 @@
+DELETE FROM repo_events WHERE status_id = 4;
+ALTER TABLE repo_events DROP COLUMN device;
+DELETE FROM event_status WHERE id = 4;
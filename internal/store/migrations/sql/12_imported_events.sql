@@ -0,0 +1,6 @@
+-- Events imported from another device's CSV export ('fp import').
+-- They get their own status so the export never writes them back out, and
+-- device records the hostname from the CSV (NULL for events recorded here).
+INSERT OR IGNORE INTO event_status (id, name) VALUES (4, 'imported');
+
+ALTER TABLE repo_events ADD COLUMN device TEXT;
@@ -0,0 +1,16 @@
+-- User-authored annotations on recorded events: one free-form note and any
+-- number of tags (e.g. incident, billable, review) per event
+CREATE TABLE IF NOT EXISTS event_notes (
+    event_id INTEGER PRIMARY KEY REFERENCES repo_events(id) ON DELETE CASCADE,
+    note TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS event_tags (
+    event_id INTEGER NOT NULL REFERENCES repo_events(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (event_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_tags_tag ON event_tags(tag);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_repo_meta_group;
+DROP TABLE IF EXISTS repo_meta;
@@ -0,0 +1,10 @@
+-- Per-repository settings chosen by the user: a display alias shown instead
+-- of the repo id, and the group (e.g. a product) the repository belongs to
+CREATE TABLE IF NOT EXISTS repo_meta (
+    repo_id TEXT PRIMARY KEY,
+    alias TEXT,
+    group_name TEXT,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_repo_meta_group ON repo_meta(group_name);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_repo_identities_repo_id;
+DROP TABLE IF EXISTS repo_identities;
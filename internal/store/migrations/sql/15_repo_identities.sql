@@ -0,0 +1,15 @@
+-- Ids of clones recorded with repo_identity=root-commit or path, keyed by
+-- clone path. legacy_id is the id the remote URL gives: events recorded
+-- under it are moved to repo_id, and later ones are mapped before they are
+-- stored. The remote URL is kept here since it is no longer part of the id.
+CREATE TABLE IF NOT EXISTS repo_identities (
+    repo_path TEXT PRIMARY KEY,
+    repo_id TEXT NOT NULL,
+    legacy_id TEXT NOT NULL,
+    strategy TEXT NOT NULL,
+    root_commit TEXT,
+    remote_url TEXT,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_repo_identities_repo_id ON repo_identities(repo_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_export_sink_events_event;
+DROP TABLE IF EXISTS export_sink_events;
@@ -0,0 +1,11 @@
+-- Events each configured export sink has received while the event is still
+-- pending because another sink failed. Rows are removed once the event is
+-- marked exported, so the table only holds partial exports.
+CREATE TABLE IF NOT EXISTS export_sink_events (
+    sink TEXT NOT NULL,
+    event_id INTEGER NOT NULL REFERENCES repo_events(id) ON DELETE CASCADE,
+    exported_at TEXT NOT NULL,
+    PRIMARY KEY (sink, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_sink_events_event ON export_sink_events(event_id);
@@ -9,3 +9,9 @@ import (
 func DBPath() string {
 	return filepath.Join(paths.AppDataDir(), "store.db")
 }
+
+// SpoolPath returns the file holding events that could not be written to
+// the database, kept next to it until they are replayed.
+func SpoolPath() string {
+	return filepath.Join(paths.AppDataDir(), "spool.jsonl")
+}
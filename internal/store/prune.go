@@ -0,0 +1,107 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PruneFilter selects events to delete: everything recorded before Before,
+// optionally limited to the given statuses.
+type PruneFilter struct {
+	Before   time.Time
+	Statuses []Status
+}
+
+func (f PruneFilter) where() (string, []any) {
+	clauses := []string{"timestamp < ?"}
+	args := []any{f.Before.UTC().Format(time.RFC3339)}
+
+	if len(f.Statuses) > 0 {
+		clauses = append(clauses, fmt.Sprintf("status_id IN (%s)",
+			strings.TrimSuffix(strings.Repeat("?,", len(f.Statuses)), ",")))
+		for _, s := range f.Statuses {
+			args = append(args, int(s))
+		}
+	}
+
+	return strings.Join(clauses, " AND "), args
+}
+
+// CountPrunable returns how many events Prune would delete.
+func (s *Store) CountPrunable(filter PruneFilter) (int64, error) {
+	where, args := filter.where()
+
+	var count int64
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM repo_events WHERE `+where, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Prune deletes the events matching the filter together with their rewrite
+// pairs, pushed refs, notes, tags, export sink state and search entries.
+// Metadata snapshots are removed once no event refers to their commit.
+// Returns the number of events deleted.
+func (s *Store) Prune(filter PruneFilter) (int64, error) {
+	where, args := filter.where()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Dependent rows cascade when foreign keys are on; delete them
+	// explicitly so connections without the pragma stay consistent
+	selected := `SELECT id FROM repo_events WHERE ` + where
+	for _, table := range []string{"commit_rewrites", "push_refs", "event_tags", "event_notes", "export_sink_events"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE event_id IN (`+selected+`)`, args...); err != nil {
+			return 0, fmt.Errorf("delete %s: %w", table, err)
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM repo_events WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("delete events: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM commit_metadata
+		WHERE NOT EXISTS (
+			SELECT 1 FROM repo_events e
+			WHERE e.repo_id = commit_metadata.repo_id AND e.commit_hash = commit_metadata.commit_hash
+		)`); err != nil {
+		return 0, fmt.Errorf("delete metadata snapshots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// Checkpoint folds the write-ahead log back into the database file.
+func (s *Store) Checkpoint() error {
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Compact checkpoints the write-ahead log and rebuilds the database file so
+// space freed by deletions is returned to the filesystem.
+func (s *Store) Compact() error {
+	if err := s.Checkpoint(); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func TestStore_Prune(t *testing.T) {
+	s := newTestStore(t)
+	db := s.DB()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	insert := func(commit string, age time.Duration, status Status, source Source) {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID:    "repo",
+			RepoPath:  "/r",
+			Commit:    commit,
+			Branch:    "main",
+			Timestamp: now.Add(-age),
+			Status:    status,
+			Source:    source,
+		}))
+	}
+
+	day := 24 * time.Hour
+	insert("old-exported", 400*day, StatusExported, SourcePostCommit)
+	insert("old-pending", 400*day, StatusPending, SourcePostCommit)
+	insert("new-exported", 10*day, StatusExported, SourcePostCommit)
+	insert("old-push", 400*day, StatusExported, SourcePrePush)
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	for _, e := range events {
+		require.NoError(t, IndexEvent(db, e, "subject "+e.Commit, ""))
+		if e.Commit == "old-push" {
+			require.NoError(t, RecordPushRefs(db, e, []PushRef{{RemoteName: "origin", LocalRef: "refs/heads/main", LocalSHA: "a", RemoteRef: "refs/heads/main", RemoteSHA: "b"}}))
+		}
+	}
+	require.NoError(t, SaveCommitMetadata(db, "repo", "old-exported", git.CommitMetadata{Subject: "gone"}))
+	require.NoError(t, SaveCommitMetadata(db, "repo", "new-exported", git.CommitMetadata{Subject: "kept"}))
+
+	filter := PruneFilter{Before: now.Add(-365 * day), Statuses: []Status{StatusExported}}
+
+	count, err := s.CountPrunable(filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	deleted, err := s.Prune(filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	var commits []string
+	for _, e := range events {
+		commits = append(commits, e.Commit)
+	}
+	require.ElementsMatch(t, []string{"old-pending", "new-exported"}, commits)
+
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM push_refs`).Scan(&n))
+	require.Zero(t, n)
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM event_search`).Scan(&n))
+	require.Equal(t, 2, n)
+
+	snapshots, err := ListCommitMetadata(db, []RepoEvent{{RepoID: "repo", Commit: "old-exported"}, {RepoID: "repo", Commit: "new-exported"}})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, "kept", snapshots["repo:new-exported"].Subject)
+
+	// Without a status filter everything old goes
+	deleted, err = s.Prune(PruneFilter{Before: now.Add(-365 * day)})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	require.NoError(t, s.Compact())
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// PushRef is a single ref update reported by the pre-push hook.
+type PushRef struct {
+	ID          int64
+	EventID     int64
+	RepoID      string
+	RemoteName  string
+	RemoteURL   string
+	LocalRef    string
+	LocalSHA    string
+	RemoteRef   string
+	RemoteSHA   string
+	CommitCount int
+	CreatedAt   time.Time
+}
+
+// RecordPushRefs stores the ref updates reported by a pre-push event.
+// The event must already be inserted.
+func RecordPushRefs(db *sql.DB, event RepoEvent, refs []PushRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := recordPushRefs(tx, event, refs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func recordPushRefs(tx querier, event RepoEvent, refs []PushRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var eventID int64
+	err := tx.QueryRow(
+		`SELECT id FROM repo_events WHERE repo_id = ? AND commit_hash = ? AND source_id = ?`,
+		event.RepoID, event.Commit, int(event.Source),
+	).Scan(&eventID)
+	if err != nil {
+		return fmt.Errorf("find push event: %w", err)
+	}
+
+	createdAt := event.Timestamp.UTC().Format(time.RFC3339)
+
+	for _, ref := range refs {
+		_, err := tx.Exec(
+			`INSERT INTO push_refs
+			 (event_id, repo_id, remote_name, remote_url, local_ref, local_sha,
+			  remote_ref, remote_sha, commit_count, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(event_id, remote_name, local_ref, remote_ref, local_sha)
+			 DO UPDATE SET
+				remote_url = excluded.remote_url,
+				remote_sha = excluded.remote_sha,
+				commit_count = excluded.commit_count,
+				created_at = excluded.created_at`,
+			eventID, event.RepoID, ref.RemoteName, nullString(ref.RemoteURL), ref.LocalRef, ref.LocalSHA,
+			ref.RemoteRef, ref.RemoteSHA, ref.CommitCount, createdAt,
+		)
+		if err != nil {
+			return fmt.Errorf("insert push ref %s: %w", ref.RemoteRef, err)
+		}
+	}
+
+	return nil
+}
+
+// ListPushRefsForEvents returns ref updates grouped by the pre-push event that reported them.
+func ListPushRefsForEvents(db *sql.DB, eventIDs []int64) (map[int64][]PushRef, error) {
+	out := make(map[int64][]PushRef)
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, len(eventIDs))
+	for i, id := range eventIDs {
+		args[i] = id
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(eventIDs)), ",")
+	query := fmt.Sprintf(`
+		SELECT id, event_id, repo_id, remote_name, remote_url, local_ref, local_sha,
+		       remote_ref, remote_sha, commit_count, created_at
+		FROM push_refs
+		WHERE event_id IN (%s)
+		ORDER BY id ASC
+	`, placeholders)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Error("store: list push refs query failed: %v", err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var (
+			ref       PushRef
+			eventID   sql.NullInt64
+			remoteURL sql.NullString
+			ts        string
+		)
+		if err := rows.Scan(
+			&ref.ID, &eventID, &ref.RepoID, &ref.RemoteName, &remoteURL, &ref.LocalRef, &ref.LocalSHA,
+			&ref.RemoteRef, &ref.RemoteSHA, &ref.CommitCount, &ts,
+		); err != nil {
+			return nil, err
+		}
+		ref.EventID = eventID.Int64
+		ref.RemoteURL = remoteURL.String
+		ref.CreatedAt, _ = time.Parse(time.RFC3339, ts)
+		out[ref.EventID] = append(out[ref.EventID], ref)
+	}
+
+	return out, rows.Err()
+}
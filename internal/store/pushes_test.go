@@ -0,0 +1,95 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordPushRefs(t *testing.T) {
+	db := newTestDB(t)
+
+	event := RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/path/to/repo",
+		Commit:    "abc123",
+		Branch:    "main",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    SourcePrePush,
+	}
+	require.NoError(t, InsertEvent(db, event))
+
+	refs := []PushRef{
+		{
+			RemoteName:  "origin",
+			RemoteURL:   "git@github.com:user/repo.git",
+			LocalRef:    "refs/heads/main",
+			LocalSHA:    "abc123",
+			RemoteRef:   "refs/heads/main",
+			RemoteSHA:   "def456",
+			CommitCount: 3,
+		},
+		{
+			RemoteName: "origin",
+			LocalRef:   "(delete)",
+			LocalSHA:   "0000000000000000000000000000000000000000",
+			RemoteRef:  "refs/heads/old",
+			RemoteSHA:  "fff999",
+		},
+	}
+	require.NoError(t, RecordPushRefs(db, event, refs))
+
+	// Recording the same push again updates instead of duplicating
+	refs[0].CommitCount = 4
+	require.NoError(t, RecordPushRefs(db, event, refs))
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	byEvent, err := ListPushRefsForEvents(db, []int64{events[0].ID})
+	require.NoError(t, err)
+
+	got := byEvent[events[0].ID]
+	require.Len(t, got, 2)
+	require.Equal(t, "origin", got[0].RemoteName)
+	require.Equal(t, "git@github.com:user/repo.git", got[0].RemoteURL)
+	require.Equal(t, "refs/heads/main", got[0].RemoteRef)
+	require.Equal(t, 4, got[0].CommitCount)
+	require.Empty(t, got[1].RemoteURL)
+}
+
+func TestRecordPushRefs_NoRefs(t *testing.T) {
+	db := newTestDB(t)
+
+	// No refs means nothing to look up, even for a missing event
+	require.NoError(t, RecordPushRefs(db, RepoEvent{RepoID: "missing"}, nil))
+
+	byEvent, err := ListPushRefsForEvents(db, nil)
+	require.NoError(t, err)
+	require.Empty(t, byEvent)
+}
+
+func TestRecordRewrites_DoesNotSupersedePushes(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, source := range []Source{SourcePostCommit, SourcePrePush} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID: "r", RepoPath: "/r", Commit: "old111", Branch: "main",
+			Timestamp: ts, Status: StatusPending, Source: source,
+		}))
+	}
+
+	rewriteEvent := RepoEvent{
+		RepoID: "r", RepoPath: "/r", Commit: "new222", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostRewrite,
+	}
+	require.NoError(t, InsertEvent(db, rewriteEvent))
+
+	superseded, err := RecordRewrites(db, rewriteEvent, "amend", []CommitRewrite{{OldCommit: "old111", NewCommit: "new222"}})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), superseded)
+}
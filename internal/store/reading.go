@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/where"
 )
 
 type EventFilter struct {
@@ -15,19 +16,50 @@ type EventFilter struct {
 	Since  *time.Time
 	Until  *time.Time
 	RepoID *string
+	Group  *string
+	Tag    *string
 	Limit  int
+
+	// ExcludeSuperseded hides events whose commit was rewritten (amend/rebase)
+	ExcludeSuperseded bool
+
+	// Where is a parsed --where expression, nil for none
+	Where where.Expr
 }
 
-// scanRepoEvent scans a single row into a RepoEvent.
-func scanRepoEvent(rows *sql.Rows) (RepoEvent, error) {
+// repoEventColumns is the column list shared by every repo_events query.
+// Order must match scanRepoEvent and Store.scanRepoEvent.
+const repoEventColumns = `
+			id,
+			repo_id,
+			repo_path,
+			commit_hash,
+			branch,
+			timestamp,
+			status_id,
+			source_id,
+			previous_commit,
+			previous_branch,
+			branch_switch,
+			superseded_by,
+			device`
+
+// scanRepoEvent scans a single row into a RepoEvent. Extra destinations
+// receive any columns selected after repoEventColumns.
+func scanRepoEvent(rows *sql.Rows, extra ...any) (RepoEvent, error) {
 	var (
-		e        RepoEvent
-		ts       string
-		statusID int
-		sourceID int
+		e              RepoEvent
+		ts             string
+		statusID       int
+		sourceID       int
+		previousCommit sql.NullString
+		previousBranch sql.NullString
+		branchSwitch   int
+		supersededBy   sql.NullString
+		device         sql.NullString
 	)
 
-	if err := rows.Scan(
+	dest := []any{
 		&e.ID,
 		&e.RepoID,
 		&e.RepoPath,
@@ -36,7 +68,13 @@ func scanRepoEvent(rows *sql.Rows) (RepoEvent, error) {
 		&ts,
 		&statusID,
 		&sourceID,
-	); err != nil {
+		&previousCommit,
+		&previousBranch,
+		&branchSwitch,
+		&supersededBy,
+		&device,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
 		return RepoEvent{}, err
 	}
 
@@ -48,54 +86,22 @@ func scanRepoEvent(rows *sql.Rows) (RepoEvent, error) {
 	e.Timestamp = t
 	e.Status = Status(statusID)
 	e.Source = Source(sourceID)
+	e.PreviousCommit = previousCommit.String
+	e.PreviousBranch = previousBranch.String
+	e.BranchSwitch = branchSwitch != 0
+	e.SupersededBy = supersededBy.String
+	e.Device = device.String
 
 	return e, nil
 }
 
 func ListEvents(db *sql.DB, filter EventFilter) ([]RepoEvent, error) {
 
-	base := `
-		SELECT
-			id,
-			repo_id,
-			repo_path,
-			commit_hash,
-			branch,
-			timestamp,
-			status_id,
-			source_id
+	base := `SELECT` + repoEventColumns + `
 		FROM repo_events
 	`
 
-	var (
-		filterClauses []string
-		filterArgs    []any
-	)
-
-	if filter.Status != nil {
-		filterClauses = append(filterClauses, "status_id = ?")
-		filterArgs = append(filterArgs, int(*filter.Status))
-	}
-
-	if filter.Source != nil {
-		filterClauses = append(filterClauses, "source_id = ?")
-		filterArgs = append(filterArgs, int(*filter.Source))
-	}
-
-	if filter.Since != nil {
-		filterClauses = append(filterClauses, "timestamp >= ?")
-		filterArgs = append(filterArgs, filter.Since.Format(time.RFC3339))
-	}
-
-	if filter.Until != nil {
-		filterClauses = append(filterClauses, "timestamp <= ?")
-		filterArgs = append(filterArgs, filter.Until.Format(time.RFC3339))
-	}
-
-	if filter.RepoID != nil {
-		filterClauses = append(filterClauses, "repo_id = ?")
-		filterArgs = append(filterArgs, *filter.RepoID)
-	}
+	filterClauses, filterArgs := eventFilterClauses(filter)
 
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(base)
@@ -147,6 +153,64 @@ func GetMaxEventID(db *sql.DB) (int64, error) {
 	return maxID.Int64, nil
 }
 
+// eventFilterClauses returns the WHERE conditions and their arguments for
+// a filter, to be joined with AND. Limit is left to the caller.
+func eventFilterClauses(filter EventFilter) ([]string, []any) {
+	var (
+		filterClauses []string
+		filterArgs    []any
+	)
+
+	if filter.Status != nil {
+		filterClauses = append(filterClauses, "status_id = ?")
+		filterArgs = append(filterArgs, int(*filter.Status))
+	}
+
+	if filter.Source != nil {
+		filterClauses = append(filterClauses, "source_id = ?")
+		filterArgs = append(filterArgs, int(*filter.Source))
+	}
+
+	// Timestamps are stored as UTC text, so bounds in any other zone are
+	// converted before they are compared.
+	if filter.Since != nil {
+		filterClauses = append(filterClauses, "timestamp >= ?")
+		filterArgs = append(filterArgs, filter.Since.UTC().Format(time.RFC3339))
+	}
+
+	if filter.Until != nil {
+		filterClauses = append(filterClauses, "timestamp <= ?")
+		filterArgs = append(filterArgs, filter.Until.UTC().Format(time.RFC3339))
+	}
+
+	if filter.RepoID != nil {
+		filterClauses = append(filterClauses, "repo_id = ?")
+		filterArgs = append(filterArgs, *filter.RepoID)
+	}
+
+	if filter.Group != nil {
+		filterClauses = append(filterClauses, "repo_id IN (SELECT repo_id FROM repo_meta WHERE group_name = ?)")
+		filterArgs = append(filterArgs, *filter.Group)
+	}
+
+	if filter.Tag != nil {
+		filterClauses = append(filterClauses, "id IN (SELECT event_id FROM event_tags WHERE tag = ?)")
+		filterArgs = append(filterArgs, *filter.Tag)
+	}
+
+	if filter.ExcludeSuperseded {
+		filterClauses = append(filterClauses, "superseded_by IS NULL")
+	}
+
+	if filter.Where != nil {
+		clause, args := compileWhere(filter.Where)
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	return filterClauses, filterArgs
+}
+
 // ListEventsSince returns events with ID greater than afterID, ordered by ID ascending.
 // Used for polling new events in real-time.
 func ListEventsSince(db *sql.DB, afterID int64) ([]RepoEvent, error) {
@@ -176,20 +240,25 @@ func ListEventsSinceFiltered(db *sql.DB, afterID int64, filter EventFilter) ([]R
 		filterArgs = append(filterArgs, *filter.RepoID)
 	}
 
-	query := fmt.Sprintf(`
-		SELECT
-			id,
-			repo_id,
-			repo_path,
-			commit_hash,
-			branch,
-			timestamp,
-			status_id,
-			source_id
+	if filter.Group != nil {
+		filterClauses = append(filterClauses, "repo_id IN (SELECT repo_id FROM repo_meta WHERE group_name = ?)")
+		filterArgs = append(filterArgs, *filter.Group)
+	}
+
+	if filter.Tag != nil {
+		filterClauses = append(filterClauses, "id IN (SELECT event_id FROM event_tags WHERE tag = ?)")
+		filterArgs = append(filterArgs, *filter.Tag)
+	}
+
+	if filter.ExcludeSuperseded {
+		filterClauses = append(filterClauses, "superseded_by IS NULL")
+	}
+
+	query := fmt.Sprintf(`SELECT%s
 		FROM repo_events
 		WHERE %s
 		ORDER BY id ASC
-	`, strings.Join(filterClauses, " AND "))
+	`, repoEventColumns, strings.Join(filterClauses, " AND "))
 
 	rows, err := db.Query(query, filterArgs...)
 	if err != nil {
@@ -173,6 +173,28 @@ func TestListEvents_FilterByUntil(t *testing.T) {
 	}
 }
 
+func TestListEvents_FilterByLocalBounds(t *testing.T) {
+	db := newTestDB(t)
+
+	// Timestamps are stored in UTC; bounds in another zone must still
+	// select by instant, not by comparing the text.
+	events := []RepoEvent{
+		{RepoID: "repo1", RepoPath: "/path1", Commit: "abc1", Branch: "main", Timestamp: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC), Status: StatusPending, Source: SourcePostCommit},
+		{RepoID: "repo2", RepoPath: "/path2", Commit: "abc2", Branch: "main", Timestamp: time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC), Status: StatusPending, Source: SourcePostCommit},
+	}
+	for _, e := range events {
+		require.NoError(t, InsertEvent(db, e))
+	}
+
+	pdt := time.FixedZone("PDT", -7*60*60)
+	since := time.Date(2024, 1, 15, 0, 0, 0, 0, pdt)
+	until := time.Date(2024, 1, 15, 23, 59, 59, 0, pdt)
+	got, err := ListEvents(db, EventFilter{Since: &since, Until: &until})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "abc2", got[0].Commit, "20:00 local on the 15th is the 16th in UTC")
+}
+
 func TestListEvents_FilterByRepoID(t *testing.T) {
 	db := newTestDB(t)
 
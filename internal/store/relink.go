@@ -0,0 +1,184 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RelinkResult reports what RelinkRepo changed.
+type RelinkResult struct {
+	// Moved is the number of events now recorded under the new id.
+	Moved int64
+	// Merged is the number of events dropped because the same commit and
+	// source were already recorded under the new id.
+	Merged int64
+	// Remaining is the number of events left under the old id, which
+	// happens when only pending events are moved. RelinkClone only counts
+	// the clone's own.
+	Remaining int64
+	// TrackedRepos is the number of registered clones moved to the new id.
+	TrackedRepos int64
+}
+
+// RelinkRepo moves the history of a repository from oldID to newID, for
+// repositories whose remote moved and now derive a different id. Only
+// pending events move unless allStatuses is set, so rows already exported
+// keep matching the export. Rewrite pairs, pushed refs, metadata snapshots,
+// search entries, registered clones and the alias and group move along.
+func RelinkRepo(db *sql.DB, oldID, newID string, allStatuses bool, now time.Time) (RelinkResult, error) {
+	return relink(db, oldID, newID, "", allStatuses, now)
+}
+
+// RelinkClone moves the pending events of one clone from oldID to newID,
+// along with its registration, for a clone that starts deriving its own
+// id. Other clones sharing oldID keep their events until they move too.
+func RelinkClone(db *sql.DB, repoPath, oldID, newID string, now time.Time) (RelinkResult, error) {
+	return relink(db, oldID, newID, repoPath, false, now)
+}
+
+// relink moves events from oldID to newID, only those recorded in repoPath
+// unless it is empty.
+func relink(db *sql.DB, oldID, newID, repoPath string, allStatuses bool, now time.Time) (RelinkResult, error) {
+	var result RelinkResult
+
+	where := `repo_id = ?`
+	args := []any{oldID}
+	if repoPath != "" {
+		where += ` AND repo_path = ?`
+		args = append(args, repoPath)
+	}
+	if !allStatuses {
+		where += ` AND status_id = ?`
+		args = append(args, int(StatusPending))
+	}
+	selected := `SELECT id FROM repo_events WHERE ` + where
+
+	tx, err := db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Events recorded under both ids (a hook that ran before and after the
+	// move) would break the (repo_id, commit_hash, source_id) constraint;
+	// the copy under the new id wins
+	duplicates := selected + ` AND EXISTS (
+		SELECT 1 FROM repo_events n
+		WHERE n.repo_id = ? AND n.commit_hash = repo_events.commit_hash AND n.source_id = repo_events.source_id
+	)`
+	duplicateArgs := append(append([]any{}, args...), newID)
+	for _, table := range []string{"commit_rewrites", "push_refs", "event_tags", "event_notes", "export_sink_events"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE event_id IN (`+duplicates+`)`, duplicateArgs...); err != nil {
+			return result, fmt.Errorf("delete duplicate %s: %w", table, err)
+		}
+	}
+	res, err := tx.Exec(`DELETE FROM repo_events WHERE id IN (`+duplicates+`)`, duplicateArgs...)
+	if err != nil {
+		return result, fmt.Errorf("delete duplicate events: %w", err)
+	}
+	if result.Merged, err = res.RowsAffected(); err != nil {
+		return result, err
+	}
+
+	moveArgs := append([]any{newID}, args...)
+	if _, err := tx.Exec(`UPDATE event_search SET repo_id = ? WHERE docid IN (`+selected+`)`, moveArgs...); err != nil {
+		return result, fmt.Errorf("move search entries: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE push_refs SET repo_id = ? WHERE event_id IN (`+selected+`)`, moveArgs...); err != nil {
+		return result, fmt.Errorf("move pushed refs: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE OR IGNORE commit_rewrites SET repo_id = ? WHERE event_id IN (`+selected+`)`, moveArgs...); err != nil {
+		return result, fmt.Errorf("move rewrite pairs: %w", err)
+	}
+
+	// Snapshots are keyed by repo and commit; copy them so events left
+	// under the old id keep theirs, then drop the ones nothing refers to
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO commit_metadata (
+			repo_id, commit_hash, author_name, author_email, authored_at,
+			committer_name, committer_email, committed_at, subject, body,
+			parent_commits, files_changed, insertions, deletions, captured_at
+		)
+		SELECT ?, commit_hash, author_name, author_email, authored_at,
+			committer_name, committer_email, committed_at, subject, body,
+			parent_commits, files_changed, insertions, deletions, captured_at
+		FROM commit_metadata
+		WHERE repo_id = ? AND commit_hash IN (SELECT commit_hash FROM repo_events WHERE `+where+`)
+	`, append([]any{newID, oldID}, args...)...); err != nil {
+		return result, fmt.Errorf("copy metadata snapshots: %w", err)
+	}
+
+	res, err = tx.Exec(`UPDATE repo_events SET repo_id = ? WHERE `+where, moveArgs...)
+	if err != nil {
+		return result, fmt.Errorf("move events: %w", err)
+	}
+	if result.Moved, err = res.RowsAffected(); err != nil {
+		return result, err
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM commit_metadata
+		WHERE repo_id = ? AND NOT EXISTS (
+			SELECT 1 FROM repo_events e
+			WHERE e.repo_id = commit_metadata.repo_id AND e.commit_hash = commit_metadata.commit_hash
+		)`, oldID); err != nil {
+		return result, fmt.Errorf("delete metadata snapshots: %w", err)
+	}
+
+	tracked := `UPDATE tracked_repos SET repo_id = ? WHERE repo_id = ?`
+	trackedArgs := []any{newID, oldID}
+	if repoPath != "" {
+		tracked += ` AND repo_path = ?`
+		trackedArgs = append(trackedArgs, repoPath)
+	}
+	res, err = tx.Exec(tracked, trackedArgs...)
+	if err != nil {
+		return result, fmt.Errorf("move tracked repos: %w", err)
+	}
+	if result.TrackedRepos, err = res.RowsAffected(); err != nil {
+		return result, err
+	}
+
+	var remaining int64
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM repo_events WHERE repo_id = ?`, oldID).Scan(&remaining); err != nil {
+		return result, fmt.Errorf("count remaining events: %w", err)
+	}
+	result.Remaining = remaining
+	if repoPath != "" {
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM repo_events WHERE repo_id = ? AND repo_path = ?`, oldID, repoPath).Scan(&result.Remaining); err != nil {
+			return result, fmt.Errorf("count remaining events: %w", err)
+		}
+	}
+
+	// The alias and group follow the repository unless the new id already
+	// has its own; they stay on the old id while events remain there
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO repo_meta (repo_id, alias, group_name, updated_at)
+		SELECT ?, alias, group_name, ? FROM repo_meta WHERE repo_id = ?
+	`, newID, now.UTC().Format(time.RFC3339), oldID); err != nil {
+		return result, fmt.Errorf("copy alias and group: %w", err)
+	}
+	if remaining == 0 {
+		if _, err := tx.Exec(`DELETE FROM repo_meta WHERE repo_id = ?`, oldID); err != nil {
+			return result, fmt.Errorf("delete alias and group: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// TrackedRepoID returns the repo id stored for a registered clone, or an
+// empty string when the path is not registered.
+func TrackedRepoID(db *sql.DB, repoPath string) (string, error) {
+	var repoID string
+	err := db.QueryRow(`SELECT repo_id FROM tracked_repos WHERE repo_path = ?`, repoPath).Scan(&repoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return repoID, err
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func TestRelinkRepo(t *testing.T) {
+	const (
+		oldID = "github.com/old-org/api"
+		newID = "github.com/new-org/api"
+	)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	insert := func(t *testing.T, db *sql.DB, repoID, commit string, status Status) {
+		t.Helper()
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID:    repoID,
+			RepoPath:  "/src/api",
+			Commit:    commit,
+			Branch:    "main",
+			Timestamp: now,
+			Status:    status,
+			Source:    SourcePostCommit,
+		}))
+	}
+
+	t.Run("pending only", func(t *testing.T) {
+		db := newTestDB(t)
+		insert(t, db, oldID, "aaa111", StatusExported)
+		insert(t, db, oldID, "bbb222", StatusPending)
+		insert(t, db, oldID, "ccc333", StatusPending)
+		insert(t, db, newID, "ccc333", StatusPending)
+		require.NoError(t, SaveCommitMetadata(db, oldID, "bbb222", git.CommitMetadata{Subject: "Fix login"}))
+		_, err := db.Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES (?, '/src/api')`, oldID)
+		require.NoError(t, err)
+		require.NoError(t, SetRepoAlias(db, oldID, "api", now))
+
+		result, err := RelinkRepo(db, oldID, newID, false, now)
+		require.NoError(t, err)
+		require.Equal(t, RelinkResult{Moved: 1, Merged: 1, Remaining: 1, TrackedRepos: 1}, result)
+
+		ok, err := HasCommitMetadata(db, newID, "bbb222")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = HasCommitMetadata(db, oldID, "bbb222")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		repoID, err := TrackedRepoID(db, "/src/api")
+		require.NoError(t, err)
+		require.Equal(t, newID, repoID)
+
+		// The alias is copied while exported events remain under the old id
+		aliases, err := RepoAliases(db)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{oldID: "api", newID: "api"}, aliases)
+	})
+
+	t.Run("one clone", func(t *testing.T) {
+		db := newTestDB(t)
+		insert(t, db, oldID, "aaa111", StatusPending)
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID: oldID, RepoPath: "/src/api-copy", Commit: "bbb222", Branch: "main",
+			Timestamp: now, Status: StatusPending, Source: SourcePostCommit,
+		}))
+		_, err := db.Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES (?, '/src/api'), (?, '/src/api-copy')`, oldID, oldID)
+		require.NoError(t, err)
+		require.NoError(t, SetRepoAlias(db, oldID, "api", now))
+
+		result, err := RelinkClone(db, "/src/api", oldID, newID, now)
+		require.NoError(t, err)
+		require.Equal(t, RelinkResult{Moved: 1, TrackedRepos: 1}, result)
+
+		// The other clone keeps its events, registration and alias
+		repoID, err := TrackedRepoID(db, "/src/api-copy")
+		require.NoError(t, err)
+		require.Equal(t, oldID, repoID)
+		e, err := FindEvent(db, "bbb222")
+		require.NoError(t, err)
+		require.Equal(t, oldID, e.RepoID)
+		aliases, err := RepoAliases(db)
+		require.NoError(t, err)
+		require.Equal(t, "api", aliases[oldID])
+	})
+
+	t.Run("all statuses", func(t *testing.T) {
+		db := newTestDB(t)
+		insert(t, db, oldID, "aaa111", StatusExported)
+		insert(t, db, oldID, "bbb222", StatusPending)
+		require.NoError(t, SetRepoGroup(db, []string{oldID}, "payments", now))
+
+		result, err := RelinkRepo(db, oldID, newID, true, now)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.Moved)
+		require.Zero(t, result.Remaining)
+
+		ok, err := RepoIDExists(db, oldID)
+		require.NoError(t, err)
+		require.False(t, ok)
+		ids, err := GroupRepoIDs(db, "payments")
+		require.NoError(t, err)
+		require.Equal(t, []string{newID}, ids)
+	})
+}
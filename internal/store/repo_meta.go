@@ -0,0 +1,139 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RepoMeta is what the user set for a repository: the alias shown instead
+// of its id and the group it belongs to. Empty fields are unset.
+type RepoMeta struct {
+	RepoID string
+	Alias  string
+	Group  string
+}
+
+// SetRepoAlias sets the display alias of a repository. An empty alias
+// removes it.
+func SetRepoAlias(db *sql.DB, repoID, alias string, now time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO repo_meta (repo_id, alias, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(repo_id) DO UPDATE SET
+			alias = excluded.alias,
+			updated_at = excluded.updated_at
+	`, repoID, nullString(alias), now.UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	return deleteEmptyRepoMeta(db)
+}
+
+// SetRepoGroup moves repositories into a group, replacing any group they
+// were in. An empty group removes them from their group.
+func SetRepoGroup(db *sql.DB, repoIDs []string, group string, now time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, repoID := range repoIDs {
+		_, err := tx.Exec(`
+			INSERT INTO repo_meta (repo_id, group_name, updated_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT(repo_id) DO UPDATE SET
+				group_name = excluded.group_name,
+				updated_at = excluded.updated_at
+		`, repoID, nullString(group), now.UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("set group of %s: %w", repoID, err)
+		}
+	}
+
+	if err := deleteEmptyRepoMeta(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteEmptyRepoMeta drops rows left with neither an alias nor a group.
+func deleteEmptyRepoMeta(db execer) error {
+	_, err := db.Exec(`DELETE FROM repo_meta WHERE alias IS NULL AND group_name IS NULL`)
+	return err
+}
+
+// ListRepoMeta returns every repository with an alias or group, ordered by
+// group and repo id.
+func ListRepoMeta(db *sql.DB) ([]RepoMeta, error) {
+	rows, err := db.Query(`
+		SELECT repo_id, alias, group_name
+		FROM repo_meta
+		ORDER BY group_name IS NULL, group_name, repo_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []RepoMeta
+	for rows.Next() {
+		var (
+			m            RepoMeta
+			alias, group sql.NullString
+		)
+		if err := rows.Scan(&m.RepoID, &alias, &group); err != nil {
+			return nil, err
+		}
+		m.Alias = alias.String
+		m.Group = group.String
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// RepoAliases returns the alias of every repository that has one, by repo id.
+func RepoAliases(db *sql.DB) (map[string]string, error) {
+	meta, err := ListRepoMeta(db)
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]string)
+	for _, m := range meta {
+		if m.Alias != "" {
+			aliases[m.RepoID] = m.Alias
+		}
+	}
+	return aliases, nil
+}
+
+// GroupRepoIDs returns the ids of the repositories in a group.
+func GroupRepoIDs(db *sql.DB, group string) ([]string, error) {
+	rows, err := db.Query(`SELECT repo_id FROM repo_meta WHERE group_name = ? ORDER BY repo_id`, group)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// RepoIDExists reports whether a repository has recorded events or is
+// tracked, so typos in repo ids can be caught before they are stored.
+func RepoIDExists(db *sql.DB, repoID string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM repo_events WHERE repo_id = ?)
+			OR EXISTS (SELECT 1 FROM tracked_repos WHERE repo_id = ?)
+	`, repoID, repoID).Scan(&exists)
+	return exists, err
+}
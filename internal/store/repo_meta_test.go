@@ -0,0 +1,75 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoMeta(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, SetRepoAlias(db, "github.com/acme/platform-services-api", "api", now))
+	require.NoError(t, SetRepoGroup(db, []string{"github.com/acme/platform-services-api", "github.com/acme/ledger"}, "payments", now))
+	require.NoError(t, SetRepoGroup(db, []string{"github.com/acme/web"}, "storefront", now))
+
+	aliases, err := RepoAliases(db)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"github.com/acme/platform-services-api": "api"}, aliases)
+
+	ids, err := GroupRepoIDs(db, "payments")
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/acme/ledger", "github.com/acme/platform-services-api"}, ids)
+
+	// Adding to another group moves the repository
+	require.NoError(t, SetRepoGroup(db, []string{"github.com/acme/ledger"}, "storefront", now))
+	ids, err = GroupRepoIDs(db, "payments")
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/acme/platform-services-api"}, ids)
+
+	// Rows with neither alias nor group are dropped
+	require.NoError(t, SetRepoGroup(db, []string{"github.com/acme/web"}, "", now))
+	require.NoError(t, SetRepoAlias(db, "github.com/acme/platform-services-api", "", now))
+	meta, err := ListRepoMeta(db)
+	require.NoError(t, err)
+	require.Equal(t, []RepoMeta{
+		{RepoID: "github.com/acme/platform-services-api", Group: "payments"},
+		{RepoID: "github.com/acme/ledger", Group: "storefront"},
+	}, meta)
+}
+
+func TestListEvents_GroupFilter(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, repoID := range []string{"github.com/acme/api", "github.com/acme/web"} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID:    repoID,
+			RepoPath:  "/" + repoID,
+			Commit:    "abc" + repoID,
+			Branch:    "main",
+			Timestamp: now.Add(time.Duration(i) * time.Hour),
+			Status:    StatusPending,
+			Source:    SourcePostCommit,
+		}))
+	}
+	require.NoError(t, SetRepoGroup(db, []string{"github.com/acme/api"}, "payments", now))
+
+	group := "payments"
+	events, err := ListEvents(db, EventFilter{Group: &group})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "github.com/acme/api", events[0].RepoID)
+
+	events, err = ListEventsSinceFiltered(db, 0, EventFilter{Group: &group})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	ok, err := RepoIDExists(db, "github.com/acme/web")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = RepoIDExists(db, "github.com/acme/typo")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// CommitRewrite maps a commit to the one that replaced it (amend or rebase).
+type CommitRewrite struct {
+	ID          int64
+	EventID     int64
+	RepoID      string
+	OldCommit   string
+	NewCommit   string
+	RewriteType string
+	CreatedAt   time.Time
+}
+
+// RecordRewrites stores rewrite pairs reported by a post-rewrite event and
+// marks events for the old commits as superseded by the new ones.
+// The event must already be inserted. Returns the number of events superseded.
+func RecordRewrites(db *sql.DB, event RepoEvent, rewriteType string, rewrites []CommitRewrite) (int64, error) {
+	if len(rewrites) == 0 {
+		return 0, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	superseded, err := recordRewrites(tx, event, rewriteType, rewrites)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return superseded, nil
+}
+
+func recordRewrites(tx querier, event RepoEvent, rewriteType string, rewrites []CommitRewrite) (int64, error) {
+	if len(rewrites) == 0 {
+		return 0, nil
+	}
+
+	var eventID int64
+	err := tx.QueryRow(
+		`SELECT id FROM repo_events WHERE repo_id = ? AND commit_hash = ? AND source_id = ?`,
+		event.RepoID, event.Commit, int(event.Source),
+	).Scan(&eventID)
+	if err != nil {
+		return 0, fmt.Errorf("find rewrite event: %w", err)
+	}
+
+	createdAt := event.Timestamp.UTC().Format(time.RFC3339)
+
+	var superseded int64
+	for _, rw := range rewrites {
+		_, err := tx.Exec(
+			`INSERT INTO commit_rewrites
+			 (event_id, repo_id, old_commit, new_commit, rewrite_type, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(repo_id, old_commit, new_commit)
+			 DO UPDATE SET event_id = excluded.event_id, rewrite_type = excluded.rewrite_type`,
+			eventID, event.RepoID, rw.OldCommit, rw.NewCommit, rewriteType, createdAt,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("insert rewrite %.7s: %w", rw.OldCommit, err)
+		}
+
+		// A commit rewritten onto itself (e.g. amend with no changes) is not superseded
+		if rw.OldCommit == rw.NewCommit {
+			continue
+		}
+
+		// Pushes of the old commit still happened, so they are never superseded
+		result, err := tx.Exec(
+			`UPDATE repo_events SET superseded_by = ?
+			 WHERE repo_id = ? AND commit_hash = ? AND superseded_by IS NULL AND source_id != ?`,
+			rw.NewCommit, event.RepoID, rw.OldCommit, int(SourcePrePush),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("mark superseded %.7s: %w", rw.OldCommit, err)
+		}
+		n, _ := result.RowsAffected()
+		superseded += n
+	}
+
+	return superseded, nil
+}
+
+// ListRewritesForEvents returns rewrite pairs grouped by the post-rewrite event that reported them.
+func ListRewritesForEvents(db *sql.DB, eventIDs []int64) (map[int64][]CommitRewrite, error) {
+	out := make(map[int64][]CommitRewrite)
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, len(eventIDs))
+	for i, id := range eventIDs {
+		args[i] = id
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(eventIDs)), ",")
+	query := fmt.Sprintf(`
+		SELECT id, event_id, repo_id, old_commit, new_commit, rewrite_type, created_at
+		FROM commit_rewrites
+		WHERE event_id IN (%s)
+		ORDER BY id ASC
+	`, placeholders)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Error("store: list rewrites query failed: %v", err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var (
+			rw      CommitRewrite
+			eventID sql.NullInt64
+			ts      string
+		)
+		if err := rows.Scan(&rw.ID, &eventID, &rw.RepoID, &rw.OldCommit, &rw.NewCommit, &rw.RewriteType, &ts); err != nil {
+			return nil, err
+		}
+		rw.EventID = eventID.Int64
+		rw.CreatedAt, _ = time.Parse(time.RFC3339, ts)
+		out[rw.EventID] = append(out[rw.EventID], rw)
+	}
+
+	return out, rows.Err()
+}
+
+// ListSupersededCommits returns the repo_id:commit keys of all superseded events.
+// Used by export to drop rewritten commits from the CSV.
+func ListSupersededCommits(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT DISTINCT repo_id, commit_hash FROM repo_events WHERE superseded_by IS NOT NULL`)
+	if err != nil {
+		log.Error("store: list superseded commits failed: %v", err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var repoID, commit string
+		if err := rows.Scan(&repoID, &commit); err != nil {
+			return nil, err
+		}
+		out[repoID+":"+commit] = true
+	}
+
+	return out, rows.Err()
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRewrites_MarksSupersededEvents(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	// Original commit recorded by post-commit
+	require.NoError(t, InsertEvent(db, RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/path/to/repo",
+		Commit:    "old111",
+		Branch:    "main",
+		Timestamp: ts,
+		Status:    StatusPending,
+		Source:    SourcePostCommit,
+	}))
+
+	// Amend produces a post-rewrite event for the new commit
+	rewriteEvent := RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/path/to/repo",
+		Commit:    "new222",
+		Branch:    "main",
+		Timestamp: ts.Add(time.Minute),
+		Status:    StatusPending,
+		Source:    SourcePostRewrite,
+	}
+	require.NoError(t, InsertEvent(db, rewriteEvent))
+
+	superseded, err := RecordRewrites(db, rewriteEvent, "amend", []CommitRewrite{
+		{OldCommit: "old111", NewCommit: "new222"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), superseded)
+
+	// Superseded event is hidden when requested
+	events, err := ListEvents(db, EventFilter{ExcludeSuperseded: true})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "new222", events[0].Commit)
+
+	// ...and annotated otherwise
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		if e.Commit == "old111" {
+			require.Equal(t, "new222", e.SupersededBy)
+		} else {
+			require.Empty(t, e.SupersededBy)
+		}
+	}
+
+	keys, err := ListSupersededCommits(db)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"github.com/user/repo:old111": true}, keys)
+}
+
+func TestRecordRewrites_ListByEvent(t *testing.T) {
+	db := newTestDB(t)
+
+	event := RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/path/to/repo",
+		Commit:    "new222",
+		Branch:    "main",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    SourcePostRewrite,
+	}
+	require.NoError(t, InsertEvent(db, event))
+
+	rewrites := []CommitRewrite{
+		{OldCommit: "a1", NewCommit: "b1"},
+		{OldCommit: "a2", NewCommit: "new222"},
+	}
+	_, err := RecordRewrites(db, event, "rebase", rewrites)
+	require.NoError(t, err)
+
+	// Recording the same pairs again is idempotent
+	_, err = RecordRewrites(db, event, "rebase", rewrites)
+	require.NoError(t, err)
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	byEvent, err := ListRewritesForEvents(db, []int64{events[0].ID})
+	require.NoError(t, err)
+	require.Len(t, byEvent[events[0].ID], 2)
+	require.Equal(t, "a1", byEvent[events[0].ID][0].OldCommit)
+	require.Equal(t, "rebase", byEvent[events[0].ID][0].RewriteType)
+}
+
+func TestRecordRewrites_EventMustExist(t *testing.T) {
+	db := newTestDB(t)
+
+	_, err := RecordRewrites(db, RepoEvent{RepoID: "missing", Commit: "x", Source: SourcePostRewrite}, "amend", []CommitRewrite{
+		{OldCommit: "a", NewCommit: "b"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,279 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/where"
+)
+
+// searchColumnWeights ranks a hit in the subject above one in the branch,
+// body or repo ID. Order matches the event_search columns.
+var searchColumnWeights = []float64{4, 1, 2, 1}
+
+// SearchFilter narrows full-text search results.
+type SearchFilter struct {
+	Since  *time.Time
+	Until  *time.Time
+	RepoID *string
+	Group  *string
+	// Where is a parsed --where expression, nil for none
+	Where where.Expr
+	Limit int
+}
+
+// SearchResult is an event matched by full-text search.
+type SearchResult struct {
+	Event   RepoEvent
+	Subject string
+	Score   float64
+}
+
+// searchableSources are the sources whose commit is their own work.
+// Checkouts and pushes point at existing commits and would only repeat them.
+var searchableSources = []Source{
+	SourcePostCommit,
+	SourcePostRewrite,
+	SourcePostMerge,
+	SourceManual,
+	SourceBackfill,
+}
+
+// IsSearchable reports whether events from source get a search entry.
+func IsSearchable(source Source) bool {
+	for _, s := range searchableSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchExpression turns a user query into an FTS match expression.
+// Double-quoted text is matched as a phrase; other words match as prefixes,
+// so "auth time" finds "authentication timeout". Returns an empty string
+// when the query has nothing to search for.
+func MatchExpression(query string) string {
+	var terms []string
+
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if phrase := searchWords(part); phrase != "" {
+				terms = append(terms, `"`+phrase+`"`)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if w := searchWords(word); w != "" {
+				terms = append(terms, `"`+w+`*"`)
+			}
+		}
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// searchWords keeps the letters and digits of s, separating everything
+// else with single spaces.
+func searchWords(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// IndexEvent adds or replaces the search entry for an event.
+func IndexEvent(db *sql.DB, e RepoEvent, subject, body string) error {
+	_, err := db.Exec(
+		`INSERT OR REPLACE INTO event_search (docid, subject, body, branch, repo_id)
+		 VALUES (?, ?, ?, ?, ?)`,
+		e.ID, subject, body, e.Branch, e.RepoID,
+	)
+	if err != nil {
+		log.Error("store: index event failed: %v (id=%d)", err, e.ID)
+	}
+	return err
+}
+
+// IndexRecordedEvent adds the search entry for an event just inserted,
+// found by its repo, commit and source. Checkouts and pushes are not
+// searchable and are skipped.
+func IndexRecordedEvent(db *sql.DB, e RepoEvent, subject, body string) error {
+	if !IsSearchable(e.Source) {
+		return nil
+	}
+
+	err := db.QueryRow(
+		`SELECT id FROM repo_events WHERE repo_id = ? AND commit_hash = ? AND source_id = ?`,
+		e.RepoID, e.Commit, int(e.Source),
+	).Scan(&e.ID)
+	if err != nil {
+		return fmt.Errorf("find event: %w", err)
+	}
+	return IndexEvent(db, e, subject, body)
+}
+
+// ListUnindexedEvents returns searchable events that have no search entry yet,
+// oldest first.
+func ListUnindexedEvents(db *sql.DB) ([]RepoEvent, error) {
+	args := make([]any, len(searchableSources))
+	for i, s := range searchableSources {
+		args[i] = int(s)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")
+
+	query := fmt.Sprintf(`SELECT`+repoEventColumns+`
+		FROM repo_events
+		WHERE source_id IN (%s)
+		  AND id NOT IN (SELECT docid FROM event_search)
+		ORDER BY id ASC
+	`, placeholders)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Error("store: list unindexed events query failed: %v", err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []RepoEvent
+	for rows.Next() {
+		e, err := scanRepoEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+
+	return out, rows.Err()
+}
+
+// SearchEvents runs a full-text search over indexed events and returns the
+// matches best first, newest first among equal scores. Superseded commits are
+// left out. The query is passed through MatchExpression.
+func SearchEvents(db *sql.DB, query string, filter SearchFilter) ([]SearchResult, error) {
+	match := MatchExpression(query)
+	if match == "" {
+		return nil, nil
+	}
+
+	var (
+		filterClauses = []string{"superseded_by IS NULL"}
+		filterArgs    = []any{match}
+	)
+
+	if filter.Since != nil {
+		filterClauses = append(filterClauses, "timestamp >= ?")
+		filterArgs = append(filterArgs, filter.Since.UTC().Format(time.RFC3339))
+	}
+
+	if filter.Until != nil {
+		filterClauses = append(filterClauses, "timestamp <= ?")
+		filterArgs = append(filterArgs, filter.Until.UTC().Format(time.RFC3339))
+	}
+
+	if filter.RepoID != nil {
+		filterClauses = append(filterClauses, "repo_id = ?")
+		filterArgs = append(filterArgs, *filter.RepoID)
+	}
+
+	if filter.Group != nil {
+		filterClauses = append(filterClauses, "repo_id IN (SELECT repo_id FROM repo_meta WHERE group_name = ?)")
+		filterArgs = append(filterArgs, *filter.Group)
+	}
+
+	if filter.Where != nil {
+		clause, args := compileWhere(filter.Where)
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	sqlQuery := `SELECT` + repoEventColumns + `,
+			match_subject,
+			match_info
+		FROM repo_events
+		JOIN (
+			SELECT docid, subject AS match_subject, matchinfo(event_search, 'pcnx') AS match_info
+			FROM event_search
+			WHERE event_search MATCH ?
+		) ON id = docid
+		WHERE ` + strings.Join(filterClauses, " AND ")
+
+	rows, err := db.Query(sqlQuery, filterArgs...)
+	if err != nil {
+		log.Error("store: search events query failed: %v", err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []SearchResult
+	for rows.Next() {
+		var (
+			r    SearchResult
+			info []byte
+		)
+		r.Event, err = scanRepoEvent(rows, &r.Subject, &info)
+		if err != nil {
+			log.Error("store: scan search row failed: %v", err)
+			return nil, err
+		}
+		r.Score = searchScore(info)
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Event.Timestamp.After(out[j].Event.Timestamp)
+	})
+
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+
+	return out, nil
+}
+
+// searchScore ranks a row from its matchinfo 'pcnx' blob. Every phrase hit
+// counts its column weight, scaled up for words that appear in few rows
+// (a simple tf-idf), so rare words in the subject score highest.
+func searchScore(info []byte) float64 {
+	values := make([]uint32, len(info)/4)
+	for i := range values {
+		values[i] = binary.NativeEndian.Uint32(info[i*4:])
+	}
+	if len(values) < 3 {
+		return 0
+	}
+
+	phrases, columns, rows := int(values[0]), int(values[1]), float64(values[2])
+	var score float64
+	for p := 0; p < phrases; p++ {
+		for c := 0; c < columns; c++ {
+			idx := 3 + 3*(p*columns+c)
+			if idx+2 >= len(values) {
+				return score
+			}
+			hitsRow, docsWithHits := values[idx], values[idx+2]
+			if hitsRow == 0 || docsWithHits == 0 {
+				continue
+			}
+			weight := 1.0
+			if c < len(searchColumnWeights) {
+				weight = searchColumnWeights[c]
+			}
+			score += weight * float64(hitsRow) * (1 + math.Log(rows/float64(docsWithHits)))
+		}
+	}
+	return score
+}
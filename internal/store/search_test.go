@@ -0,0 +1,173 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/where"
+)
+
+func insertIndexedEvent(t *testing.T, db *sql.DB, e RepoEvent, subject string) RepoEvent {
+	t.Helper()
+
+	require.NoError(t, InsertEvent(db, e))
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	for _, stored := range events {
+		if stored.Commit == e.Commit && stored.Source == e.Source {
+			require.NoError(t, IndexEvent(db, stored, subject, ""))
+			return stored
+		}
+	}
+	t.Fatalf("event %s not stored", e.Commit)
+	return RepoEvent{}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"auth timeout", `"auth*" "timeout*"`},
+		{`"auth timeout" fix`, `"auth timeout" "fix*"`},
+		{"feature/auth-fix", `"feature auth fix*"`},
+		{"  --- ", ""},
+		{"", ""},
+		{"café", `"café*"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			require.Equal(t, tt.want, MatchExpression(tt.query))
+		})
+	}
+}
+
+func TestSearchEvents_RanksAndFilters(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+
+	base := RepoEvent{RepoID: "github.com/user/api", RepoPath: "/src/api", Branch: "main", Status: StatusPending, Source: SourcePostCommit}
+
+	subjectHit := base
+	subjectHit.Commit, subjectHit.Timestamp = "aaa111", ts
+	insertIndexedEvent(t, db, subjectHit, "Fix auth timeout on login")
+
+	branchHit := base
+	branchHit.Commit, branchHit.Branch, branchHit.Timestamp = "bbb222", "fix/auth-timeout", ts.Add(time.Hour)
+	insertIndexedEvent(t, db, branchHit, "Bump dependencies")
+
+	otherRepo := base
+	otherRepo.Commit, otherRepo.RepoID, otherRepo.Timestamp = "ccc333", "github.com/user/web", ts.Add(-48*time.Hour)
+	insertIndexedEvent(t, db, otherRepo, "Authentication timeout banner")
+
+	insertIndexedEvent(t, db, RepoEvent{RepoID: "github.com/user/api", RepoPath: "/src/api", Commit: "ddd444", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostCommit}, "Update README")
+
+	results, err := SearchEvents(db, "auth timeout", SearchFilter{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "aaa111", results[0].Event.Commit, "subject hits rank above branch hits")
+	require.Equal(t, "Fix auth timeout on login", results[0].Subject)
+
+	repoID := "github.com/user/api"
+	results, err = SearchEvents(db, "auth timeout", SearchFilter{RepoID: &repoID})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	since := ts.Add(-time.Hour)
+	results, err = SearchEvents(db, "auth", SearchFilter{Since: &since, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NotEqual(t, "ccc333", results[0].Event.Commit)
+
+	expr, err := where.Parse(`branch ~ "fix/*"`)
+	require.NoError(t, err)
+	results, err = SearchEvents(db, "auth timeout", SearchFilter{Where: expr})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "bbb222", results[0].Event.Commit)
+
+	results, err = SearchEvents(db, "---", SearchFilter{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestSearchEvents_SkipsSupersededAndDeleted(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+
+	old := insertIndexedEvent(t, db, RepoEvent{RepoID: "repo", RepoPath: "/r", Commit: "old111", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostCommit}, "Fix flaky parser")
+	rewritten := RepoEvent{RepoID: "repo", RepoPath: "/r", Commit: "new222", Branch: "main",
+		Timestamp: ts.Add(time.Minute), Status: StatusPending, Source: SourcePostRewrite}
+	insertIndexedEvent(t, db, rewritten, "Fix flaky parser")
+
+	_, err := RecordRewrites(db, rewritten, "amend", []CommitRewrite{{OldCommit: "old111", NewCommit: "new222"}})
+	require.NoError(t, err)
+
+	results, err := SearchEvents(db, "parser", SearchFilter{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "new222", results[0].Event.Commit)
+
+	// Deleting an event drops its search entry
+	_, err = db.Exec(`DELETE FROM repo_events WHERE id = ?`, old.ID)
+	require.NoError(t, err)
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM event_search WHERE docid = ?`, old.ID).Scan(&n))
+	require.Zero(t, n)
+}
+
+func TestListUnindexedEvents(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+
+	indexed := insertIndexedEvent(t, db, RepoEvent{RepoID: "repo", RepoPath: "/r", Commit: "aaa111", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostCommit}, "Indexed")
+	require.NoError(t, InsertEvent(db, RepoEvent{RepoID: "repo", RepoPath: "/r", Commit: "bbb222", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourceBackfill}))
+	require.NoError(t, InsertEvent(db, RepoEvent{RepoID: "repo", RepoPath: "/r", Commit: "aaa111", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostCheckout}))
+
+	events, err := ListUnindexedEvents(db)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "bbb222", events[0].Commit)
+	require.NotEqual(t, indexed.ID, events[0].ID)
+}
+
+func TestIndexRecordedEvent(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+
+	commit := RepoEvent{RepoID: "repo", RepoPath: "/r", Commit: "aaa111", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostCommit}
+	push := commit
+	push.Source = SourcePrePush
+	require.NoError(t, InsertEvent(db, commit))
+	require.NoError(t, InsertEvent(db, push))
+
+	require.NoError(t, IndexRecordedEvent(db, commit, "Fix auth timeout", ""))
+	require.NoError(t, IndexRecordedEvent(db, push, "Fix auth timeout", ""))
+
+	results, err := SearchEvents(db, "timeout", SearchFilter{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, SourcePostCommit, results[0].Event.Source)
+
+	events, err := ListUnindexedEvents(db)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestIsSearchable(t *testing.T) {
+	for _, s := range []Source{SourcePostCommit, SourcePostRewrite, SourcePostMerge, SourceManual, SourceBackfill} {
+		require.True(t, IsSearchable(s), s.String())
+	}
+	require.False(t, IsSearchable(SourcePostCheckout))
+	require.False(t, IsSearchable(SourcePrePush))
+}
@@ -0,0 +1,257 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// SpooledEvent is an event that could not be written to the database,
+// together with the rewrite pairs and pushed refs recorded with it.
+type SpooledEvent struct {
+	Event       RepoEvent
+	RewriteType string
+	Rewrites    []CommitRewrite
+	PushRefs    []PushRef
+	Error       string
+	SpooledAt   time.Time
+}
+
+// spoolLine is the JSON form of a SpooledEvent, one per line of the spool.
+type spoolLine struct {
+	SpooledAt      string         `json:"spooled_at"`
+	Error          string         `json:"error,omitempty"`
+	RepoID         string         `json:"repo_id"`
+	RepoPath       string         `json:"repo_path"`
+	Commit         string         `json:"commit"`
+	Branch         string         `json:"branch"`
+	Timestamp      string         `json:"timestamp"`
+	Source         string         `json:"source"`
+	PreviousCommit string         `json:"previous_commit,omitempty"`
+	PreviousBranch string         `json:"previous_branch,omitempty"`
+	BranchSwitch   bool           `json:"branch_switch,omitempty"`
+	RewriteType    string         `json:"rewrite_type,omitempty"`
+	Rewrites       []spoolRewrite `json:"rewrites,omitempty"`
+	PushRefs       []spoolPushRef `json:"push_refs,omitempty"`
+}
+
+type spoolRewrite struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+type spoolPushRef struct {
+	RemoteName  string `json:"remote_name"`
+	RemoteURL   string `json:"remote_url,omitempty"`
+	LocalRef    string `json:"local_ref"`
+	LocalSHA    string `json:"local_sha"`
+	RemoteRef   string `json:"remote_ref"`
+	RemoteSHA   string `json:"remote_sha"`
+	CommitCount int    `json:"commit_count"`
+}
+
+const (
+	claimSuffix = ".replay"
+	badSuffix   = ".bad"
+)
+
+// AppendSpool adds an event to the spool file and syncs it to disk.
+// Each event is a single write to a file opened for appending, so hooks
+// running at the same time don't interleave lines.
+func AppendSpool(path string, e SpooledEvent) error {
+	line := spoolLine{
+		SpooledAt:      e.SpooledAt.UTC().Format(time.RFC3339),
+		Error:          e.Error,
+		RepoID:         e.Event.RepoID,
+		RepoPath:       e.Event.RepoPath,
+		Commit:         e.Event.Commit,
+		Branch:         e.Event.Branch,
+		Timestamp:      e.Event.Timestamp.UTC().Format(time.RFC3339),
+		Source:         strings.ToLower(e.Event.Source.String()),
+		PreviousCommit: e.Event.PreviousCommit,
+		PreviousBranch: e.Event.PreviousBranch,
+		BranchSwitch:   e.Event.BranchSwitch,
+		RewriteType:    e.RewriteType,
+	}
+	for _, r := range e.Rewrites {
+		line.Rewrites = append(line.Rewrites, spoolRewrite{Old: r.OldCommit, New: r.NewCommit})
+	}
+	for _, r := range e.PushRefs {
+		line.PushRefs = append(line.PushRefs, spoolPushRef{
+			RemoteName:  r.RemoteName,
+			RemoteURL:   r.RemoteURL,
+			LocalRef:    r.LocalRef,
+			LocalSHA:    r.LocalSHA,
+			RemoteRef:   r.RemoteRef,
+			RemoteSHA:   r.RemoteSHA,
+			CommitCount: r.CommitCount,
+		})
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+
+	return appendLine(path, data)
+}
+
+// appendLine adds a line to a spool file and syncs it to disk.
+func appendLine(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create spool directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("open spool: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write spool: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("sync spool: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadSpool returns the events in a spool file, oldest first. A missing file
+// is an empty spool. Lines that can't be parsed are logged and skipped.
+func ReadSpool(path string) ([]SpooledEvent, error) {
+	return readSpool(path, func(int, string) error { return nil })
+}
+
+// ReadSpoolClaim reads a claimed spool file for replaying. Lines that can't
+// be parsed are appended to badPath, so removing the claim once it is
+// replayed loses nothing; the claim must be kept if they could not be saved.
+func ReadSpoolClaim(claim, badPath string) ([]SpooledEvent, error) {
+	return readSpool(claim, func(n int, text string) error {
+		if err := appendLine(badPath, []byte(text)); err != nil {
+			return fmt.Errorf("keep line %d of %s: %w", n, claim, err)
+		}
+		log.Warn("spool: kept unreadable line %d of %s in %s", n, claim, badPath)
+		return nil
+	})
+}
+
+// BadSpoolPath returns the file that keeps spool lines that could not be
+// parsed when they were replayed.
+func BadSpoolPath(path string) string {
+	return path + badSuffix
+}
+
+// readSpool parses a spool file, passing lines that can't be parsed to bad.
+func readSpool(path string, bad func(n int, text string) error) ([]SpooledEvent, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open spool: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []SpooledEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		e, err := parseSpoolLine(text)
+		if err != nil {
+			log.Warn("spool: skipping line %d of %s: %v", n, path, err)
+			if err := bad(n, text); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read spool: %w", err)
+	}
+	return events, nil
+}
+
+func parseSpoolLine(text string) (SpooledEvent, error) {
+	var line spoolLine
+	if err := json.Unmarshal([]byte(text), &line); err != nil {
+		return SpooledEvent{}, err
+	}
+
+	source, ok := domain.ParseEventSource(line.Source)
+	if !ok {
+		return SpooledEvent{}, fmt.Errorf("unknown source %q", line.Source)
+	}
+	timestamp, err := time.Parse(time.RFC3339, line.Timestamp)
+	if err != nil {
+		return SpooledEvent{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	spooledAt, _ := time.Parse(time.RFC3339, line.SpooledAt)
+
+	e := SpooledEvent{
+		Event: RepoEvent{
+			RepoID:         line.RepoID,
+			RepoPath:       line.RepoPath,
+			Commit:         line.Commit,
+			Branch:         line.Branch,
+			Timestamp:      timestamp,
+			Status:         StatusPending,
+			Source:         source,
+			PreviousCommit: line.PreviousCommit,
+			PreviousBranch: line.PreviousBranch,
+			BranchSwitch:   line.BranchSwitch,
+		},
+		RewriteType: line.RewriteType,
+		Error:       line.Error,
+		SpooledAt:   spooledAt,
+	}
+	for _, r := range line.Rewrites {
+		e.Rewrites = append(e.Rewrites, CommitRewrite{OldCommit: r.Old, NewCommit: r.New})
+	}
+	for _, r := range line.PushRefs {
+		e.PushRefs = append(e.PushRefs, PushRef{
+			RemoteName:  r.RemoteName,
+			RemoteURL:   r.RemoteURL,
+			LocalRef:    r.LocalRef,
+			LocalSHA:    r.LocalSHA,
+			RemoteRef:   r.RemoteRef,
+			RemoteSHA:   r.RemoteSHA,
+			CommitCount: r.CommitCount,
+		})
+	}
+	return e, nil
+}
+
+// ClaimSpool moves the spool file aside for replaying, so events spooled
+// meanwhile start a new file. Returns every claimed file, including ones
+// left behind by a replay that was interrupted; replaying those again is
+// safe because inserts are upserts.
+func ClaimSpool(path string) ([]string, error) {
+	claim := fmt.Sprintf("%s.%d-%d%s", path, os.Getpid(), time.Now().UnixNano(), claimSuffix)
+	if err := os.Rename(path, claim); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("claim spool: %w", err)
+	}
+
+	return SpoolClaims(path)
+}
+
+// SpoolClaims returns the files claimed by replays that are running or were
+// interrupted.
+func SpoolClaims(path string) ([]string, error) {
+	claims, err := filepath.Glob(path + ".*" + claimSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpool_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	push := SpooledEvent{
+		Event: RepoEvent{
+			RepoID:    "github.com/user/repo",
+			RepoPath:  "/repo",
+			Commit:    "aaa111",
+			Branch:    "main",
+			Timestamp: at,
+			Status:    StatusPending,
+			Source:    SourcePrePush,
+		},
+		PushRefs: []PushRef{{
+			RemoteName: "origin", LocalRef: "refs/heads/main", LocalSHA: "aaa111",
+			RemoteRef: "refs/heads/main", RemoteSHA: "000000", CommitCount: 3,
+		}},
+		Error:     "database is locked",
+		SpooledAt: at,
+	}
+	rewrite := SpooledEvent{
+		Event: RepoEvent{
+			RepoID:    "github.com/user/repo",
+			Commit:    "bbb222",
+			Timestamp: at,
+			Status:    StatusPending,
+			Source:    SourcePostRewrite,
+		},
+		RewriteType: "amend",
+		Rewrites:    []CommitRewrite{{OldCommit: "aaa111", NewCommit: "bbb222"}},
+		SpooledAt:   at,
+	}
+
+	require.NoError(t, AppendSpool(path, push))
+	require.NoError(t, AppendSpool(path, rewrite))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	events, err := ReadSpool(path)
+	require.NoError(t, err)
+	require.Equal(t, []SpooledEvent{push, rewrite}, events)
+}
+
+func TestReadSpool_SkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n{\"source\":\"nope\"}\n\n"), 0600))
+	require.NoError(t, AppendSpool(path, SpooledEvent{
+		Event: RepoEvent{RepoID: "r", Commit: "c", Source: SourceManual, Status: StatusPending},
+	}))
+
+	events, err := ReadSpool(path)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	missing, err := ReadSpool(filepath.Join(t.TempDir(), "none.jsonl"))
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestReadSpoolClaim_KeepsBadLines(t *testing.T) {
+	dir := t.TempDir()
+	claim := filepath.Join(dir, "spool.jsonl.1-1.replay")
+	bad := BadSpoolPath(filepath.Join(dir, "spool.jsonl"))
+	require.NoError(t, os.WriteFile(claim, []byte("not json\n\n"), 0600))
+	require.NoError(t, AppendSpool(claim, SpooledEvent{
+		Event: RepoEvent{RepoID: "r", Commit: "c", Source: SourceManual, Status: StatusPending},
+	}))
+	require.NoError(t, os.WriteFile(bad, []byte("older\n"), 0600))
+
+	events, err := ReadSpoolClaim(claim, bad)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	kept, err := os.ReadFile(bad)
+	require.NoError(t, err)
+	require.Equal(t, "older\nnot json\n", string(kept))
+
+	// Without a place for them, the claim must not be read as done
+	_, err = ReadSpoolClaim(claim, filepath.Join(claim, "bad"))
+	require.Error(t, err)
+}
+
+func TestClaimSpool(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spool.jsonl")
+
+	claims, err := ClaimSpool(path)
+	require.NoError(t, err)
+	require.Empty(t, claims)
+
+	// A claim left by an interrupted replay is picked up with the new one
+	stale := path + ".1-1.replay"
+	require.NoError(t, os.WriteFile(stale, nil, 0600))
+	require.NoError(t, AppendSpool(path, SpooledEvent{
+		Event: RepoEvent{RepoID: "r", Commit: "c", Source: SourceManual},
+	}))
+
+	claims, err = ClaimSpool(path)
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+	require.Contains(t, claims, stale)
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,241 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// StatsDimension is what event statistics are broken down by.
+type StatsDimension string
+
+const (
+	StatsByDay     StatsDimension = "day"
+	StatsByWeek    StatsDimension = "week"
+	StatsByRepo    StatsDimension = "repo"
+	StatsByBranch  StatsDimension = "branch"
+	StatsBySource  StatsDimension = "source"
+	StatsByHour    StatsDimension = "hour"
+	StatsByWeekday StatsDimension = "weekday"
+)
+
+// StatsDimensions lists every dimension, in the order help text shows them.
+var StatsDimensions = []StatsDimension{
+	StatsByDay, StatsByWeek, StatsByRepo, StatsByBranch, StatsBySource, StatsByHour, StatsByWeekday,
+}
+
+// ParseStatsDimension parses a --group-by value.
+func ParseStatsDimension(s string) (StatsDimension, bool) {
+	for _, d := range StatsDimensions {
+		if strings.EqualFold(s, string(d)) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// statsKeys are the SQL expressions grouping events per dimension. Days,
+// hours and weekdays are in local time: timestamps are stored in UTC, and
+// a day cut from the text would start at midnight UTC. Weeks are folded
+// from days in Go.
+var statsKeys = map[StatsDimension]string{
+	StatsByDay:     "date(timestamp, 'localtime')",
+	StatsByRepo:    "repo_id",
+	StatsByBranch:  "COALESCE(branch, '')",
+	StatsBySource:  "CAST(source_id AS TEXT)",
+	StatsByHour:    "strftime('%H', timestamp, 'localtime')",
+	StatsByWeekday: "strftime('%w', timestamp, 'localtime')",
+}
+
+// StatsRow counts the events sharing one key of a dimension.
+type StatsRow struct {
+	// Key is a YYYY-MM-DD day, an ISO week such as 2025-W11, a repository
+	// id, a branch, a source name, an hour from 00 to 23 or a weekday name
+	Key string
+
+	Events int
+
+	// Commits counts distinct commits, leaving out checkouts and pushes
+	Commits int
+
+	// Insertions and Deletions are summed from metadata snapshots, so they
+	// only cover the Snapshots commits that have one
+	Insertions int
+	Deletions  int
+	Snapshots  int
+}
+
+// EventStats counts the events matching a filter per key of a dimension.
+// Day, week and hour rows are in order; the other dimensions are ordered by
+// event count, busiest first. Limit is ignored.
+func EventStats(db *sql.DB, filter EventFilter, by StatsDimension) ([]StatsRow, error) {
+	if by == StatsByWeek {
+		days, err := EventStats(db, filter, StatsByDay)
+		if err != nil {
+			return nil, err
+		}
+		return foldWeeks(days), nil
+	}
+
+	key, ok := statsKeys[by]
+	if !ok {
+		return nil, fmt.Errorf("unknown stats dimension %q", by)
+	}
+
+	rows, err := queryStats(db, filter, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch by {
+	case StatsBySource:
+		for i := range rows {
+			id, _ := strconv.Atoi(rows[i].Key)
+			rows[i].Key = Source(id).String()
+		}
+	case StatsByWeekday:
+		// Weeks start on Monday
+		sort.SliceStable(rows, func(i, j int) bool {
+			return (rows[i].Key[0]-'0'+6)%7 < (rows[j].Key[0]-'0'+6)%7
+		})
+		for i := range rows {
+			day, _ := strconv.Atoi(rows[i].Key)
+			rows[i].Key = time.Weekday(day).String()
+		}
+	}
+
+	if by == StatsByRepo || by == StatsByBranch || by == StatsBySource {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Events > rows[j].Events })
+	}
+	return rows, nil
+}
+
+// EventTotals counts every event matching a filter in a single row with
+// an empty key.
+func EventTotals(db *sql.DB, filter EventFilter) (StatsRow, error) {
+	rows, err := queryStats(db, filter, "''")
+	if err != nil || len(rows) == 0 {
+		return StatsRow{}, err
+	}
+	return rows[0], nil
+}
+
+// queryStats groups the filtered events by a key expression, ordered by
+// key. Commits recorded by several events (a manual record after the hook,
+// an amend) count once per key.
+func queryStats(db *sql.DB, filter EventFilter, key string) ([]StatsRow, error) {
+	clauses, args := eventFilterClauses(filter)
+	whereSQL := ""
+	if len(clauses) > 0 {
+		whereSQL = "WHERE " + strings.Join(clauses, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		WITH filtered AS (
+			SELECT %s AS key, repo_id, commit_hash, source_id
+			FROM repo_events
+			%s
+		),
+		counts AS (
+			SELECT key, COUNT(*) AS events FROM filtered GROUP BY key
+		),
+		commits AS (
+			SELECT DISTINCT key, repo_id, commit_hash FROM filtered
+			WHERE source_id NOT IN (?, ?)
+		),
+		changes AS (
+			SELECT c.key,
+				COUNT(*) AS commits,
+				COALESCE(SUM(m.insertions), 0) AS insertions,
+				COALESCE(SUM(m.deletions), 0) AS deletions,
+				COUNT(m.commit_hash) AS snapshots
+			FROM commits c
+			LEFT JOIN commit_metadata m ON m.repo_id = c.repo_id AND m.commit_hash = c.commit_hash
+			GROUP BY c.key
+		)
+		SELECT counts.key, counts.events,
+			COALESCE(changes.commits, 0), COALESCE(changes.insertions, 0),
+			COALESCE(changes.deletions, 0), COALESCE(changes.snapshots, 0)
+		FROM counts
+		LEFT JOIN changes ON changes.key = counts.key
+		ORDER BY counts.key
+	`, key, whereSQL)
+	args = append(args, int(SourcePostCheckout), int(SourcePrePush))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Error("store: event stats query failed: %v", err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []StatsRow
+	for rows.Next() {
+		var r StatsRow
+		if err := rows.Scan(&r.Key, &r.Events, &r.Commits, &r.Insertions, &r.Deletions, &r.Snapshots); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// foldWeeks adds day rows up into ISO weeks.
+func foldWeeks(days []StatsRow) []StatsRow {
+	var out []StatsRow
+	for _, day := range days {
+		t, err := time.Parse("2006-01-02", day.Key)
+		if err != nil {
+			continue
+		}
+		year, week := t.ISOWeek()
+		key := fmt.Sprintf("%d-W%02d", year, week)
+
+		if len(out) == 0 || out[len(out)-1].Key != key {
+			out = append(out, StatsRow{Key: key})
+		}
+		w := &out[len(out)-1]
+		w.Events += day.Events
+		w.Commits += day.Commits
+		w.Insertions += day.Insertions
+		w.Deletions += day.Deletions
+		w.Snapshots += day.Snapshots
+	}
+	return out
+}
+
+// Streak is a run of consecutive days with at least one event.
+type Streak struct {
+	Days  int
+	Start string // YYYY-MM-DD
+	End   string // YYYY-MM-DD
+}
+
+// LongestStreak finds the longest run of consecutive days in day rows, as
+// returned by EventStats with StatsByDay. The earliest run wins a tie.
+func LongestStreak(days []StatsRow) Streak {
+	var best, current Streak
+	var last time.Time
+	for _, day := range days {
+		t, err := time.Parse("2006-01-02", day.Key)
+		if err != nil {
+			continue
+		}
+		if current.Days > 0 && t.Equal(last.AddDate(0, 0, 1)) {
+			current.Days++
+			current.End = day.Key
+		} else {
+			current = Streak{Days: 1, Start: day.Key, End: day.Key}
+		}
+		last = t
+		if current.Days > best.Days {
+			best = current
+		}
+	}
+	return best
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func statsTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := newTestDB(t)
+
+	// Times are local and stored in UTC, as every writer does, so the keys
+	// come back as written here in any time zone
+	local := time.Local
+	insert := func(repo, commit, branch string, ts time.Time, source Source) {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID: repo, RepoPath: "/src/" + repo, Commit: commit, Branch: branch,
+			Timestamp: ts.UTC(), Status: StatusPending, Source: source,
+		}))
+	}
+
+	// Monday 2025-03-10 to Wednesday 2025-03-12, then Monday 2025-03-17.
+	// The 23:30 commit is on the 12th locally, whatever day it is in UTC.
+	insert("api", "a1", "main", time.Date(2025, 3, 10, 9, 0, 0, 0, local), SourcePostCommit)
+	insert("api", "a1", "main", time.Date(2025, 3, 10, 9, 5, 0, 0, local), SourceManual)
+	insert("api", "a1", "main", time.Date(2025, 3, 10, 9, 30, 0, 0, local), SourcePrePush)
+	insert("api", "a2", "feature", time.Date(2025, 3, 11, 10, 0, 0, 0, local), SourcePostCommit)
+	insert("web", "w1", "main", time.Date(2025, 3, 12, 23, 30, 0, 0, local), SourcePostCommit)
+	insert("web", "w1", "feature", time.Date(2025, 3, 17, 10, 15, 0, 0, local), SourcePostCheckout)
+
+	require.NoError(t, SaveCommitMetadata(db, "api", "a1", git.CommitMetadata{Insertions: 10, Deletions: 2}))
+	require.NoError(t, SaveCommitMetadata(db, "web", "w1", git.CommitMetadata{Insertions: 5, Deletions: 7}))
+	return db
+}
+
+func TestEventTotals(t *testing.T) {
+	db := statsTestDB(t)
+
+	totals, err := EventTotals(db, EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, StatsRow{Events: 6, Commits: 3, Insertions: 15, Deletions: 9, Snapshots: 2}, totals)
+
+	repo := "web"
+	totals, err = EventTotals(db, EventFilter{RepoID: &repo})
+	require.NoError(t, err)
+	require.Equal(t, 2, totals.Events)
+	require.Equal(t, 1, totals.Commits)
+	require.Equal(t, 5, totals.Insertions)
+
+	empty := newTestDB(t)
+	totals, err = EventTotals(empty, EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, StatsRow{}, totals)
+}
+
+func TestEventStats_Dimensions(t *testing.T) {
+	db := statsTestDB(t)
+
+	keys := func(by StatsDimension) []string {
+		rows, err := EventStats(db, EventFilter{}, by)
+		require.NoError(t, err)
+		var out []string
+		for _, r := range rows {
+			out = append(out, r.Key)
+		}
+		return out
+	}
+
+	require.Equal(t, []string{"2025-03-10", "2025-03-11", "2025-03-12", "2025-03-17"}, keys(StatsByDay))
+	require.Equal(t, []string{"2025-W11", "2025-W12"}, keys(StatsByWeek))
+	require.Equal(t, []string{"api", "web"}, keys(StatsByRepo))
+	require.Equal(t, []string{"main", "feature"}, keys(StatsByBranch))
+	require.Equal(t, []string{"POST-COMMIT", "POST-CHECKOUT", "PRE-PUSH", "MANUAL"}, keys(StatsBySource))
+	require.Equal(t, []string{"09", "10", "23"}, keys(StatsByHour))
+	require.Equal(t, []string{"Monday", "Tuesday", "Wednesday"}, keys(StatsByWeekday))
+
+	weeks, err := EventStats(db, EventFilter{}, StatsByWeek)
+	require.NoError(t, err)
+	require.Equal(t, StatsRow{Key: "2025-W11", Events: 5, Commits: 3, Insertions: 15, Deletions: 9, Snapshots: 2}, weeks[0])
+	require.Equal(t, StatsRow{Key: "2025-W12", Events: 1}, weeks[1])
+
+	days, err := EventStats(db, EventFilter{}, StatsByDay)
+	require.NoError(t, err)
+	require.Equal(t, 3, days[0].Events)
+	require.Equal(t, 1, days[0].Commits, "the same commit recorded three times counts once")
+}
+
+func TestParseStatsDimension(t *testing.T) {
+	d, ok := ParseStatsDimension("Weekday")
+	require.True(t, ok)
+	require.Equal(t, StatsByWeekday, d)
+
+	_, ok = ParseStatsDimension("month")
+	require.False(t, ok)
+}
+
+func TestLongestStreak(t *testing.T) {
+	days := func(keys ...string) []StatsRow {
+		var out []StatsRow
+		for _, k := range keys {
+			out = append(out, StatsRow{Key: k, Events: 1})
+		}
+		return out
+	}
+
+	require.Equal(t, Streak{}, LongestStreak(nil))
+	require.Equal(t, Streak{Days: 1, Start: "2025-03-01", End: "2025-03-01"}, LongestStreak(days("2025-03-01", "2025-03-05")))
+	require.Equal(t, Streak{Days: 3, Start: "2025-02-27", End: "2025-03-01"},
+		LongestStreak(days("2025-02-20", "2025-02-21", "2025-02-27", "2025-02-28", "2025-03-01", "2025-03-03")))
+}
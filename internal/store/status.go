@@ -12,4 +12,5 @@ const (
 	StatusExported = domain.StatusExported
 	StatusOrphaned = domain.StatusOrphaned
 	StatusSkipped  = domain.StatusSkipped
+	StatusImported = domain.StatusImported
 )
@@ -24,6 +24,22 @@ type Store struct {
 // New creates a new Store with the given database path.
 // Runs migrations automatically.
 func New(path string) (*Store, error) {
+	s, err := NewUnmigrated(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = migrations.Run(s.db); err != nil {
+		_ = s.Close()
+		return nil, fmt.Errorf("run migrations: %w", err)
+	}
+
+	return s, nil
+}
+
+// NewUnmigrated opens the database without running migrations, so its schema
+// can be inspected or downgraded even when this fp cannot use it.
+func NewUnmigrated(path string) (*Store, error) {
 	db, err := sql.Open("sqlite3", path)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
@@ -42,11 +58,6 @@ func New(path string) (*Store, error) {
 
 	setDBPermissions(path)
 
-	if err = migrations.Run(db); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("run migrations: %w", err)
-	}
-
 	return &Store{db: db, path: path}, nil
 }
 
@@ -131,10 +142,15 @@ func setDBPermissions(path string) {
 func (s *Store) Insert(event domain.RepoEvent) error {
 	_, err := s.db.Exec(
 		`INSERT INTO repo_events
-		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id,
+		  previous_commit, previous_branch, branch_switch)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(repo_id, commit_hash, source_id)
-		 DO UPDATE SET timestamp = excluded.timestamp`,
+		 DO UPDATE SET
+			timestamp = excluded.timestamp,
+			previous_commit = excluded.previous_commit,
+			previous_branch = excluded.previous_branch,
+			branch_switch = excluded.branch_switch`,
 		event.RepoID.String(),
 		event.RepoPath,
 		event.Commit,
@@ -142,22 +158,16 @@ func (s *Store) Insert(event domain.RepoEvent) error {
 		event.Timestamp.Format(time.RFC3339),
 		int(event.Status),
 		int(event.Source),
+		nullString(event.PreviousCommit),
+		nullString(event.PreviousBranch),
+		boolToInt(event.BranchSwitch),
 	)
 	return err
 }
 
 // List returns events matching the given filter.
 func (s *Store) List(filter domain.EventFilter) ([]domain.RepoEvent, error) {
-	base := `
-		SELECT
-			id,
-			repo_id,
-			repo_path,
-			commit_hash,
-			branch,
-			timestamp,
-			status_id,
-			source_id
+	base := `SELECT` + repoEventColumns + `
 		FROM repo_events
 	`
 
@@ -178,12 +188,12 @@ func (s *Store) List(filter domain.EventFilter) ([]domain.RepoEvent, error) {
 
 	if filter.Since != nil {
 		filterClauses = append(filterClauses, "timestamp >= ?")
-		filterArgs = append(filterArgs, filter.Since.Format(time.RFC3339))
+		filterArgs = append(filterArgs, filter.Since.UTC().Format(time.RFC3339))
 	}
 
 	if filter.Until != nil {
 		filterClauses = append(filterClauses, "timestamp <= ?")
-		filterArgs = append(filterArgs, filter.Until.Format(time.RFC3339))
+		filterArgs = append(filterArgs, filter.Until.UTC().Format(time.RFC3339))
 	}
 
 	if !filter.RepoID.IsEmpty() {
@@ -273,16 +283,7 @@ func (s *Store) GetMaxID() (int64, error) {
 
 // ListSince returns events with ID greater than the given ID.
 func (s *Store) ListSince(id int64) ([]domain.RepoEvent, error) {
-	query := `
-		SELECT
-			id,
-			repo_id,
-			repo_path,
-			commit_hash,
-			branch,
-			timestamp,
-			status_id,
-			source_id
+	query := `SELECT` + repoEventColumns + `
 		FROM repo_events
 		WHERE id > ?
 		ORDER BY id ASC
@@ -324,11 +325,16 @@ func (s *Store) MigrateRepoID(oldID, newID domain.RepoID) (int64, error) {
 // scanRepoEvent scans a single row into a domain.RepoEvent.
 func (s *Store) scanRepoEvent(rows *sql.Rows) (domain.RepoEvent, error) {
 	var (
-		e        domain.RepoEvent
-		repoID   string
-		ts       string
-		statusID int
-		sourceID int
+		e              domain.RepoEvent
+		repoID         string
+		ts             string
+		statusID       int
+		sourceID       int
+		previousCommit sql.NullString
+		previousBranch sql.NullString
+		branchSwitch   int
+		supersededBy   sql.NullString
+		device         sql.NullString
 	)
 
 	if err := rows.Scan(
@@ -340,6 +346,11 @@ func (s *Store) scanRepoEvent(rows *sql.Rows) (domain.RepoEvent, error) {
 		&ts,
 		&statusID,
 		&sourceID,
+		&previousCommit,
+		&previousBranch,
+		&branchSwitch,
+		&supersededBy,
+		&device,
 	); err != nil {
 		return domain.RepoEvent{}, err
 	}
@@ -353,6 +364,11 @@ func (s *Store) scanRepoEvent(rows *sql.Rows) (domain.RepoEvent, error) {
 	e.Timestamp = t
 	e.Status = domain.EventStatus(statusID)
 	e.Source = domain.EventSource(sourceID)
+	e.PreviousCommit = previousCommit.String
+	e.PreviousBranch = previousBranch.String
+	e.BranchSwitch = branchSwitch != 0
+	e.SupersededBy = supersededBy.String
+	e.Device = device.String
 
 	return e, nil
 }
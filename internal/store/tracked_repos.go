@@ -1,6 +1,8 @@
 package store
 
 import (
+	"database/sql"
+
 	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/repo"
 )
@@ -8,16 +10,26 @@ import (
 // RegisteredRepo represents a repository where fp hooks are installed.
 type RegisteredRepo struct {
 	Path     string
+	RepoID   string
 	AddedAt  string
 	LastSeen string
+	// RemoteURL is set for clones identified by root commit or path
+	// (repo_identity), whose id no longer shows the remote.
+	RemoteURL string
 }
 
 // AddRepo registers a repository when hooks are installed.
 func (s *Store) AddRepo(repoPath string) error {
 	remoteURL, _ := git.OriginURL(repoPath)
-	repoID, _ := repo.DeriveID(remoteURL, repoPath)
+	legacyID, _ := repo.DeriveID(remoteURL, repoPath)
+
+	// Clones identified by root commit or path keep their mapped id
+	repoID, err := MappedRepoID(s.db, repoPath, string(legacyID))
+	if err != nil {
+		return err
+	}
 
-	_, err := s.db.Exec(`
+	_, err = s.db.Exec(`
 		INSERT INTO tracked_repos (repo_id, repo_path, last_seen)
 		VALUES (?, ?, datetime('now'))
 		ON CONFLICT(repo_path) DO UPDATE SET
@@ -36,9 +48,10 @@ func (s *Store) RemoveRepo(repoPath string) error {
 // ListRepos returns all repositories with hooks installed.
 func (s *Store) ListRepos() ([]RegisteredRepo, error) {
 	rows, err := s.db.Query(`
-		SELECT repo_path, added_at, last_seen
-		FROM tracked_repos
-		ORDER BY repo_path
+		SELECT t.repo_path, t.repo_id, t.added_at, t.last_seen, i.remote_url
+		FROM tracked_repos t
+		LEFT JOIN repo_identities i ON i.repo_path = t.repo_path
+		ORDER BY t.repo_path
 	`)
 	if err != nil {
 		return nil, err
@@ -47,10 +60,14 @@ func (s *Store) ListRepos() ([]RegisteredRepo, error) {
 
 	var repos []RegisteredRepo
 	for rows.Next() {
-		var r RegisteredRepo
-		if err := rows.Scan(&r.Path, &r.AddedAt, &r.LastSeen); err != nil {
+		var (
+			r         RegisteredRepo
+			remoteURL sql.NullString
+		)
+		if err := rows.Scan(&r.Path, &r.RepoID, &r.AddedAt, &r.LastSeen, &remoteURL); err != nil {
 			return nil, err
 		}
+		r.RemoteURL = remoteURL.String
 		repos = append(repos, r)
 	}
 	return repos, rows.Err()
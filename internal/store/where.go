@@ -0,0 +1,133 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/where"
+)
+
+// whereColumns are the SQL expressions each field compares. Hours, weekdays
+// and dates are in local time, as where.Match reads them; timestamps are
+// stored in UTC.
+var whereColumns = map[where.Field]string{
+	where.FieldBranch:  "COALESCE(branch, '')",
+	where.FieldSource:  "source_id",
+	where.FieldStatus:  "status_id",
+	where.FieldHour:    "CAST(strftime('%H', timestamp, 'localtime') AS INTEGER)",
+	where.FieldWeekday: "CAST(strftime('%w', timestamp, 'localtime') AS INTEGER)",
+	where.FieldDate:    "date(timestamp, 'localtime')",
+}
+
+// compileWhere turns a parsed --where expression into a condition on
+// repo_events. Values only ever travel as arguments; the SQL text comes
+// from fixed fragments chosen by field and operator.
+func compileWhere(e where.Expr) (string, []any) {
+	switch e := e.(type) {
+	case where.And:
+		left, leftArgs := compileWhere(e.Left)
+		right, rightArgs := compileWhere(e.Right)
+		return "(" + left + " AND " + right + ")", append(leftArgs, rightArgs...)
+	case where.Or:
+		left, leftArgs := compileWhere(e.Left)
+		right, rightArgs := compileWhere(e.Right)
+		return "(" + left + " OR " + right + ")", append(leftArgs, rightArgs...)
+	case where.Not:
+		inner, args := compileWhere(e.Expr)
+		return "NOT " + inner, args
+	case where.Predicate:
+		return compilePredicate(e)
+	}
+	// Parse never returns anything else
+	return "0", nil
+}
+
+func compilePredicate(p where.Predicate) (string, []any) {
+	switch p.Op {
+	case where.OpLt, where.OpLe, where.OpGt, where.OpGe:
+		return whereColumns[p.Field] + " " + string(p.Op) + " ?", []any{whereArg(p.Field, p.Values[0])}
+	}
+
+	// Negative operators negate the positive condition, so "tag != wip"
+	// keeps untagged events, as where.Match does
+	negate := false
+	glob := false
+	switch p.Op {
+	case where.OpNe, where.OpNotIn:
+		negate = true
+	case where.OpNotGlob:
+		negate, glob = true, true
+	case where.OpGlob:
+		glob = true
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	for _, v := range p.Values {
+		cond, condArgs := compileValue(p.Field, v, glob)
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+
+	sql := "(" + strings.Join(conds, " OR ") + ")"
+	if negate {
+		sql = "NOT " + sql
+	}
+	return sql, args
+}
+
+// compileValue returns the condition for a field equal to, or matching the
+// glob of, one value.
+func compileValue(field where.Field, value string, glob bool) (string, []any) {
+	if glob {
+		// where.Glob also matches any /-separated tail of the value
+		pattern := strings.ReplaceAll(value, "[", "[[]")
+		match := func(column string) string {
+			return "(" + column + " GLOB ? OR " + column + " GLOB ?)"
+		}
+		args := []any{pattern, "*/" + pattern}
+
+		switch field {
+		case where.FieldRepo:
+			return "(" + match("repo_id") +
+				" OR repo_id IN (SELECT repo_id FROM repo_meta WHERE " + match("alias") + "))", append(args, args...)
+		case where.FieldTag:
+			return "id IN (SELECT event_id FROM event_tags WHERE " + match("tag") + ")", args
+		case where.FieldCommit:
+			return match("commit_hash"), args
+		default:
+			return match(whereColumns[field]), args
+		}
+	}
+
+	switch field {
+	case where.FieldRepo:
+		return "(repo_id = ? OR repo_id IN (SELECT repo_id FROM repo_meta WHERE alias = ?))", []any{value, value}
+	case where.FieldTag:
+		return "id IN (SELECT event_id FROM event_tags WHERE tag = ?)", []any{value}
+	case where.FieldCommit:
+		// Hashes match by prefix, as everywhere else
+		return "substr(commit_hash, 1, ?) = ?", []any{len(value), value}
+	default:
+		return whereColumns[field] + " = ?", []any{whereArg(field, value)}
+	}
+}
+
+// whereArg converts a normalized value to the type its column stores.
+func whereArg(field where.Field, value string) any {
+	switch field {
+	case where.FieldSource:
+		source, _ := domain.ParseEventSource(value)
+		return int(source)
+	case where.FieldStatus:
+		status, _ := domain.ParseEventStatus(value)
+		return int(status)
+	case where.FieldHour, where.FieldWeekday:
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	return value
+}
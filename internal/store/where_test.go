@@ -0,0 +1,76 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/where"
+)
+
+func TestListEvents_Where(t *testing.T) {
+	db := statsTestDB(t)
+	require.NoError(t, SetRepoAlias(db, "web", "frontend", time.Now()))
+
+	all, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	for _, e := range all {
+		if e.Commit == "a2" {
+			_, err := AddTag(db, e.ID, "billable", time.Now())
+			require.NoError(t, err)
+		}
+	}
+
+	tests := []struct {
+		expr    string
+		commits []string // newest first, as ListEvents orders them
+	}{
+		{"repo in (api, frontend)", []string{"w1", "w1", "a2", "a1", "a1", "a1"}},
+		{"repo = frontend", []string{"w1", "w1"}},
+		{`repo ~ "front*"`, []string{"w1", "w1"}},
+		{"repo != frontend", []string{"a2", "a1", "a1", "a1"}},
+		{"branch ~ feat* and source != post-checkout", []string{"a2"}},
+		{"not branch = main", []string{"w1", "a2"}},
+		{"hour >= 10 and hour < 23", []string{"w1", "a2"}},
+		{"hour = 23", []string{"w1"}},
+		{"weekday in (mon)", []string{"w1", "a1", "a1", "a1"}},
+		{"date = 2025-03-12", []string{"w1"}},
+		{"source in (manual, pre-push)", []string{"a1", "a1"}},
+		{"commit = a", []string{"a2", "a1", "a1", "a1"}},
+		{"tag = billable", []string{"a2"}},
+		{"tag != billable and repo = api", []string{"a1", "a1", "a1"}},
+		{"branch = \"main' OR 1=1 --\"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := where.Parse(tt.expr)
+			require.NoError(t, err)
+
+			events, err := ListEvents(db, EventFilter{Where: e})
+			require.NoError(t, err)
+			var commits []string
+			for _, ev := range events {
+				commits = append(commits, ev.Commit)
+			}
+			require.Equal(t, tt.commits, commits)
+		})
+	}
+}
+
+func TestListEvents_WhereCombinesWithFilter(t *testing.T) {
+	db := statsTestDB(t)
+
+	e, err := where.Parse("branch = main")
+	require.NoError(t, err)
+	repo := "api"
+	events, err := ListEvents(db, EventFilter{RepoID: &repo, Where: e})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	totals, err := EventTotals(db, EventFilter{Where: e})
+	require.NoError(t, err)
+	require.Equal(t, 4, totals.Events)
+	require.Equal(t, 2, totals.Commits)
+}
@@ -2,18 +2,56 @@ package store
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/footprint-tools/cli/internal/log"
 )
 
 func InsertEvent(db *sql.DB, e RepoEvent) error {
+	return insertEvent(db, e)
+}
+
+// RecordEvent inserts a hook event together with the rewrite pairs and
+// pushed refs it reported, in one transaction: when it fails nothing was
+// written, so the whole event can be spooled and replayed. Returns the
+// number of events the rewrites superseded.
+func RecordEvent(db *sql.DB, e RepoEvent, rewriteType string, rewrites []CommitRewrite, refs []PushRef) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := insertEvent(tx, e); err != nil {
+		return 0, err
+	}
+	superseded, err := recordRewrites(tx, e, rewriteType, rewrites)
+	if err != nil {
+		return 0, fmt.Errorf("save rewrites: %w", err)
+	}
+	if err := recordPushRefs(tx, e, refs); err != nil {
+		return 0, fmt.Errorf("save pushed refs: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return superseded, nil
+}
+
+func insertEvent(db execer, e RepoEvent) error {
 	_, err := db.Exec(
 		`INSERT INTO repo_events
-		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id,
+		  previous_commit, previous_branch, branch_switch)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(repo_id, commit_hash, source_id)
-		 DO UPDATE SET timestamp = excluded.timestamp`,
+		 DO UPDATE SET
+			timestamp = excluded.timestamp,
+			previous_commit = excluded.previous_commit,
+			previous_branch = excluded.previous_branch,
+			branch_switch = excluded.branch_switch`,
 		e.RepoID,
 		e.RepoPath,
 		e.Commit,
@@ -21,6 +59,9 @@ func InsertEvent(db *sql.DB, e RepoEvent) error {
 		e.Timestamp.Format(time.RFC3339),
 		int(e.Status),
 		int(e.Source),
+		nullString(e.PreviousCommit),
+		nullString(e.PreviousBranch),
+		boolToInt(e.BranchSwitch),
 	)
 	if err != nil {
 		log.Error("store: insert event failed: %v (repo=%s, commit=%.7s)", err, e.RepoID, e.Commit)
@@ -67,3 +108,16 @@ func CountOrphanedEvents(db *sql.DB) (int64, error) {
 	}
 	return count, nil
 }
+
+// nullString converts an empty string to a SQL NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// boolToInt converts a bool to the 0/1 integer SQLite uses for booleans.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -181,6 +181,53 @@ func TestInsertEvent_OnConflictUpdatesTimestamp(t *testing.T) {
 	require.True(t, event2.Timestamp.Equal(parsedTime), "timestamp should be updated to event2's timestamp")
 }
 
+func TestInsertEvent_CheckoutContextRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+
+	event := RepoEvent{
+		RepoID:         "github.com/user/repo",
+		RepoPath:       "/path/to/repo",
+		Commit:         "abc123",
+		Branch:         "feature",
+		Timestamp:      time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:         StatusPending,
+		Source:         SourcePostCheckout,
+		PreviousCommit: "def456",
+		PreviousBranch: "main",
+		BranchSwitch:   true,
+	}
+	require.NoError(t, InsertEvent(db, event))
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "def456", events[0].PreviousCommit)
+	require.Equal(t, "main", events[0].PreviousBranch)
+	require.True(t, events[0].BranchSwitch)
+
+	// Events without checkout context read back as empty values
+	require.NoError(t, InsertEvent(db, RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/path/to/repo",
+		Commit:    "xyz789",
+		Branch:    "main",
+		Timestamp: time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    SourcePostCommit,
+	}))
+
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		if e.Commit == "xyz789" {
+			require.Empty(t, e.PreviousCommit)
+			require.Empty(t, e.PreviousBranch)
+			require.False(t, e.BranchSwitch)
+		}
+	}
+}
+
 func TestInsertEvent_DifferentSourceCreatesNewRow(t *testing.T) {
 	db := newTestDB(t)
 
@@ -359,3 +406,33 @@ func TestCountOrphanedEvents(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int64(2), count)
 }
+
+func TestRecordEvent(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	old := RepoEvent{RepoID: "github.com/user/repo", RepoPath: "/r", Commit: "aaa111", Branch: "main",
+		Timestamp: ts, Status: StatusPending, Source: SourcePostCommit}
+	require.NoError(t, InsertEvent(db, old))
+
+	rewrite := old
+	rewrite.Commit, rewrite.Source, rewrite.Timestamp = "bbb222", SourcePostRewrite, ts.Add(time.Minute)
+	superseded, err := RecordEvent(db, rewrite, "amend", []CommitRewrite{{OldCommit: "aaa111", NewCommit: "bbb222"}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), superseded)
+
+	// A push whose refs cannot be saved leaves no event behind either
+	_, err = db.Exec(`DROP TABLE push_refs`)
+	require.NoError(t, err)
+	push := rewrite
+	push.Source, push.Timestamp = SourcePrePush, ts.Add(2*time.Minute)
+	_, err = RecordEvent(db, push, "", nil, []PushRef{{RemoteName: "origin", LocalRef: "refs/heads/main", LocalSHA: "bbb222", RemoteRef: "refs/heads/main"}})
+	require.ErrorContains(t, err, "save pushed refs")
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		require.NotEqual(t, SourcePrePush, e.Source)
+	}
+}
@@ -33,6 +33,7 @@ var (
 	color5Style  lipgloss.Style
 	color6Style  lipgloss.Style
 	color7Style  lipgloss.Style
+	uiDimStyle   lipgloss.Style
 )
 
 // Init initializes the style package with the given enabled state and config.
@@ -86,6 +87,7 @@ func initStyles(colors ColorConfig) {
 	color5Style = makeStyle(colors.Color5)
 	color6Style = makeStyle(colors.Color6)
 	color7Style = makeStyle(colors.Color7)
+	uiDimStyle = makeStyle(colors.UIDim)
 }
 
 // makeStyle creates a lipgloss style from a color value.
@@ -131,6 +133,15 @@ func Muted(text string) string { return render(mutedStyle, text) }
 // Border styles text for interactive delimiters (scrollbars, card borders, etc.).
 func Border(text string) string { return render(borderStyle, text) }
 
+// Heat styles a heatmap cell: days with activity in the success color and
+// empty days in the dimmed UI color.
+func Heat(level int, text string) string {
+	if level == 0 {
+		return render(uiDimStyle, text)
+	}
+	return render(successStyle, text)
+}
+
 // Color1 through Color7 are neutral colors for visual distinction only.
 func Color1(text string) string { return render(color1Style, text) }
 func Color2(text string) string { return render(color2Style, text) }
@@ -0,0 +1,106 @@
+package where
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	quoted bool // a quoted word is always a value, never a keyword
+	col    int  // 1-based, for error messages
+}
+
+// String describes a token for error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenWord:
+		if t.quoted {
+			return fmt.Sprintf("%q", t.text)
+		}
+		return "'" + t.text + "'"
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+// wordBreaks end an unquoted word.
+const wordBreaks = "()=,!~<>\"' \t\r\n"
+
+// lex splits an expression into tokens, always ending with tokenEOF.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		col := i + 1
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", col: col})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", col: col})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", col: col})
+			i++
+
+		case strings.ContainsRune("=!~<>", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' || r == '!' && i+1 < len(runes) && runes[i+1] == '~' {
+				op += string(runes[i+1])
+			}
+			switch Op(op) {
+			case OpEq, opEqDouble, OpNe, OpGlob, OpNotGlob, OpLt, OpLe, OpGt, OpGe:
+			default:
+				return nil, fmt.Errorf("unknown operator '%s' at column %d", op, col)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, col: col})
+			i += len([]rune(op))
+
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated string at column %d", col)
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: b.String(), quoted: true, col: col})
+			i = j + 1
+
+		default:
+			j := i
+			for j < len(runes) && !strings.ContainsRune(wordBreaks, runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:j]), col: col})
+			i = j
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, col: len(runes) + 1}), nil
+}
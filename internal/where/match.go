@@ -0,0 +1,179 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/domain"
+)
+
+// Event holds the fields of an event an expression can test.
+type Event struct {
+	RepoID    string
+	RepoAlias string
+	Branch    string
+	Commit    string
+	Source    domain.EventSource
+	Status    domain.EventStatus
+	Tags      []string
+	Timestamp time.Time // hour, weekday and date are read in local time
+}
+
+// Match reports whether an event matches an expression. It agrees with
+// the SQL the store compiles from the same expression.
+func Match(e Expr, ev Event) bool {
+	switch e := e.(type) {
+	case And:
+		return Match(e.Left, ev) && Match(e.Right, ev)
+	case Or:
+		return Match(e.Left, ev) || Match(e.Right, ev)
+	case Not:
+		return !Match(e.Expr, ev)
+	case Predicate:
+		return matchPredicate(e, ev)
+	}
+	return false
+}
+
+func matchPredicate(p Predicate, ev Event) bool {
+	var candidates []string
+	switch p.Field {
+	case FieldRepo:
+		candidates = []string{ev.RepoID}
+		if ev.RepoAlias != "" {
+			candidates = append(candidates, ev.RepoAlias)
+		}
+	case FieldBranch:
+		candidates = []string{ev.Branch}
+	case FieldCommit:
+		candidates = []string{ev.Commit}
+	case FieldSource:
+		candidates = []string{ev.Source.String()}
+	case FieldStatus:
+		candidates = []string{ev.Status.String()}
+	case FieldTag:
+		candidates = ev.Tags
+	case FieldHour:
+		return compareInt(p, ev.Timestamp.Local().Hour())
+	case FieldWeekday:
+		candidates = []string{strconv.Itoa(int(ev.Timestamp.Local().Weekday()))}
+	case FieldDate:
+		return compareString(p, ev.Timestamp.Local().Format("2006-01-02"))
+	}
+
+	// Negative operators hold when no candidate matches the positive one,
+	// so "tag != wip" keeps untagged events and "repo != x" checks aliases
+	positive := p
+	switch p.Op {
+	case OpNe:
+		positive.Op = OpEq
+	case OpNotGlob:
+		positive.Op = OpGlob
+	case OpNotIn:
+		positive.Op = OpIn
+	}
+	found := false
+	for _, c := range candidates {
+		if matchValue(positive, c) {
+			found = true
+			break
+		}
+	}
+	if positive.Op != p.Op {
+		return !found
+	}
+	return found
+}
+
+// matchValue applies a positive operator (=, ~ or in) to one value.
+func matchValue(p Predicate, value string) bool {
+	for _, want := range p.Values {
+		switch {
+		case p.Op == OpGlob:
+			if Glob(want, value) {
+				return true
+			}
+		case p.Field == FieldCommit:
+			if want != "" && strings.HasPrefix(value, want) {
+				return true
+			}
+		case value == want:
+			return true
+		}
+	}
+	return false
+}
+
+func compareInt(p Predicate, value int) bool {
+	switch p.Op {
+	case OpIn, OpNotIn:
+		found := false
+		for _, v := range p.Values {
+			if n, _ := strconv.Atoi(v); n == value {
+				found = true
+			}
+		}
+		return found == (p.Op == OpIn)
+	}
+	want, _ := strconv.Atoi(p.Values[0])
+	return compare(p.Op, value-want)
+}
+
+func compareString(p Predicate, value string) bool {
+	return compare(p.Op, strings.Compare(value, p.Values[0]))
+}
+
+// compare applies an ordering operator to the sign of value - want.
+func compare(op Op, diff int) bool {
+	switch op {
+	case OpEq:
+		return diff == 0
+	case OpNe:
+		return diff != 0
+	case OpLt:
+		return diff < 0
+	case OpLe:
+		return diff <= 0
+	case OpGt:
+		return diff > 0
+	case OpGe:
+		return diff >= 0
+	}
+	return false
+}
+
+// Glob reports whether value matches pattern, where * matches any run of
+// characters (slashes included) and ? one character. A pattern also
+// matches any /-separated tail of the value, so acme/* matches
+// github.com/acme/api and feature/* matches the feature branches.
+func Glob(pattern, value string) bool {
+	if globMatch([]rune(pattern), []rune(value)) {
+		return true
+	}
+	for i, r := range value {
+		if r == '/' && globMatch([]rune(pattern), []rune(value[i+1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+func globMatch(pattern, value []rune) bool {
+	if len(pattern) == 0 {
+		return len(value) == 0
+	}
+	switch pattern[0] {
+	case '*':
+		for i := 0; i <= len(value); i++ {
+			if globMatch(pattern[1:], value[i:]) {
+				return true
+			}
+		}
+		return false
+	case '?':
+		return len(value) > 0 && globMatch(pattern[1:], value[1:])
+	default:
+		return len(value) > 0 && value[0] == pattern[0] && globMatch(pattern[1:], value[1:])
+	}
+}
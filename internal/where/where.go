@@ -0,0 +1,353 @@
+// Package where parses the --where filter language for events, e.g.
+//
+//	repo ~ "acme/*" and branch != main and source in (post-commit, backfill)
+//
+// An expression is parsed once and then either compiled to parameterized
+// SQL by the store or matched against loaded events by the interactive
+// views, so both read the same language the same way.
+package where
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/domain"
+)
+
+// Expr is a parsed expression: an And, Or, Not or Predicate.
+type Expr interface {
+	expr()
+}
+
+// And matches events matching both sides.
+type And struct{ Left, Right Expr }
+
+// Or matches events matching either side.
+type Or struct{ Left, Right Expr }
+
+// Not matches events the inner expression does not.
+type Not struct{ Expr Expr }
+
+// Predicate compares one field of an event with one or more values.
+type Predicate struct {
+	Field Field
+	Op    Op
+
+	// Values are normalized: source and status names upper case as the
+	// domain spells them, weekdays 0 (Sunday) to 6, hours 0 to 23 and
+	// dates YYYY-MM-DD. In and NotIn have one or more, the others one.
+	Values []string
+}
+
+func (And) expr()       {}
+func (Or) expr()        {}
+func (Not) expr()       {}
+func (Predicate) expr() {}
+
+// Field is an event property an expression can test.
+type Field string
+
+const (
+	FieldRepo    Field = "repo"    // repository id or alias
+	FieldBranch  Field = "branch"  // branch name
+	FieldCommit  Field = "commit"  // commit hash; = matches a prefix
+	FieldSource  Field = "source"  // post-commit, pre-push, ...
+	FieldStatus  Field = "status"  // pending, exported, ...
+	FieldTag     Field = "tag"     // any tag of the event
+	FieldHour    Field = "hour"    // hour of day, 0 to 23, local time
+	FieldWeekday Field = "weekday" // mon to sun
+	FieldDate    Field = "date"    // YYYY-MM-DD, local time
+)
+
+// Fields lists every field, in the order help and errors show them.
+var Fields = []Field{
+	FieldRepo, FieldBranch, FieldCommit, FieldSource, FieldStatus, FieldTag, FieldHour, FieldWeekday, FieldDate,
+}
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEq       Op = "="
+	OpNe       Op = "!="
+	OpGlob     Op = "~"
+	OpNotGlob  Op = "!~"
+	OpLt       Op = "<"
+	OpLe       Op = "<="
+	OpGt       Op = ">"
+	OpGe       Op = ">="
+	OpIn       Op = "in"
+	OpNotIn    Op = "not in"
+	opEqDouble Op = "=="
+)
+
+// fieldOps lists the operators each kind of field accepts.
+var fieldOps = map[Field][]Op{
+	FieldRepo:    {OpEq, OpNe, OpGlob, OpNotGlob, OpIn, OpNotIn},
+	FieldBranch:  {OpEq, OpNe, OpGlob, OpNotGlob, OpIn, OpNotIn},
+	FieldCommit:  {OpEq, OpNe, OpGlob, OpNotGlob, OpIn, OpNotIn},
+	FieldTag:     {OpEq, OpNe, OpGlob, OpNotGlob, OpIn, OpNotIn},
+	FieldSource:  {OpEq, OpNe, OpIn, OpNotIn},
+	FieldStatus:  {OpEq, OpNe, OpIn, OpNotIn},
+	FieldWeekday: {OpEq, OpNe, OpIn, OpNotIn},
+	FieldHour:    {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe, OpIn, OpNotIn},
+	FieldDate:    {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+}
+
+// Parse parses an expression. Words and, or, not and in are keywords in
+// any case; values with spaces or symbols are quoted with " or '.
+func Parse(input string) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, errors.New("empty expression")
+	}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%s at column %d", fmt.Sprintf(format, args...), t.col)
+}
+
+// keyword reports whether the next token is the given keyword, consuming
+// it if so.
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenWord && !t.quoted && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (Expr, error) {
+	if p.keyword("not") {
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{e}, nil
+	}
+	if p.peek().kind == tokenLParen {
+		p.next()
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, p.errorf(t, "expected ) but found %s", t)
+		}
+		return e, nil
+	}
+	return p.predicate()
+}
+
+func (p *parser) predicate() (Expr, error) {
+	t := p.next()
+	if t.kind != tokenWord || t.quoted {
+		return nil, p.errorf(t, "expected a field but found %s", t)
+	}
+	field := Field(strings.ToLower(t.text))
+	if _, ok := fieldOps[field]; !ok {
+		return nil, p.errorf(t, "unknown field '%s': use %s", t.text, fieldList())
+	}
+
+	opToken := p.peek()
+	var op Op
+	switch {
+	case opToken.kind == tokenOp:
+		p.next()
+		op = Op(opToken.text)
+		if op == opEqDouble {
+			op = OpEq
+		}
+	case p.keyword("in"):
+		op = OpIn
+	case p.keyword("not"):
+		if !p.keyword("in") {
+			return nil, p.errorf(p.peek(), "expected 'in' after 'not'")
+		}
+		op = OpNotIn
+	default:
+		return nil, p.errorf(opToken, "expected an operator after '%s' but found %s", t.text, opToken)
+	}
+	if !allowed(field, op) {
+		return nil, p.errorf(opToken, "'%s' does not apply to %s: use %s", op, field, opList(field))
+	}
+
+	var raw []token
+	if op == OpIn || op == OpNotIn {
+		if open := p.next(); open.kind != tokenLParen {
+			return nil, p.errorf(open, "expected ( after '%s'", op)
+		}
+		for {
+			v := p.next()
+			if v.kind != tokenWord {
+				return nil, p.errorf(v, "expected a value but found %s", v)
+			}
+			raw = append(raw, v)
+			sep := p.next()
+			if sep.kind == tokenRParen {
+				break
+			}
+			if sep.kind != tokenComma {
+				return nil, p.errorf(sep, "expected , or ) but found %s", sep)
+			}
+		}
+	} else {
+		v := p.next()
+		if v.kind != tokenWord {
+			return nil, p.errorf(v, "expected a value after '%s' but found %s", op, v)
+		}
+		raw = append(raw, v)
+	}
+
+	pred := Predicate{Field: field, Op: op}
+	for _, v := range raw {
+		value, err := normalize(field, v.text)
+		if err != nil {
+			return nil, p.errorf(v, "%v", err)
+		}
+		pred.Values = append(pred.Values, value)
+	}
+	return pred, nil
+}
+
+func allowed(field Field, op Op) bool {
+	for _, o := range fieldOps[field] {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldList() string {
+	names := make([]string, len(Fields))
+	for i, f := range Fields {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+func opList(field Field) string {
+	ops := make([]string, len(fieldOps[field]))
+	for i, op := range fieldOps[field] {
+		ops[i] = string(op)
+	}
+	return strings.Join(ops, " ")
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// normalize checks a value against its field and returns the form
+// Predicate.Values holds.
+func normalize(field Field, value string) (string, error) {
+	switch field {
+	case FieldSource:
+		source, ok := domain.ParseEventSource(value)
+		if !ok {
+			return "", fmt.Errorf("unknown source '%s'", value)
+		}
+		return source.String(), nil
+
+	case FieldStatus:
+		status, ok := domain.ParseEventStatus(value)
+		if !ok {
+			return "", fmt.Errorf("unknown status '%s'", value)
+		}
+		return status.String(), nil
+
+	case FieldHour:
+		hour, err := strconv.Atoi(value)
+		if err != nil || hour < 0 || hour > 23 {
+			return "", fmt.Errorf("invalid hour '%s': use 0 to 23", value)
+		}
+		return strconv.Itoa(hour), nil
+
+	case FieldWeekday:
+		name := strings.ToLower(value)
+		if len(name) >= 3 {
+			if day, ok := weekdays[name[:3]]; ok {
+				return strconv.Itoa(int(day)), nil
+			}
+		}
+		return "", fmt.Errorf("invalid weekday '%s': use mon to sun", value)
+
+	case FieldTag:
+		// Tags are stored lower case, without a leading #
+		value = strings.ToLower(strings.TrimPrefix(value, "#"))
+
+	case FieldDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", fmt.Errorf("invalid date '%s': expected format YYYY-MM-DD", value)
+		}
+		return value, nil
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("empty %s", field)
+	}
+	return value, nil
+}
@@ -0,0 +1,140 @@
+package where
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/domain"
+)
+
+func TestParse(t *testing.T) {
+	e, err := Parse(`repo ~ "acme/*" and branch != main and source in (post-commit, BACKFILL)`)
+	require.NoError(t, err)
+	require.Equal(t, And{
+		Left: And{
+			Left:  Predicate{Field: FieldRepo, Op: OpGlob, Values: []string{"acme/*"}},
+			Right: Predicate{Field: FieldBranch, Op: OpNe, Values: []string{"main"}},
+		},
+		Right: Predicate{Field: FieldSource, Op: OpIn, Values: []string{"POST-COMMIT", "BACKFILL"}},
+	}, e)
+}
+
+func TestParse_Precedence(t *testing.T) {
+	// not binds tighter than and, and tighter than or
+	e, err := Parse("hour < 9 or NOT weekday in (sat, sunday) and tag == #Billable")
+	require.NoError(t, err)
+	require.Equal(t, Or{
+		Left: Predicate{Field: FieldHour, Op: OpLt, Values: []string{"9"}},
+		Right: And{
+			Left:  Not{Predicate{Field: FieldWeekday, Op: OpIn, Values: []string{"6", "0"}}},
+			Right: Predicate{Field: FieldTag, Op: OpEq, Values: []string{"billable"}},
+		},
+	}, e)
+
+	e, err = Parse("(repo = a or repo = b) and commit not in ('abc', def)")
+	require.NoError(t, err)
+	require.IsType(t, And{}, e)
+	require.Equal(t, Predicate{Field: FieldCommit, Op: OpNotIn, Values: []string{"abc", "def"}}, e.(And).Right)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"", "empty expression"},
+		{"author = me", "unknown field 'author'"},
+		{"repo", "expected an operator after 'repo' but found end of input at column 5"},
+		{"repo =", "expected a value after '=' but found end of input"},
+		{"source ~ post*", "'~' does not apply to source"},
+		{"date in (2025-01-01)", "'in' does not apply to date"},
+		{"source = hook", "unknown source 'hook' at column 10"},
+		{"hour >= 24", "invalid hour '24'"},
+		{"weekday = funday", "invalid weekday 'funday'"},
+		{"date > 03/01/2025", "invalid date '03/01/2025'"},
+		{"branch in (a b)", "expected , or ) but found 'b'"},
+		{"branch = 'main", "unterminated string at column 10"},
+		{"branch = main)", "unexpected ')'"},
+		{"(branch = main", "expected ) but found end of input"},
+		{"branch !< main", "unknown operator '!' at column 8"},
+		{"repo not = x", "expected 'in' after 'not'"},
+		{`"repo" = x`, "expected a field but found \"repo\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	ev := Event{
+		RepoID:    "github.com/acme/api",
+		RepoAlias: "api",
+		Branch:    "feature/login",
+		Commit:    "abc1234def",
+		Source:    domain.SourcePostCommit,
+		Status:    domain.StatusPending,
+		Tags:      []string{"billable"},
+		// A Monday evening locally, held in another zone as stored events are
+		Timestamp: time.Date(2025, 3, 10, 21, 30, 0, 0, time.Local).In(time.FixedZone("", -3*60*60)),
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`repo ~ "acme/*"`, true},
+		{`repo ~ "*/api"`, true},
+		{`repo ~ "other/*"`, false},
+		{"repo = api", true},
+		{"repo != api", false},
+		{"repo in (web, github.com/acme/api)", true},
+		{"branch ~ feature/*", true},
+		{"branch ~ login", true},
+		{"branch !~ fix*", true},
+		{"branch = main", false},
+		{"commit = abc1", true},
+		{"commit = abd", false},
+		{"source in (post-commit, backfill)", true},
+		{"source != post-commit", false},
+		{"status = pending", true},
+		{"tag = billable", true},
+		{"tag != wip", true},
+		{"tag ~ bill*", true},
+		{"hour >= 21", true},
+		{"hour < 21", false},
+		{"hour in (9, 21)", true},
+		{"hour not in (9, 21)", false},
+		{"weekday = mon", true},
+		{"weekday in (sat, sun)", false},
+		{"date = 2025-03-10", true},
+		{"date >= 2025-03-11", false},
+		{"date < 2025-03-11", true},
+		{"not branch = main and (hour > 20 or tag = wip)", true},
+		{"branch = main or source = manual", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, Match(e, ev))
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	require.True(t, Glob("*", ""))
+	require.True(t, Glob("main", "main"))
+	require.True(t, Glob("fe?ture/*", "feature/a/b"))
+	require.True(t, Glob("acme/*", "github.com/acme/api"))
+	require.False(t, Glob("acme", "github.com/acme/api"))
+	require.False(t, Glob("cme/*", "github.com/acme/api"))
+	require.True(t, Glob("*cme/*", "github.com/acme/api"))
+	require.True(t, Glob("[wip]", "[wip]"))
+}
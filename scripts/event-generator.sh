@@ -109,7 +109,7 @@ install_hook() {
 
     cat > "$hook_path" << EOF
 #!/bin/sh
-FP_SOURCE='$hook_name' '$FP_BIN' record >/dev/null 2>&1 || true
+FP_SOURCE='$hook_name' '$FP_BIN' record "\$@" >/dev/null 2>&1 || true
 EOF
     chmod +x "$hook_path"
 }